@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/logfiend/internal/attack"
+	"github.com/logfiend/internal/config"
+	"github.com/logfiend/internal/detection"
+	"github.com/logfiend/internal/providers"
+	"github.com/logfiend/internal/schema"
+	"github.com/logfiend/internal/types"
+)
+
+// command is a subcommand invoked as `logfiend <name> [flags] [args]`.
+// Running logfiend without a subcommand performs the inventory.
+type command struct {
+	name        string
+	description string
+	run         func(args []string) error
+}
+
+// errCommandFailed signals that a command already reported its failure
+var errCommandFailed = errors.New("command failed")
+
+func commands() []command {
+	return []command{
+		{"schema", "Print the JSON Schema for inventory output", runSchema},
+		{"validate-inventory", "Validate an inventory file against the JSON Schema", runValidateInventory},
+		{"attack-coverage", "Report MITRE ATT&CK data component coverage for an inventory", runAttackCoverage},
+		{"detections", "Inventory detection rules and check the sources they depend on", runDetections},
+	}
+}
+
+// findCommand returns the subcommand with the given name, if any
+func findCommand(name string) *command {
+	for _, cmd := range commands() {
+		if cmd.name == name {
+			return &cmd
+		}
+	}
+	return nil
+}
+
+// printCommands lists subcommands beneath the default flag usage
+func printCommands() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintln(out, "\nCommands:")
+	for _, cmd := range commands() {
+		fmt.Fprintf(out, "  %-20s %s\n", cmd.name, cmd.description)
+	}
+}
+
+func runSchema(args []string) error {
+	fs := flag.NewFlagSet("schema", flag.ExitOnError)
+	output := fs.String("output", "", "Path to save the schema (default: stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err := os.Stdout.Write(schema.Inventory())
+		return err
+	}
+
+	if err := validateOutputPath(*output); err != nil {
+		return fmt.Errorf("invalid output path: %w", err)
+	}
+	if err := writeOutputSafely(*output, schema.Inventory()); err != nil {
+		return fmt.Errorf("error writing schema: %w", err)
+	}
+	fmt.Printf("✅ Inventory schema saved to %s\n", *output)
+	return nil
+}
+
+func runValidateInventory(args []string) error {
+	fs := flag.NewFlagSet("validate-inventory", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: logfiend validate-inventory <file>")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected exactly one inventory file")
+	}
+
+	path := fs.Arg(0)
+	data, err := readInventoryFile(path)
+	if err != nil {
+		return err
+	}
+
+	violations, err := schema.ValidateInventory(data)
+	if err != nil {
+		return err
+	}
+	for _, violation := range violations {
+		fmt.Printf("  %s\n", violation.Error())
+	}
+	if len(violations) > 0 {
+		fmt.Printf("❌ %s does not match the inventory schema (%d errors)\n", path, len(violations))
+		return errCommandFailed
+	}
+
+	var header struct {
+		Metadata struct {
+			SchemaVersion string `json:"schema_version"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return fmt.Errorf("failed to read inventory metadata: %w", err)
+	}
+	if err := schema.CheckVersion(header.Metadata.SchemaVersion); err != nil {
+		fmt.Printf("❌ %s: %v\n", path, err)
+		return errCommandFailed
+	}
+
+	fmt.Printf("✅ %s is a valid inventory (schema_version %s)\n", path, header.Metadata.SchemaVersion)
+	return nil
+}
+
+// readInventoryFile applies the output path rules to an inventory being read back
+func readInventoryFile(path string) ([]byte, error) {
+	if err := validateOutputPath(path); err != nil {
+		return nil, fmt.Errorf("invalid inventory path: %w", err)
+	}
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("error reading inventory file: %w", err)
+	}
+	return data, nil
+}
+
+// loadInventory reads and decodes an inventory file written by logfiend
+func loadInventory(path string) (types.DataSourceInventory, error) {
+	var inventory types.DataSourceInventory
+	data, err := readInventoryFile(path)
+	if err != nil {
+		return inventory, err
+	}
+	if err := json.Unmarshal(data, &inventory); err != nil {
+		return inventory, fmt.Errorf("failed to decode inventory: %w", err)
+	}
+	return inventory, nil
+}
+
+// writeJSON marshals a report with pretty printing and writes it safely
+func writeJSON(path string, value interface{}) error {
+	if err := validateOutputPath(path); err != nil {
+		return fmt.Errorf("invalid output path: %w", err)
+	}
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling %s: %w", path, err)
+	}
+	if err := writeOutputSafely(path, data); err != nil {
+		return fmt.Errorf("error writing %s: %w", path, err)
+	}
+	return nil
+}
+
+func runAttackCoverage(args []string) error {
+	fs := flag.NewFlagSet("attack-coverage", flag.ExitOnError)
+	inventoryPath := fs.String("inventory", "datasource_inventory.json", "Path to the inventory JSON")
+	rulesPath := fs.String("rules", "", "Path to a YAML rules file overriding the bundled mapping (optional)")
+	output := fs.String("output", "attack_coverage.json", "Path to save the coverage report")
+	navigator := fs.String("navigator", "", "Path to save an ATT&CK Navigator layer (optional)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *rulesPath != "" {
+		if err := validatePath(*rulesPath); err != nil {
+			return fmt.Errorf("invalid rules path: %w", err)
+		}
+	}
+	rules, err := attack.LoadRules(*rulesPath)
+	if err != nil {
+		return err
+	}
+
+	inventory, err := loadInventory(*inventoryPath)
+	if err != nil {
+		return err
+	}
+
+	report := attack.Analyze(inventory, rules)
+	if err := writeJSON(*output, report); err != nil {
+		return err
+	}
+	if *navigator != "" {
+		if err := writeJSON(*navigator, report.Layer()); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("✅ ATT&CK coverage saved to %s: %d covered, %d partial, %d missing of %d data components\n",
+		*output, report.Summary.Covered, report.Summary.Partial, report.Summary.Missing, report.Summary.Total)
+	if *navigator != "" {
+		fmt.Printf("🗺️  Navigator layer saved to %s\n", *navigator)
+	}
+	return nil
+}
+
+// loadConfig loads, validates and sanitizes a config file as the inventory run does
+func loadConfig(path string) (*config.Config, error) {
+	if err := validatePath(path); err != nil {
+		return nil, fmt.Errorf("invalid config path: %w", err)
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := cfg.Sanitize(); err != nil {
+		return nil, fmt.Errorf("failed to sanitize config: %w", err)
+	}
+	return cfg, nil
+}
+
+func runDetections(args []string) error {
+	fs := flag.NewFlagSet("detections", flag.ExitOnError)
+	configPath := fs.String("config", "config.yml", "Path to configuration file")
+	inventoryPath := fs.String("inventory", "datasource_inventory.json", "Path to the inventory JSON the rules are checked against")
+	output := fs.String("output", "detection_dependencies.json", "Path to save the dependency report")
+	silentAfter := fs.Duration("silent-after", 24*time.Hour, "Treat sources with no events for this long as silent (0 disables)")
+	timeout := fs.Duration("timeout", 30*time.Second, "Request timeout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	inventory, err := loadInventory(*inventoryPath)
+	if err != nil {
+		return err
+	}
+
+	provider, err := providers.NewProvider(cfg.Provider)
+	if err != nil {
+		return fmt.Errorf("failed to initialize provider '%s': %w", cfg.Provider.Type, err)
+	}
+	detector, ok := provider.(types.DetectionProvider)
+	if !ok || !provider.GetCapabilities().SupportsDetectionRules {
+		return fmt.Errorf("provider %s does not support detection rules", provider.Name())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	rules, err := detector.FetchDetectionRules(ctx)
+	if err != nil {
+		return fmt.Errorf("error retrieving detection rules from %s: %w", provider.Name(), err)
+	}
+
+	report := detection.Analyze(inventory, rules, detection.Options{SilentAfter: *silentAfter})
+	if err := writeJSON(*output, report); err != nil {
+		return err
+	}
+
+	s := report.Summary
+	fmt.Printf("✅ %d detection rules analyzed (%d enabled), report saved to %s\n", s.Total, s.Enabled, *output)
+	fmt.Printf("   ok: %d, missing sources: %d, silent sources: %d, unanalyzed: %d\n",
+		s.OK, s.MissingSources, s.SilentSources, s.Unanalyzed)
+	for _, rule := range report.Rules {
+		if rule.Enabled && (rule.Status == detection.StatusMissing || rule.Status == detection.StatusSilent) {
+			fmt.Printf("   ⚠️  %s: %s\n", rule.Status, rule.Name)
+		}
+	}
+	return nil
+}
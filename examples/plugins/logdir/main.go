@@ -0,0 +1,141 @@
+// Command logdir is a sample logfiend provider plugin. It inventories the log
+// files matching options.pattern (default "*.log") under options.dir, e.g. a
+// syslog relay's spool directory, reporting their size and last write time.
+//
+// Build it and point a plugin provider at the binary:
+//
+//	go build -o plugins/logdir ./examples/plugins/logdir
+//
+//	provider:
+//	  type: plugin
+//	  options:
+//	    path: "plugins/logdir"
+//	    dir: "/var/log/remote"
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/logfiend/pluginsdk"
+)
+
+type logDirProvider struct {
+	dir        string
+	pattern    string
+	staleAfter time.Duration
+}
+
+func main() {
+	pluginsdk.Serve(newLogDirProvider)
+}
+
+func newLogDirProvider(config pluginsdk.ProviderConfig) (pluginsdk.Provider, error) {
+	p := &logDirProvider{
+		dir:        config.Options["dir"],
+		pattern:    config.Options["pattern"],
+		staleAfter: 24 * time.Hour,
+	}
+	if p.dir == "" {
+		return nil, fmt.Errorf("logdir plugin requires options.dir")
+	}
+	if p.pattern == "" {
+		p.pattern = "*.log"
+	}
+	if _, err := filepath.Match(p.pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid options.pattern: %w", err)
+	}
+	if value := config.Options["stale_after"]; value != "" {
+		staleAfter, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid options.stale_after: %w", err)
+		}
+		p.staleAfter = staleAfter
+	}
+	return p, nil
+}
+
+func (p *logDirProvider) Name() string {
+	return "logdir"
+}
+
+func (p *logDirProvider) FetchDataViews(ctx context.Context) ([]pluginsdk.DataSource, error) {
+	dataSources := []pluginsdk.DataSource{}
+	err := filepath.WalkDir(p.dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if entry.IsDir() || !entry.Type().IsRegular() {
+			return nil
+		}
+		if matched, _ := filepath.Match(p.pattern, entry.Name()); !matched {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(p.dir, path)
+		if err != nil {
+			return err
+		}
+		modified := info.ModTime().UTC()
+		size := info.Size()
+
+		status := "active"
+		if time.Since(modified) > p.staleAfter {
+			status = "degraded"
+		}
+
+		normalized := pluginsdk.NewNormalizedFields()
+		normalized.SizeBytes = &size
+		normalized.LastEventTime = &modified
+
+		dataSources = append(dataSources, pluginsdk.DataSource{
+			ID:         "file:" + filepath.ToSlash(rel),
+			Name:       filepath.ToSlash(rel),
+			Title:      filepath.ToSlash(rel),
+			Type:       "logdir-file",
+			Pattern:    path,
+			Status:     status,
+			Tags:       []string{"logdir"},
+			Normalized: normalized,
+			Metadata: map[string]interface{}{
+				"directory": p.dir,
+				"modified":  modified.Format(time.RFC3339),
+			},
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", p.dir, err)
+	}
+	return dataSources, nil
+}
+
+func (p *logDirProvider) ValidateConnection(ctx context.Context) error {
+	info, err := os.Stat(p.dir)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", p.dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", p.dir)
+	}
+	return nil
+}
+
+func (p *logDirProvider) GetCapabilities() pluginsdk.ProviderCapabilities {
+	return pluginsdk.ProviderCapabilities{
+		SupportsRealTimeQueries: false,
+		SupportsHistoricalData:  false,
+		SupportedDataTypes:      []string{"logdir-file"},
+		RequiresAuthentication:  false,
+	}
+}
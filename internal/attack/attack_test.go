@@ -0,0 +1,129 @@
+package attack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/logfiend/internal/types"
+)
+
+func coverageOf(report Report, key string) ComponentCoverage {
+	for _, component := range report.Components {
+		if component.Key() == key {
+			return component
+		}
+	}
+	return ComponentCoverage{}
+}
+
+func TestAnalyzeCoverageLevels(t *testing.T) {
+	rules, err := DefaultRules()
+	if err != nil {
+		t.Fatalf("bundled rules invalid: %v", err)
+	}
+
+	inventory := types.DataSourceInventory{DataSources: []types.DataSource{
+		{ID: "1", Name: "wineventlog", Type: "splunk-index",
+			Metadata: map[string]interface{}{"sourcetypes": []interface{}{"XmlWinEventLog:Security"}}},
+		{ID: "2", Name: "logs-aws.cloudtrail-*", Pattern: "logs-aws.cloudtrail-*", Type: "data-view", Status: "disabled"},
+	}}
+
+	report := Analyze(inventory, rules)
+
+	cases := map[string]string{
+		"Logon Session: Logon Session Creation":     CoverageFull,
+		"Process: Process Creation":                 CoveragePartial, // only 4688 auditing
+		"Cloud Service: Cloud Service Modification": CoveragePartial, // source disabled
+		"Container: Container Creation":             CoverageMissing,
+	}
+	for key, want := range cases {
+		if got := coverageOf(report, key).Coverage; got != want {
+			t.Errorf("%s: expected %s, got %s", key, want, got)
+		}
+	}
+
+	s := report.Summary
+	if s.Covered+s.Partial+s.Missing != s.Total || s.Total != len(rules.Components) {
+		t.Fatalf("inconsistent summary %+v", s)
+	}
+}
+
+func TestLoadRulesOverride(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yml")
+	override := `
+rules:
+  - name: sysmon
+    coverage: partial
+    components: ["Process: Process Creation"]
+    match:
+      names: ["edr-*"]
+  - name: custom-container-audit
+    coverage: full
+    components: ["Container: Container Creation"]
+    match:
+      types: ["splunk-index"]
+      names: ["k8s"]
+`
+	if err := os.WriteFile(path, []byte(override), 0600); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	rules, err := LoadRules(path)
+	if err != nil {
+		t.Fatalf("LoadRules error: %v", err)
+	}
+	bundled, _ := DefaultRules()
+	if len(rules.Rules) != len(bundled.Rules)+1 {
+		t.Fatalf("expected one added rule, got %d rules", len(rules.Rules))
+	}
+
+	inventory := types.DataSourceInventory{DataSources: []types.DataSource{
+		{ID: "k8s", Name: "k8s", Type: "splunk-index"},
+		{ID: "edr", Name: "edr-main", Type: "splunk-index"},
+	}}
+	report := Analyze(inventory, rules)
+	if got := coverageOf(report, "Container: Container Creation").Coverage; got != CoverageFull {
+		t.Fatalf("expected custom rule to cover containers, got %s", got)
+	}
+	if got := coverageOf(report, "Process: Process Creation").Coverage; got != CoveragePartial {
+		t.Fatalf("expected overridden sysmon rule to give partial, got %s", got)
+	}
+}
+
+func TestLoadRulesRejectsUnknownComponent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yml")
+	bad := `
+rules:
+  - name: typo
+    coverage: full
+    components: ["Process: Proces Creation"]
+    match:
+      names: ["x"]
+`
+	if err := os.WriteFile(path, []byte(bad), 0600); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	if _, err := LoadRules(path); err == nil {
+		t.Fatal("expected error for unknown component")
+	}
+}
+
+func TestLayerScoresTechniques(t *testing.T) {
+	report := Report{Provider: "splunk", Components: []ComponentCoverage{
+		{Component: Component{DataSource: "A", Component: "One", Techniques: []string{"T1000"}}, Coverage: CoveragePartial},
+		{Component: Component{DataSource: "A", Component: "Two", Techniques: []string{"T1000", "T2000"}}, Coverage: CoverageFull},
+		{Component: Component{DataSource: "B", Component: "Three", Techniques: []string{"T3000"}}, Coverage: CoverageMissing},
+	}}
+
+	layer := report.Layer()
+	want := map[string]int{"T1000": 2, "T2000": 2, "T3000": 0}
+	if len(layer.Techniques) != len(want) {
+		t.Fatalf("expected %d techniques, got %v", len(want), layer.Techniques)
+	}
+	for _, technique := range layer.Techniques {
+		if technique.Score != want[technique.TechniqueID] {
+			t.Errorf("%s: expected score %d, got %d", technique.TechniqueID, want[technique.TechniqueID], technique.Score)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package attack
+
+import (
+	"sort"
+	"time"
+
+	"github.com/logfiend/internal/match"
+	"github.com/logfiend/internal/types"
+)
+
+// Report summarizes ATT&CK data component coverage for an inventory
+type Report struct {
+	GeneratedAt time.Time           `json:"generated_at"`
+	Provider    string              `json:"provider"`
+	Summary     Summary             `json:"summary"`
+	Components  []ComponentCoverage `json:"components"`
+}
+
+// Summary counts components by coverage level
+type Summary struct {
+	Total   int `json:"total"`
+	Covered int `json:"covered"`
+	Partial int `json:"partial"`
+	Missing int `json:"missing"`
+}
+
+// ComponentCoverage is the coverage of one data component
+type ComponentCoverage struct {
+	Component
+	Coverage string   `json:"coverage"`
+	Sources  []string `json:"sources,omitempty"`
+	Rules    []string `json:"rules,omitempty"`
+}
+
+// Analyze matches the inventory's data sources against the rules. A
+// component is fully covered when a "full" rule matches an active source; it
+// is partially covered when only "partial" rules match, or when the matching
+// sources are all disabled or offline.
+func Analyze(inventory types.DataSourceInventory, rules *RuleSet) Report {
+	coverage := make(map[string]*ComponentCoverage, len(rules.Components))
+	for _, component := range rules.Components {
+		coverage[component.Key()] = &ComponentCoverage{Component: component, Coverage: CoverageMissing}
+	}
+
+	for _, ds := range inventory.DataSources {
+		attrs := match.Extract(ds)
+		for _, rule := range rules.Rules {
+			if !rule.Match.MatchesAttributes(attrs) {
+				continue
+			}
+
+			level := rule.Coverage
+			if !match.Active(ds) {
+				level = CoveragePartial
+			}
+			for _, key := range rule.Components {
+				if entry, ok := coverage[key]; ok {
+					entry.record(level, sourceLabel(ds), rule.Name)
+				}
+			}
+		}
+	}
+
+	report := Report{
+		GeneratedAt: time.Now(),
+		Provider:    inventory.Metadata.Provider,
+		Components:  make([]ComponentCoverage, 0, len(coverage)),
+	}
+	for _, component := range rules.Components {
+		entry := coverage[component.Key()]
+		sort.Strings(entry.Sources)
+		sort.Strings(entry.Rules)
+		report.Components = append(report.Components, *entry)
+
+		report.Summary.Total++
+		switch entry.Coverage {
+		case CoverageFull:
+			report.Summary.Covered++
+		case CoveragePartial:
+			report.Summary.Partial++
+		default:
+			report.Summary.Missing++
+		}
+	}
+
+	return report
+}
+
+func (c *ComponentCoverage) record(level, source, rule string) {
+	if rank(level) > rank(c.Coverage) {
+		c.Coverage = level
+	}
+	c.Sources = appendUnique(c.Sources, source)
+	c.Rules = appendUnique(c.Rules, rule)
+}
+
+func rank(level string) int {
+	switch level {
+	case CoverageFull:
+		return 2
+	case CoveragePartial:
+		return 1
+	}
+	return 0
+}
+
+// sourceLabel identifies a data source in reports
+func sourceLabel(ds types.DataSource) string {
+	if ds.Name != "" {
+		return ds.Type + "/" + ds.Name
+	}
+	return ds.Type + "/" + ds.ID
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,97 @@
+package attack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NavigatorLayer is an ATT&CK Navigator layer (format 4.5)
+type NavigatorLayer struct {
+	Name        string                `json:"name"`
+	Versions    NavigatorVersions     `json:"versions"`
+	Domain      string                `json:"domain"`
+	Description string                `json:"description"`
+	Techniques  []NavigatorTechnique  `json:"techniques"`
+	Gradient    NavigatorGradient     `json:"gradient"`
+	LegendItems []NavigatorLegendItem `json:"legendItems"`
+}
+
+// NavigatorVersions pins the ATT&CK, Navigator and layer format versions
+type NavigatorVersions struct {
+	Attack    string `json:"attack"`
+	Navigator string `json:"navigator"`
+	Layer     string `json:"layer"`
+}
+
+// NavigatorTechnique scores one technique
+type NavigatorTechnique struct {
+	TechniqueID string `json:"techniqueID"`
+	Score       int    `json:"score"`
+	Comment     string `json:"comment,omitempty"`
+}
+
+// NavigatorGradient maps scores to colors
+type NavigatorGradient struct {
+	Colors   []string `json:"colors"`
+	MinValue int      `json:"minValue"`
+	MaxValue int      `json:"maxValue"`
+}
+
+// NavigatorLegendItem labels a color in the legend
+type NavigatorLegendItem struct {
+	Label string `json:"label"`
+	Color string `json:"color"`
+}
+
+// Layer converts a coverage report into a Navigator layer. Each technique is
+// scored by the best coverage of the data components that detect it:
+// 0 missing, 1 partial, 2 covered.
+func (r Report) Layer() NavigatorLayer {
+	scores := make(map[string]int)
+	reasons := make(map[string][]string)
+	for _, component := range r.Components {
+		score := rank(component.Coverage)
+		for _, technique := range component.Techniques {
+			if current, seen := scores[technique]; !seen || score > current {
+				scores[technique] = score
+			}
+			if score > 0 {
+				reasons[technique] = append(reasons[technique], fmt.Sprintf("%s (%s)", component.Key(), component.Coverage))
+			}
+		}
+	}
+
+	techniqueIDs := make([]string, 0, len(scores))
+	for id := range scores {
+		techniqueIDs = append(techniqueIDs, id)
+	}
+	sort.Strings(techniqueIDs)
+
+	techniques := make([]NavigatorTechnique, 0, len(techniqueIDs))
+	for _, id := range techniqueIDs {
+		techniques = append(techniques, NavigatorTechnique{
+			TechniqueID: id,
+			Score:       scores[id],
+			Comment:     strings.Join(reasons[id], "; "),
+		})
+	}
+
+	return NavigatorLayer{
+		Name:        fmt.Sprintf("logfiend data source coverage (%s)", r.Provider),
+		Versions:    NavigatorVersions{Attack: "14", Navigator: "4.9.1", Layer: "4.5"},
+		Domain:      "enterprise-attack",
+		Description: fmt.Sprintf("Data component coverage generated by logfiend at %s", r.GeneratedAt.Format("2006-01-02T15:04:05Z07:00")),
+		Techniques:  techniques,
+		Gradient: NavigatorGradient{
+			Colors:   []string{"#ff6666", "#ffe766", "#8ec843"},
+			MinValue: 0,
+			MaxValue: 2,
+		},
+		LegendItems: []NavigatorLegendItem{
+			{Label: "No data source coverage", Color: "#ff6666"},
+			{Label: "Partial coverage", Color: "#ffe766"},
+			{Label: "Covered", Color: "#8ec843"},
+		},
+	}
+}
@@ -0,0 +1,161 @@
+// Package attack maps inventoried data sources to MITRE ATT&CK data
+// components and reports which components the SIEM estate covers.
+package attack
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/logfiend/internal/match"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed rules.yml
+var bundledRules []byte
+
+// Coverage levels, ordered from weakest to strongest
+const (
+	CoverageMissing = "missing"
+	CoveragePartial = "partial"
+	CoverageFull    = "full"
+)
+
+// RuleSet is the mapping file format
+type RuleSet struct {
+	Version    int         `yaml:"version"`
+	Replace    bool        `yaml:"replace,omitempty"`
+	Components []Component `yaml:"components"`
+	Rules      []Rule      `yaml:"rules"`
+}
+
+// Component is an ATT&CK data component and the techniques it helps detect
+type Component struct {
+	DataSource string   `yaml:"data_source" json:"data_source"`
+	Component  string   `yaml:"component" json:"component"`
+	Techniques []string `yaml:"techniques,omitempty" json:"techniques,omitempty"`
+}
+
+// Key returns the "<Data Source>: <Data Component>" form used by ATT&CK
+func (c Component) Key() string {
+	return c.DataSource + ": " + c.Component
+}
+
+// Rule maps matching data sources to the data components they provide
+type Rule struct {
+	Name       string         `yaml:"name"`
+	Coverage   string         `yaml:"coverage"`
+	Components []string       `yaml:"components"`
+	Match      match.Criteria `yaml:"match"`
+}
+
+// DefaultRules returns the bundled rule set
+func DefaultRules() (*RuleSet, error) {
+	rules, err := parseRules(bundledRules, "bundled rules")
+	if err != nil {
+		return nil, err
+	}
+	if err := rules.validate(); err != nil {
+		return nil, fmt.Errorf("invalid bundled rules: %w", err)
+	}
+	return rules, nil
+}
+
+// LoadRules returns the bundled rules overridden by the file at path. Entries
+// in the file replace bundled entries of the same name unless the file sets
+// `replace: true`, in which case the bundled rules are ignored.
+func LoadRules(path string) (*RuleSet, error) {
+	rules, err := DefaultRules()
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return rules, nil
+	}
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("error reading rules file '%s': %w", path, err)
+	}
+	override, err := parseRules(data, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if override.Replace {
+		rules = override
+	} else {
+		rules.merge(override)
+	}
+	if err := rules.validate(); err != nil {
+		return nil, fmt.Errorf("invalid rules file '%s': %w", path, err)
+	}
+	return rules, nil
+}
+
+func parseRules(data []byte, source string) (*RuleSet, error) {
+	var rules RuleSet
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", source, err)
+	}
+	return &rules, nil
+}
+
+// merge applies override entries on top of the receiver, keyed by name
+func (r *RuleSet) merge(override *RuleSet) {
+	for _, component := range override.Components {
+		replaced := false
+		for i := range r.Components {
+			if r.Components[i].Key() == component.Key() {
+				r.Components[i] = component
+				replaced = true
+			}
+		}
+		if !replaced {
+			r.Components = append(r.Components, component)
+		}
+	}
+
+	for _, rule := range override.Rules {
+		replaced := false
+		for i := range r.Rules {
+			if r.Rules[i].Name == rule.Name {
+				r.Rules[i] = rule
+				replaced = true
+			}
+		}
+		if !replaced {
+			r.Rules = append(r.Rules, rule)
+		}
+	}
+}
+
+// validate checks rules reference known components and valid coverage levels
+func (r *RuleSet) validate() error {
+	known := make(map[string]bool, len(r.Components))
+	for _, component := range r.Components {
+		if component.DataSource == "" || component.Component == "" {
+			return fmt.Errorf("component entries require data_source and component")
+		}
+		known[component.Key()] = true
+	}
+
+	for _, rule := range r.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("rule entries require a name")
+		}
+		if rule.Coverage != CoverageFull && rule.Coverage != CoveragePartial {
+			return fmt.Errorf("rule %q: coverage must be %q or %q", rule.Name, CoverageFull, CoveragePartial)
+		}
+		if rule.Match.IsEmpty() {
+			return fmt.Errorf("rule %q: match criteria are required", rule.Name)
+		}
+		for _, key := range rule.Components {
+			if !known[key] {
+				return fmt.Errorf("rule %q: unknown component %q", rule.Name, key)
+			}
+		}
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ type Config struct {
 	Provider types.ProviderConfig `yaml:"provider"`
 	Output   OutputConfig         `yaml:"output,omitempty"`
 	Logging  LoggingConfig        `yaml:"logging,omitempty"`
+	OCSF     OCSFConfig           `yaml:"ocsf,omitempty"`
 }
 
 // OutputConfig configures output settings
@@ -32,6 +33,12 @@ type LoggingConfig struct {
 	Format string `yaml:"format,omitempty"` // text, json
 }
 
+// OCSFConfig configures OCSF classification of inventoried sources
+type OCSFConfig struct {
+	Disabled     bool   `yaml:"disabled,omitempty"`      // skip classification
+	MappingsFile string `yaml:"mappings_file,omitempty"` // extends the built-in catalog
+}
+
 // Load reads and parses the configuration file
 func Load(path string) (*Config, error) {
 	// Validate and sanitize path
@@ -76,7 +83,10 @@ func (c *Config) Validate() error {
 	if c.Provider.Type == "" {
 		return fmt.Errorf("provider type is required")
 	}
-	if c.Provider.Endpoint == "" {
+	if c.Provider.Endpoint == "" && requiresEndpoint(c.Provider) {
+		if option, ok := endpointOptions[strings.ToLower(strings.TrimSpace(c.Provider.Type))]; ok {
+			return fmt.Errorf("provider endpoint or options.%s is required", option)
+		}
 		return fmt.Errorf("provider endpoint is required")
 	}
 
@@ -90,6 +100,31 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// endpointOptional lists providers that may run without an endpoint: file
+// reads local files, plugins decide for themselves and the AWS providers
+// derive regional endpoints
+var endpointOptional = map[string]bool{
+	"file":       true,
+	"plugin":     true,
+	"cloudwatch":   true,
+	"securitylake": true,
+}
+
+// endpointOptions names the option that selects a regional API in place of
+// the endpoint; the provider rejects configs that set both
+var endpointOptions = map[string]string{
+	"insightidr": "region",
+	"sumologic":  "deployment",
+}
+
+func requiresEndpoint(provider types.ProviderConfig) bool {
+	providerType := strings.ToLower(strings.TrimSpace(provider.Type))
+	if option, ok := endpointOptions[providerType]; ok && strings.TrimSpace(provider.Options[option]) != "" {
+		return false
+	}
+	return !endpointOptional[providerType]
+}
+
 func (c *Config) validateAuth() error {
 	auth := c.Provider.Auth
 	switch auth.Type {
@@ -105,6 +140,26 @@ func (c *Config) validateAuth() error {
 		if auth.APIKey == "" {
 			return fmt.Errorf("api_key auth requires api_key")
 		}
+	case "aws_sigv4":
+		// Without static keys, providers fall back to the environment and the
+		// shared credentials file
+		if (auth.AccessKeyID == "") != (auth.SecretAccessKey == "") {
+			return fmt.Errorf("aws_sigv4 auth requires both access_key_id and secret_access_key, or neither")
+		}
+		if auth.Region == "" {
+			return fmt.Errorf("aws_sigv4 auth requires region")
+		}
+	case "service_account":
+		if auth.CredentialsFile == "" {
+			return fmt.Errorf("service_account auth requires credentials_file")
+		}
+		if filepath.IsAbs(auth.CredentialsFile) {
+			return fmt.Errorf("credentials_file must be a relative path")
+		}
+	case "client_credentials":
+		if auth.ClientID == "" || auth.ClientSecret == "" {
+			return fmt.Errorf("client_credentials auth requires client_id and client_secret")
+		}
 	default:
 		return fmt.Errorf("unsupported auth type: %s", auth.Type)
 	}
@@ -137,6 +192,20 @@ func (c *Config) Sanitize() error {
 		if c.Provider.Auth.Type == "api_key" && c.Provider.Auth.APIKey == "" {
 			return fmt.Errorf("api_key auth requires non-empty api_key")
 		}
+		if c.Provider.Auth.Type == "aws_sigv4" {
+			c.Provider.Auth.AccessKeyID = strings.TrimSpace(c.Provider.Auth.AccessKeyID)
+			c.Provider.Auth.Region = strings.TrimSpace(c.Provider.Auth.Region)
+			if (c.Provider.Auth.AccessKeyID == "") != (c.Provider.Auth.SecretAccessKey == "") {
+				return fmt.Errorf("aws_sigv4 auth requires both access_key_id and secret_access_key, or neither")
+			}
+		}
+		if c.Provider.Auth.Type == "client_credentials" {
+			c.Provider.Auth.ClientID = strings.TrimSpace(c.Provider.Auth.ClientID)
+			c.Provider.Auth.TokenURL = strings.TrimSpace(c.Provider.Auth.TokenURL)
+			if c.Provider.Auth.ClientID == "" || c.Provider.Auth.ClientSecret == "" {
+				return fmt.Errorf("client_credentials auth requires non-empty client_id and client_secret")
+			}
+		}
 	}
 
 	return nil
@@ -144,6 +213,10 @@ func (c *Config) Sanitize() error {
 
 func (c *Config) sanitizeEndpoint() error {
 	endpoint := strings.TrimSpace(c.Provider.Endpoint)
+	if endpoint == "" && !requiresEndpoint(c.Provider) {
+		c.Provider.Endpoint = endpoint
+		return nil
+	}
 	if endpoint == "" {
 		return fmt.Errorf("endpoint cannot be empty")
 	}
@@ -0,0 +1,221 @@
+package detection
+
+import (
+	"strings"
+	"time"
+
+	"github.com/logfiend/internal/match"
+	"github.com/logfiend/internal/types"
+)
+
+// Dependency statuses for references and rules
+const (
+	StatusOK         = "ok"
+	StatusSilent     = "silent"
+	StatusMissing    = "missing"
+	StatusUnresolved = "unresolved" // reference cannot be mapped to inventory sources
+	StatusUnanalyzed = "unanalyzed" // rule has no resolvable references
+)
+
+// Options tunes the dependency analysis
+type Options struct {
+	// SilentAfter marks sources whose last event is older than this as silent.
+	// Zero disables the age check.
+	SilentAfter time.Duration
+	// Now is the reference time for the age check; defaults to time.Now
+	Now time.Time
+}
+
+// Report is the result of analyzing rules against an inventory
+type Report struct {
+	GeneratedAt time.Time    `json:"generated_at"`
+	Provider    string       `json:"provider"`
+	Summary     Summary      `json:"summary"`
+	Rules       []RuleResult `json:"rules"`
+}
+
+// Summary counts rules by dependency status. Issue counts only include
+// enabled rules, since disabled rules cannot fail silently.
+type Summary struct {
+	Total          int `json:"total"`
+	Enabled        int `json:"enabled"`
+	OK             int `json:"ok"`
+	MissingSources int `json:"missing_sources"`
+	SilentSources  int `json:"silent_sources"`
+	Unanalyzed     int `json:"unanalyzed"`
+}
+
+// RuleResult is the dependency status of one rule
+type RuleResult struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Type       string            `json:"type"`
+	Enabled    bool              `json:"enabled"`
+	Status     string            `json:"status"`
+	References []ReferenceResult `json:"references,omitempty"`
+}
+
+// ReferenceResult is the resolution of one rule reference
+type ReferenceResult struct {
+	Reference
+	Status  string   `json:"status"`
+	Sources []string `json:"sources,omitempty"`
+}
+
+// Analyze resolves each rule's references against the inventory
+func Analyze(inventory types.DataSourceInventory, rules []types.DetectionRule, opts Options) Report {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	candidates := make([]candidate, 0, len(inventory.DataSources))
+	for _, ds := range inventory.DataSources {
+		candidates = append(candidates, candidate{ds: ds, attrs: match.Extract(ds)})
+	}
+
+	report := Report{
+		GeneratedAt: opts.Now,
+		Provider:    inventory.Metadata.Provider,
+		Rules:       make([]RuleResult, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		result := analyzeRule(rule, candidates, opts)
+		report.Rules = append(report.Rules, result)
+		report.Summary.add(result)
+	}
+
+	return report
+}
+
+type candidate struct {
+	ds    types.DataSource
+	attrs match.Attributes
+}
+
+func analyzeRule(rule types.DetectionRule, candidates []candidate, opts Options) RuleResult {
+	result := RuleResult{
+		ID:      rule.ID,
+		Name:    rule.Name,
+		Type:    rule.Type,
+		Enabled: rule.Enabled,
+		Status:  StatusUnanalyzed,
+	}
+
+	for _, ref := range ExtractReferences(rule) {
+		resolved := resolve(ref, candidates, opts)
+		result.References = append(result.References, resolved)
+
+		switch {
+		case resolved.Status == StatusMissing:
+			result.Status = StatusMissing
+		case resolved.Status == StatusSilent && result.Status != StatusMissing:
+			result.Status = StatusSilent
+		case resolved.Status == StatusOK && result.Status == StatusUnanalyzed:
+			result.Status = StatusOK
+		}
+	}
+
+	return result
+}
+
+// resolve finds the inventory sources behind a reference. A reference is ok
+// if at least one matching source is active and not stale.
+func resolve(ref Reference, candidates []candidate, opts Options) ReferenceResult {
+	result := ReferenceResult{Reference: ref, Status: StatusMissing}
+	if ref.Kind == KindDatamodel {
+		result.Status = StatusUnresolved
+		return result
+	}
+	if ref.Kind == KindIndex && strings.HasPrefix(ref.Value, "-") {
+		// Elastic exclusion patterns narrow other references
+		result.Status = StatusUnresolved
+		return result
+	}
+
+	for _, c := range candidates {
+		if !referenceMatches(ref, c.attrs) {
+			continue
+		}
+		result.Sources = append(result.Sources, c.ds.Type+"/"+c.ds.Name)
+		if live(c.ds, opts) {
+			result.Status = StatusOK
+		} else if result.Status == StatusMissing {
+			result.Status = StatusSilent
+		}
+	}
+
+	return result
+}
+
+func referenceMatches(ref Reference, attrs match.Attributes) bool {
+	switch ref.Kind {
+	case KindIndex:
+		pattern := stripCluster(ref.Value)
+		for _, name := range attrs.Names {
+			for _, part := range strings.Split(name, ",") {
+				part = stripCluster(strings.TrimSpace(part))
+				if match.Glob(pattern, part) || match.Glob(part, pattern) {
+					return true
+				}
+			}
+		}
+	case KindSourcetype:
+		return anyGlob(ref.Value, attrs.Sourcetypes)
+	case KindTable:
+		return anyGlob(ref.Value, attrs.Tables)
+	}
+	return false
+}
+
+// stripCluster removes an Elastic cross-cluster prefix such as "remote:logs-*"
+func stripCluster(pattern string) string {
+	if i := strings.LastIndex(pattern, ":"); i >= 0 {
+		return pattern[i+1:]
+	}
+	return pattern
+}
+
+func anyGlob(pattern string, values []string) bool {
+	for _, value := range values {
+		if match.Glob(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// live reports whether a source is collecting and has recent data
+func live(ds types.DataSource, opts Options) bool {
+	if !match.Active(ds) {
+		return false
+	}
+	n := ds.Normalized
+	if n == nil {
+		return true
+	}
+	if n.EventCount != nil && *n.EventCount == 0 {
+		return false
+	}
+	if opts.SilentAfter > 0 && n.LastEventTime != nil && opts.Now.Sub(*n.LastEventTime) > opts.SilentAfter {
+		return false
+	}
+	return true
+}
+
+func (s *Summary) add(result RuleResult) {
+	s.Total++
+	if !result.Enabled {
+		return
+	}
+	s.Enabled++
+	switch result.Status {
+	case StatusOK:
+		s.OK++
+	case StatusMissing:
+		s.MissingSources++
+	case StatusSilent:
+		s.SilentSources++
+	default:
+		s.Unanalyzed++
+	}
+}
@@ -0,0 +1,97 @@
+package detection
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+func TestExtractReferences(t *testing.T) {
+	cases := []struct {
+		name string
+		rule types.DetectionRule
+		want []Reference
+	}{
+		{
+			name: "spl",
+			rule: types.DetectionRule{Language: "spl", Query: `index=wineventlog sourcetype="XmlWinEventLog:Security" EventCode=4625 OR index IN (os, "linux") | stats count by user`},
+			want: []Reference{
+				{KindIndex, "linux"}, {KindIndex, "os"}, {KindIndex, "wineventlog"},
+				{KindSourcetype, "XmlWinEventLog:Security"},
+			},
+		},
+		{
+			name: "spl datamodel",
+			rule: types.DetectionRule{Language: "spl", Query: `| tstats count from datamodel=Endpoint.Processes where Processes.process_name=cmd.exe`},
+			want: []Reference{{KindDatamodel, "Endpoint"}},
+		},
+		{
+			name: "kql",
+			rule: types.DetectionRule{Language: "kql", Query: `// failed sign-ins
+let threshold = 5;
+let ips = SigninLogs | where ResultType != 0 | summarize by IPAddress;
+union isfuzzy=true SecurityEvent, AADNonInteractiveUserSignInLogs
+| join kind=inner (DeviceInfo | project DeviceName) on DeviceName
+| where IPAddress in (ips)`},
+			want: []Reference{
+				{KindTable, "AADNonInteractiveUserSignInLogs"}, {KindTable, "DeviceInfo"},
+				{KindTable, "SecurityEvent"}, {KindTable, "SigninLogs"},
+			},
+		},
+		{
+			name: "elastic index and esql",
+			rule: types.DetectionRule{Language: "esql", Indices: []string{"winlogbeat-*"}, Query: "FROM logs-endpoint.events.process-*, logs-windows.* METADATA _id | WHERE process.name == \"cmd.exe\""},
+			want: []Reference{
+				{KindIndex, "logs-endpoint.events.process-*"}, {KindIndex, "logs-windows.*"}, {KindIndex, "winlogbeat-*"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := ExtractReferences(c.rule)
+			if !reflect.DeepEqual(got, c.want) {
+				t.Fatalf("expected %v, got %v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestAnalyzeFlagsMissingAndSilentSources(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	stale := now.Add(-72 * time.Hour)
+	recent := now.Add(-time.Hour)
+
+	inventory := types.DataSourceInventory{DataSources: []types.DataSource{
+		{Name: "wineventlog", Type: "splunk-index", Normalized: &types.NormalizedFields{LastEventTime: &recent}},
+		{Name: "firewall", Type: "splunk-index", Normalized: &types.NormalizedFields{LastEventTime: &stale}},
+		{Name: "SigninLogs", Type: "log-analytics-table"},
+	}}
+	rules := []types.DetectionRule{
+		{ID: "1", Name: "ok", Enabled: true, Language: "spl", Query: "index=wineventlog EventCode=4625"},
+		{ID: "2", Name: "silent", Enabled: true, Language: "spl", Query: "index=firewall action=blocked"},
+		{ID: "3", Name: "missing", Enabled: true, Language: "spl", Query: "index=wineventlog OR index=proxy"},
+		{ID: "4", Name: "table", Enabled: true, Language: "kql", Query: "SigninLogs | where ResultType != 0"},
+		{ID: "5", Name: "qradar", Enabled: true, Type: "qradar-rule"},
+		{ID: "6", Name: "disabled", Enabled: false, Language: "spl", Query: "index=gone"},
+	}
+
+	report := Analyze(inventory, rules, Options{SilentAfter: 24 * time.Hour, Now: now})
+
+	want := map[string]string{
+		"ok": StatusOK, "silent": StatusSilent, "missing": StatusMissing,
+		"table": StatusOK, "qradar": StatusUnanalyzed, "disabled": StatusMissing,
+	}
+	for _, rule := range report.Rules {
+		if rule.Status != want[rule.Name] {
+			t.Errorf("%s: expected %s, got %s", rule.Name, want[rule.Name], rule.Status)
+		}
+	}
+
+	expected := Summary{Total: 6, Enabled: 5, OK: 2, MissingSources: 1, SilentSources: 1, Unanalyzed: 1}
+	if report.Summary != expected {
+		t.Fatalf("expected summary %+v, got %+v", expected, report.Summary)
+	}
+}
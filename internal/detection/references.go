@@ -0,0 +1,189 @@
+// Package detection works out which inventoried data sources each detection
+// rule depends on and reports rules whose sources are missing or silent.
+package detection
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/logfiend/internal/types"
+)
+
+// Reference kinds extracted from rule queries
+const (
+	KindIndex      = "index"
+	KindSourcetype = "sourcetype"
+	KindTable      = "table"
+	KindDatamodel  = "datamodel"
+)
+
+// Reference is a data dependency named by a detection rule
+type Reference struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+// ExtractReferences parses the index, sourcetype, table and data model
+// references out of a rule's declared indices and query
+func ExtractReferences(rule types.DetectionRule) []Reference {
+	refs := make([]Reference, 0, len(rule.Indices))
+	for _, index := range rule.Indices {
+		refs = append(refs, Reference{Kind: KindIndex, Value: index})
+	}
+
+	switch strings.ToLower(rule.Language) {
+	case "spl":
+		refs = append(refs, parseSPL(rule.Query)...)
+	case "kql":
+		refs = append(refs, parseKQL(rule.Query)...)
+	case "esql":
+		refs = append(refs, parseESQL(rule.Query)...)
+	}
+
+	return dedupe(refs)
+}
+
+var (
+	splFieldPattern     = regexp.MustCompile(`(?i)\b(index|sourcetype)\s*(?:=|::)\s*("[^"]*"|[^\s|()\[\]]+)`)
+	splInPattern        = regexp.MustCompile(`(?i)\b(index|sourcetype)\s+in\s*\(([^)]*)\)`)
+	splDatamodelPattern = regexp.MustCompile(`(?i)(?:\bdatamodel\s*=\s*|\|\s*datamodel\s+)"?([A-Za-z0-9_.]+)`)
+)
+
+// parseSPL extracts index=, sourcetype=, IN (...) and data model references.
+// Macros are not expanded, so searches that hide their indexes behind macros
+// yield no index references.
+func parseSPL(query string) []Reference {
+	var refs []Reference
+	for _, m := range splFieldPattern.FindAllStringSubmatch(query, -1) {
+		refs = append(refs, Reference{Kind: strings.ToLower(m[1]), Value: strings.Trim(m[2], `"`)})
+	}
+	for _, m := range splInPattern.FindAllStringSubmatch(query, -1) {
+		for _, value := range strings.Split(m[2], ",") {
+			if value = strings.Trim(strings.TrimSpace(value), `"`); value != "" {
+				refs = append(refs, Reference{Kind: strings.ToLower(m[1]), Value: value})
+			}
+		}
+	}
+	for _, m := range splDatamodelPattern.FindAllStringSubmatch(query, -1) {
+		// Data model references look like Endpoint.Processes; the model is the first part
+		model, _, _ := strings.Cut(m[1], ".")
+		refs = append(refs, Reference{Kind: KindDatamodel, Value: model})
+	}
+	return refs
+}
+
+var (
+	kqlCommentPattern  = regexp.MustCompile(`//[^\n]*`)
+	kqlLetPattern      = regexp.MustCompile(`(?i)^let\s+([A-Za-z_][A-Za-z0-9_]*)\s*=`)
+	kqlUnionPattern    = regexp.MustCompile(`(?i)\bunion\b((?:\s+(?:kind|withsource|isfuzzy)\s*=\s*\S+)*)\s+([^|]+)`)
+	kqlSubqueryPattern = regexp.MustCompile(`(?i)\b(?:join|lookup)\b[^(|]*\(\s*([A-Za-z_][A-Za-z0-9_]*)`)
+	kqlIdentPattern    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// kqlNonTables are tabular operators and functions that can start a statement
+var kqlNonTables = map[string]bool{
+	"let": true, "union": true, "print": true, "range": true, "datatable": true,
+	"externaldata": true, "materialize": true, "search": true, "find": true,
+	"set": true, "declare": true, "view": true, "evaluate": true,
+}
+
+// parseKQL extracts table references: the table each statement starts with,
+// union members and join/lookup subqueries. Names bound with let are skipped.
+func parseKQL(query string) []Reference {
+	query = kqlCommentPattern.ReplaceAllString(query, "")
+	statements := strings.Split(query, ";")
+
+	letNames := make(map[string]bool)
+	var candidates []string
+	for _, statement := range statements {
+		statement = strings.TrimSpace(statement)
+		if statement == "" {
+			continue
+		}
+		if m := kqlLetPattern.FindStringSubmatch(statement); m != nil {
+			letNames[strings.ToLower(m[1])] = true
+			statement = strings.TrimSpace(statement[len(m[0]):])
+			// let x = view() { ... } and let x = (T | ...) still reference tables
+			statement = strings.TrimLeft(statement, "( ")
+		}
+
+		if first := leadingIdentifier(statement); first != "" {
+			candidates = append(candidates, first)
+		}
+		for _, m := range kqlUnionPattern.FindAllStringSubmatch(statement, -1) {
+			for _, member := range strings.Split(m[2], ",") {
+				member = strings.Trim(strings.TrimSpace(member), "()")
+				if kqlIdentPattern.MatchString(member) {
+					candidates = append(candidates, member)
+				}
+			}
+		}
+		for _, m := range kqlSubqueryPattern.FindAllStringSubmatch(statement, -1) {
+			candidates = append(candidates, m[1])
+		}
+	}
+
+	var refs []Reference
+	for _, candidate := range candidates {
+		lower := strings.ToLower(candidate)
+		if letNames[lower] || kqlNonTables[lower] {
+			continue
+		}
+		refs = append(refs, Reference{Kind: KindTable, Value: candidate})
+	}
+	return refs
+}
+
+func leadingIdentifier(statement string) string {
+	end := strings.IndexAny(statement, " \t\r\n|(")
+	if end < 0 {
+		end = len(statement)
+	}
+	first := statement[:end]
+	if !kqlIdentPattern.MatchString(first) {
+		return ""
+	}
+	return first
+}
+
+var esqlFromPattern = regexp.MustCompile(`(?i)^\s*from\s+([^|\n]+)`)
+
+// parseESQL extracts the index patterns of an ES|QL FROM command
+func parseESQL(query string) []Reference {
+	m := esqlFromPattern.FindStringSubmatch(query)
+	if m == nil {
+		return nil
+	}
+	source := m[1]
+	if i := strings.Index(strings.ToLower(source), " metadata "); i >= 0 {
+		source = source[:i]
+	}
+
+	var refs []Reference
+	for _, index := range strings.Split(source, ",") {
+		if index = strings.Trim(strings.TrimSpace(index), `"`); index != "" {
+			refs = append(refs, Reference{Kind: KindIndex, Value: index})
+		}
+	}
+	return refs
+}
+
+func dedupe(refs []Reference) []Reference {
+	seen := make(map[Reference]bool, len(refs))
+	result := make([]Reference, 0, len(refs))
+	for _, ref := range refs {
+		if ref.Value == "" || seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		result = append(result, ref)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind < result[j].Kind
+		}
+		return result[i].Value < result[j].Value
+	})
+	return result
+}
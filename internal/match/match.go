@@ -0,0 +1,215 @@
+// Package match selects inventoried data sources by the identifiers analysts
+// use to talk about them: provider type, name, Splunk sourcetype, Log
+// Analytics table, Elastic data stream dataset and QRadar DSM type.
+package match
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/logfiend/internal/types"
+)
+
+// Criteria describes which data sources a rule applies to. All values are
+// case-insensitive glob patterns. Types restricts the candidate sources; a
+// source matches if any of the remaining selectors matches. Criteria with only
+// Types set match every source of those types.
+type Criteria struct {
+	Types       []string `yaml:"types,omitempty" json:"types,omitempty"`
+	Names       []string `yaml:"names,omitempty" json:"names,omitempty"`
+	Sourcetypes []string `yaml:"sourcetypes,omitempty" json:"sourcetypes,omitempty"`
+	Tables      []string `yaml:"tables,omitempty" json:"tables,omitempty"`
+	Datasets    []string `yaml:"datasets,omitempty" json:"datasets,omitempty"`
+	DSMTypes    []string `yaml:"dsm_types,omitempty" json:"dsm_types,omitempty"`
+}
+
+// Attributes are the identifiers extracted from a data source for matching
+type Attributes struct {
+	Type        string
+	Names       []string
+	Sourcetypes []string
+	Tables      []string
+	Datasets    []string
+	DSMTypes    []string
+}
+
+// IsEmpty reports whether the criteria have no selectors at all
+func (c Criteria) IsEmpty() bool {
+	return len(c.Types) == 0 && !c.hasSelectors()
+}
+
+func (c Criteria) hasSelectors() bool {
+	return len(c.Names)+len(c.Sourcetypes)+len(c.Tables)+len(c.Datasets)+len(c.DSMTypes) > 0
+}
+
+// Matches reports whether the data source satisfies the criteria
+func (c Criteria) Matches(ds types.DataSource) bool {
+	return c.MatchesAttributes(Extract(ds))
+}
+
+// MatchesAttributes is Matches for pre-extracted attributes
+func (c Criteria) MatchesAttributes(attrs Attributes) bool {
+	if c.IsEmpty() {
+		return false
+	}
+	if len(c.Types) > 0 && !anyMatch(c.Types, []string{attrs.Type}) {
+		return false
+	}
+	if !c.hasSelectors() {
+		return true
+	}
+
+	return anyMatch(c.Names, attrs.Names) ||
+		anyMatch(c.Sourcetypes, attrs.Sourcetypes) ||
+		anyMatch(c.Tables, attrs.Tables) ||
+		anyMatch(c.Datasets, attrs.Datasets) ||
+		anyMatch(c.DSMTypes, attrs.DSMTypes)
+}
+
+// Extract derives matching attributes from a data source's typed fields and
+// the well-known metadata keys providers populate
+func Extract(ds types.DataSource) Attributes {
+	attrs := Attributes{
+		Type:        ds.Type,
+		Names:       nonEmpty(ds.Name, ds.Title, ds.Pattern),
+		Sourcetypes: metadataStrings(ds.Metadata, "sourcetype", "sourcetypes"),
+		Tables:      metadataStrings(ds.Metadata, "table", "tables"),
+		Datasets:    metadataStrings(ds.Metadata, "dataset", "datasets"),
+		DSMTypes:    metadataStrings(ds.Metadata, "typeName", "dsmType"),
+	}
+
+	if ds.Type == "log-analytics-table" {
+		attrs.Tables = append(attrs.Tables, ds.Name)
+	}
+	for _, candidate := range nonEmpty(ds.Pattern, ds.Name) {
+		for _, pattern := range strings.Split(candidate, ",") {
+			if dataset, ok := DatasetFromPattern(pattern); ok {
+				attrs.Datasets = append(attrs.Datasets, dataset)
+			}
+		}
+	}
+
+	return attrs
+}
+
+// DatasetFromPattern extracts the dataset from an Elastic data stream name or
+// pattern following the <type>-<dataset>-<namespace> naming scheme, e.g.
+// "logs-system.auth-*" yields "system.auth"
+func DatasetFromPattern(pattern string) (string, bool) {
+	pattern = strings.TrimPrefix(strings.TrimSpace(pattern), ".ds-")
+	parts := strings.SplitN(pattern, "-", 3)
+	if len(parts) < 2 {
+		return "", false
+	}
+	switch parts[0] {
+	case "logs", "metrics", "traces", "synthetics":
+	default:
+		return "", false
+	}
+	dataset := parts[1]
+	if dataset == "" || strings.ContainsAny(dataset, "*?") {
+		return "", false
+	}
+	return dataset, true
+}
+
+// Active reports whether a data source is currently collecting. Sources that
+// are disabled or whose collector is unreachable are considered inactive.
+func Active(ds types.DataSource) bool {
+	if ds.Normalized != nil && ds.Normalized.Enabled != nil && !*ds.Normalized.Enabled {
+		return false
+	}
+	return !inactiveStatuses[strings.ToLower(ds.Status)]
+}
+
+// inactiveStatuses are DataSource.Status values meaning no data is flowing
+var inactiveStatuses = map[string]bool{
+	"disabled":        true,
+	"offline":         true,
+	"disconnected":    true,
+	"never_connected": true,
+	"stopped":         true,
+	"failed":          true,
+	"error":           true,
+	"no_data":         true,
+}
+
+// Glob reports whether value matches the case-insensitive glob pattern
+func Glob(pattern, value string) bool {
+	pattern = strings.ToLower(pattern)
+	value = strings.ToLower(value)
+	return wildcard(pattern, value)
+}
+
+// wildcard matches '*' (any run) and '?' (one character). Unlike path.Match,
+// '/' is not special, since sourcetypes such as ".../Operational" contain it.
+func wildcard(pattern, value string) bool {
+	p, v := []rune(pattern), []rune(value)
+	pi, vi := 0, 0
+	star, resume := -1, 0
+	for vi < len(v) {
+		switch {
+		case pi < len(p) && (p[pi] == '?' || p[pi] == v[vi]):
+			pi++
+			vi++
+		case pi < len(p) && p[pi] == '*':
+			star, resume = pi, vi
+			pi++
+		case star >= 0:
+			pi = star + 1
+			resume++
+			vi = resume
+		default:
+			return false
+		}
+	}
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
+
+func anyMatch(patterns, values []string) bool {
+	for _, pattern := range patterns {
+		for _, value := range values {
+			if Glob(pattern, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func nonEmpty(values ...string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// metadataStrings reads string or string-list values from metadata keys,
+// accepting both []string and the []interface{} produced by JSON decoding
+func metadataStrings(metadata map[string]interface{}, keys ...string) []string {
+	var result []string
+	for _, key := range keys {
+		switch value := metadata[key].(type) {
+		case string:
+			if value != "" {
+				result = append(result, value)
+			}
+		case []string:
+			result = append(result, value...)
+		case []interface{}:
+			for _, item := range value {
+				if text, ok := item.(string); ok && text != "" {
+					result = append(result, text)
+				}
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
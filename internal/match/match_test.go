@@ -0,0 +1,67 @@
+package match
+
+import (
+	"testing"
+
+	"github.com/logfiend/internal/types"
+)
+
+func TestGlob(t *testing.T) {
+	cases := []struct {
+		pattern, value string
+		ok             bool
+	}{
+		{"SecurityEvent", "securityevent", true},
+		{"*Sysmon/Operational", "XmlWinEventLog:Microsoft-Windows-Sysmon/Operational", true},
+		{"pan:*", "pan:traffic", true},
+		{"cisco:ftd?", "cisco:ftd1", true},
+		{"pan:*", "cisco:asa", false},
+		{"a*b*c", "axxbyyc", true},
+		{"a*b*c", "axxbyy", false},
+	}
+	for _, c := range cases {
+		if got := Glob(c.pattern, c.value); got != c.ok {
+			t.Errorf("Glob(%q, %q) = %v, want %v", c.pattern, c.value, got, c.ok)
+		}
+	}
+}
+
+func TestDatasetFromPattern(t *testing.T) {
+	cases := map[string]string{
+		"logs-system.auth-*":                "system.auth",
+		".ds-logs-aws.cloudtrail-default-1": "aws.cloudtrail",
+		"logs-*":                            "",
+		"filebeat-*":                        "",
+	}
+	for pattern, want := range cases {
+		got, _ := DatasetFromPattern(pattern)
+		if got != want {
+			t.Errorf("DatasetFromPattern(%q) = %q, want %q", pattern, got, want)
+		}
+	}
+}
+
+func TestCriteriaMatches(t *testing.T) {
+	table := types.DataSource{Name: "SigninLogs", Type: "log-analytics-table"}
+	qradar := types.DataSource{Name: "dc01", Type: "qradar-log-source",
+		Metadata: map[string]interface{}{"typeName": "Microsoft Windows Security Event Log"}}
+
+	byTable := Criteria{Tables: []string{"SigninLogs"}}
+	if !byTable.Matches(table) || byTable.Matches(qradar) {
+		t.Fatal("table criteria matched the wrong sources")
+	}
+
+	byDSM := Criteria{Types: []string{"qradar-*"}, DSMTypes: []string{"microsoft windows security event log"}}
+	if !byDSM.Matches(qradar) || byDSM.Matches(table) {
+		t.Fatal("dsm criteria matched the wrong sources")
+	}
+
+	typeOnly := Criteria{Types: []string{"log-analytics-table"}}
+	if !typeOnly.Matches(table) {
+		t.Fatal("type-only criteria should match every source of the type")
+	}
+
+	if (Criteria{}).Matches(table) {
+		t.Fatal("empty criteria should match nothing")
+	}
+}
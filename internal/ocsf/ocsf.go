@@ -0,0 +1,289 @@
+// Package ocsf classifies inventoried data sources into the Open
+// Cybersecurity Schema Framework (OCSF) categories and classes they would
+// populate, using a built-in catalog that users can extend.
+package ocsf
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/logfiend/internal/match"
+	"github.com/logfiend/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed catalog.yml
+var bundledCatalog []byte
+
+// Catalog is the mapping file format
+type Catalog struct {
+	Version     int        `yaml:"version"`
+	OCSFVersion string     `yaml:"ocsf_version"`
+	Replace     bool       `yaml:"replace,omitempty"`
+	Categories  []Category `yaml:"categories"`
+	Classes     []Class    `yaml:"classes"`
+	Mappings    []Mapping  `yaml:"mappings"`
+}
+
+// Category is an OCSF event category
+type Category struct {
+	UID  int    `yaml:"uid"`
+	Name string `yaml:"name"`
+}
+
+// Class is an OCSF event class
+type Class struct {
+	UID         int    `yaml:"uid"`
+	Name        string `yaml:"name"`
+	CategoryUID int    `yaml:"category_uid"`
+}
+
+// Mapping assigns OCSF classes to matching data sources
+type Mapping struct {
+	Name    string         `yaml:"name"`
+	Classes []int          `yaml:"classes"`
+	Match   match.Criteria `yaml:"match"`
+}
+
+// DefaultCatalog returns the built-in catalog
+func DefaultCatalog() (*Catalog, error) {
+	catalog, err := parseCatalog(bundledCatalog, "built-in catalog")
+	if err != nil {
+		return nil, err
+	}
+	if err := catalog.validate(); err != nil {
+		return nil, fmt.Errorf("invalid built-in catalog: %w", err)
+	}
+	return catalog, nil
+}
+
+// LoadCatalog returns the built-in catalog extended by the file at path.
+// Entries in the file replace built-in entries with the same name or UID
+// unless the file sets `replace: true`, which discards the built-in catalog.
+func LoadCatalog(path string) (*Catalog, error) {
+	catalog, err := DefaultCatalog()
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return catalog, nil
+	}
+
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("error reading OCSF mappings file '%s': %w", path, err)
+	}
+	extension, err := parseCatalog(data, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if extension.Replace {
+		catalog = extension
+	} else {
+		catalog.merge(extension)
+	}
+	if err := catalog.validate(); err != nil {
+		return nil, fmt.Errorf("invalid OCSF mappings file '%s': %w", path, err)
+	}
+	return catalog, nil
+}
+
+func parseCatalog(data []byte, source string) (*Catalog, error) {
+	var catalog Catalog
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", source, err)
+	}
+	return &catalog, nil
+}
+
+func (c *Catalog) merge(extension *Catalog) {
+	if extension.OCSFVersion != "" {
+		c.OCSFVersion = extension.OCSFVersion
+	}
+
+	for _, category := range extension.Categories {
+		if i := c.categoryIndex(category.UID); i >= 0 {
+			c.Categories[i] = category
+		} else {
+			c.Categories = append(c.Categories, category)
+		}
+	}
+
+	for _, class := range extension.Classes {
+		if i := c.classIndex(class.UID); i >= 0 {
+			c.Classes[i] = class
+		} else {
+			c.Classes = append(c.Classes, class)
+		}
+	}
+
+	for _, mapping := range extension.Mappings {
+		replaced := false
+		for i := range c.Mappings {
+			if c.Mappings[i].Name == mapping.Name {
+				c.Mappings[i] = mapping
+				replaced = true
+			}
+		}
+		if !replaced {
+			c.Mappings = append(c.Mappings, mapping)
+		}
+	}
+}
+
+func (c *Catalog) categoryIndex(uid int) int {
+	for i, category := range c.Categories {
+		if category.UID == uid {
+			return i
+		}
+	}
+	return -1
+}
+
+func (c *Catalog) classIndex(uid int) int {
+	for i, class := range c.Classes {
+		if class.UID == uid {
+			return i
+		}
+	}
+	return -1
+}
+
+// validate checks that mappings only reference declared classes and categories
+func (c *Catalog) validate() error {
+	if c.OCSFVersion == "" {
+		return fmt.Errorf("ocsf_version is required")
+	}
+	for _, class := range c.Classes {
+		if c.categoryIndex(class.CategoryUID) < 0 {
+			return fmt.Errorf("class %d references unknown category %d", class.UID, class.CategoryUID)
+		}
+	}
+	for _, mapping := range c.Mappings {
+		if mapping.Name == "" {
+			return fmt.Errorf("mapping entries require a name")
+		}
+		if mapping.Match.IsEmpty() {
+			return fmt.Errorf("mapping %q: match criteria are required", mapping.Name)
+		}
+		if len(mapping.Classes) == 0 {
+			return fmt.Errorf("mapping %q: at least one class is required", mapping.Name)
+		}
+		for _, uid := range mapping.Classes {
+			if c.classIndex(uid) < 0 {
+				return fmt.Errorf("mapping %q: unknown class %d", mapping.Name, uid)
+			}
+		}
+	}
+	return nil
+}
+
+// Classify returns the OCSF classes a data source would populate, or nil if
+// no mapping matches
+func (c *Catalog) Classify(ds types.DataSource) *types.OCSFClassification {
+	attrs := match.Extract(ds)
+	classUIDs := make(map[int]bool)
+	var matchedBy []string
+	for _, mapping := range c.Mappings {
+		if !mapping.Match.MatchesAttributes(attrs) {
+			continue
+		}
+		matchedBy = append(matchedBy, mapping.Name)
+		for _, uid := range mapping.Classes {
+			classUIDs[uid] = true
+		}
+	}
+	if len(classUIDs) == 0 {
+		return nil
+	}
+
+	classes := make([]types.OCSFClass, 0, len(classUIDs))
+	for uid := range classUIDs {
+		classes = append(classes, c.describe(uid))
+	}
+	sort.Slice(classes, func(i, j int) bool {
+		return classes[i].ClassUID < classes[j].ClassUID
+	})
+
+	return &types.OCSFClassification{
+		SchemaVersion: c.OCSFVersion,
+		Classes:       classes,
+		MatchedBy:     matchedBy,
+	}
+}
+
+// describe resolves a class UID to its names; validate guarantees it exists
+func (c *Catalog) describe(uid int) types.OCSFClass {
+	class := c.Classes[c.classIndex(uid)]
+	category := c.Categories[c.categoryIndex(class.CategoryUID)]
+	return types.OCSFClass{
+		CategoryUID:  category.UID,
+		CategoryName: category.Name,
+		ClassUID:     class.UID,
+		ClassName:    class.Name,
+	}
+}
+
+// Apply classifies every data source in the inventory and attaches the
+// per-category summary
+func (c *Catalog) Apply(inventory *types.DataSourceInventory) {
+	for i := range inventory.DataSources {
+		inventory.DataSources[i].OCSF = c.Classify(inventory.DataSources[i])
+	}
+	inventory.OCSFSummary = c.Summarize(inventory.DataSources)
+}
+
+// Summarize counts classified sources per category and class. A source that
+// populates several classes in one category counts once for the category.
+func (c *Catalog) Summarize(dataSources []types.DataSource) *types.OCSFSummary {
+	summary := &types.OCSFSummary{SchemaVersion: c.OCSFVersion, Categories: []types.OCSFCategorySummary{}}
+	categories := make(map[int]*types.OCSFCategorySummary)
+	classCounts := make(map[int]map[int]*types.OCSFClassSummary)
+
+	for _, ds := range dataSources {
+		if ds.OCSF == nil || len(ds.OCSF.Classes) == 0 {
+			summary.UnclassifiedCount++
+			continue
+		}
+		summary.ClassifiedCount++
+
+		seenCategories := make(map[int]bool)
+		for _, class := range ds.OCSF.Classes {
+			category, ok := categories[class.CategoryUID]
+			if !ok {
+				category = &types.OCSFCategorySummary{CategoryUID: class.CategoryUID, CategoryName: class.CategoryName}
+				categories[class.CategoryUID] = category
+				classCounts[class.CategoryUID] = make(map[int]*types.OCSFClassSummary)
+			}
+			if !seenCategories[class.CategoryUID] {
+				category.SourceCount++
+				seenCategories[class.CategoryUID] = true
+			}
+
+			counts := classCounts[class.CategoryUID]
+			if _, ok := counts[class.ClassUID]; !ok {
+				counts[class.ClassUID] = &types.OCSFClassSummary{ClassUID: class.ClassUID, ClassName: class.ClassName}
+			}
+			counts[class.ClassUID].SourceCount++
+		}
+	}
+
+	for uid, category := range categories {
+		for _, class := range classCounts[uid] {
+			category.Classes = append(category.Classes, *class)
+		}
+		sort.Slice(category.Classes, func(i, j int) bool {
+			return category.Classes[i].ClassUID < category.Classes[j].ClassUID
+		})
+		summary.Categories = append(summary.Categories, *category)
+	}
+	sort.Slice(summary.Categories, func(i, j int) bool {
+		return summary.Categories[i].CategoryUID < summary.Categories[j].CategoryUID
+	})
+
+	return summary
+}
@@ -0,0 +1,121 @@
+package ocsf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/logfiend/internal/types"
+)
+
+func classUIDs(classification *types.OCSFClassification) []int {
+	if classification == nil {
+		return nil
+	}
+	uids := make([]int, 0, len(classification.Classes))
+	for _, class := range classification.Classes {
+		uids = append(uids, class.ClassUID)
+	}
+	return uids
+}
+
+func TestClassifyBuiltInMappings(t *testing.T) {
+	catalog, err := DefaultCatalog()
+	if err != nil {
+		t.Fatalf("built-in catalog invalid: %v", err)
+	}
+
+	cases := []struct {
+		name string
+		ds   types.DataSource
+		want []int
+	}{
+		{"sentinel table", types.DataSource{Name: "DnsEvents", Type: "log-analytics-table"}, []int{4003}},
+		{"elastic dataset", types.DataSource{Name: "logs-okta.system-*", Pattern: "logs-okta.system-*", Type: "data-view"}, []int{3001, 3002}},
+		{"qradar dsm", types.DataSource{Name: "fw01", Type: "qradar-log-source",
+			Metadata: map[string]interface{}{"typeName": "Palo Alto PA Series"}}, []int{4001}},
+		{"splunk sourcetype", types.DataSource{Name: "proxy", Type: "splunk-index",
+			Metadata: map[string]interface{}{"sourcetypes": []string{"zscalernss-web"}}}, []int{4002}},
+		{"unknown", types.DataSource{Name: "main", Type: "splunk-index"}, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := classUIDs(catalog.Classify(c.ds))
+			if len(got) != len(c.want) {
+				t.Fatalf("expected classes %v, got %v", c.want, got)
+			}
+			for i := range got {
+				if got[i] != c.want[i] {
+					t.Fatalf("expected classes %v, got %v", c.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestApplySummarizesByCategory(t *testing.T) {
+	catalog, err := DefaultCatalog()
+	if err != nil {
+		t.Fatalf("built-in catalog invalid: %v", err)
+	}
+
+	inventory := types.DataSourceInventory{DataSources: []types.DataSource{
+		{Name: "SigninLogs", Type: "log-analytics-table"},
+		{Name: "SecurityEvent", Type: "log-analytics-table"},
+		{Name: "Heartbeat", Type: "log-analytics-table"},
+	}}
+	catalog.Apply(&inventory)
+
+	summary := inventory.OCSFSummary
+	if summary.ClassifiedCount != 2 || summary.UnclassifiedCount != 1 {
+		t.Fatalf("unexpected counts %+v", summary)
+	}
+	for _, category := range summary.Categories {
+		if category.CategoryUID != 3 {
+			continue
+		}
+		if category.SourceCount != 2 {
+			t.Fatalf("expected both sources in IAM, got %d", category.SourceCount)
+		}
+		for _, class := range category.Classes {
+			if class.ClassUID == 3002 && class.SourceCount != 2 {
+				t.Fatalf("expected 2 authentication sources, got %d", class.SourceCount)
+			}
+		}
+		return
+	}
+	t.Fatal("IAM category missing from summary")
+}
+
+func TestLoadCatalogExtension(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ocsf.yml")
+	extension := `
+classes:
+  - {uid: 6002, name: Application Lifecycle, category_uid: 6}
+mappings:
+  - name: in-house-app
+    classes: [6002]
+    match:
+      names: ["app-*"]
+`
+	if err := os.WriteFile(path, []byte(extension), 0600); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	catalog, err := LoadCatalog(path)
+	if err != nil {
+		t.Fatalf("LoadCatalog error: %v", err)
+	}
+	got := classUIDs(catalog.Classify(types.DataSource{Name: "app-billing", Type: "splunk-index"}))
+	if len(got) != 1 || got[0] != 6002 {
+		t.Fatalf("expected extension mapping to apply, got %v", got)
+	}
+
+	bad := "mappings:\n  - name: x\n    classes: [9999]\n    match:\n      names: [x]\n"
+	if err := os.WriteFile(path, []byte(bad), 0600); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+	if _, err := LoadCatalog(path); err == nil {
+		t.Fatal("expected error for unknown class")
+	}
+}
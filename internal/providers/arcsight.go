@@ -0,0 +1,593 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+// ArcSightProvider implements the Provider interface for ArcSight Logger and
+// ESM. Both products authenticate through the core-service LoginService,
+// which ESM serves under /www.
+// Logger inventories storage groups, receivers and the SmartConnectors seen
+// by a chart search; ESM inventories the connectors registered with the
+// manager.
+type ArcSightProvider struct {
+	config       types.ProviderConfig
+	client       *http.Client
+	baseURL      string
+	product      string
+	searchWindow time.Duration
+	pollInterval time.Duration
+	now          func() time.Time
+
+	mu    sync.Mutex
+	token cachedToken
+}
+
+// ArcSightStorageGroup represents a Logger storage group
+type ArcSightStorageGroup struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	MaxSizeGB  float64 `json:"maxSizeGB"`
+	UsedSizeGB float64 `json:"usedSizeGB"`
+	MaxAgeDays int     `json:"maxAgeDays"`
+	Status     string  `json:"status"`
+}
+
+// ArcSightReceiver represents a Logger receiver
+type ArcSightReceiver struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	Port         int    `json:"port"`
+	Enabled      bool   `json:"enabled"`
+	Status       string `json:"status"`
+	StorageGroup string `json:"storageGroup"`
+}
+
+// ArcSightAgent represents a connector registered with ESM
+type ArcSightAgent struct {
+	ResourceID string `json:"resourceid"`
+	Name       string `json:"name"`
+	AgentType  string `json:"agentType"`
+	Version    string `json:"version"`
+	Status     string `json:"status"`
+}
+
+// ArcSightSearchChartResponse represents the /server/search/chart_data response
+type ArcSightSearchChartResponse struct {
+	Fields []struct {
+		Name string `json:"name"`
+	} `json:"fields"`
+	Results [][]interface{} `json:"results"`
+}
+
+// arcSightConnector accumulates the devices a SmartConnector has forwarded
+type arcSightConnector struct {
+	name       string
+	agentType  string
+	eventCount int64
+	vendors    map[string]bool
+	products   map[string]bool
+	devices    map[string]bool
+}
+
+const (
+	arcSightLoginPath      = "/core-service/rest/LoginService/login"
+	arcSightESMPrefix      = "/www"
+	arcSightTokenLifetime  = 10 * time.Minute
+	arcSightDefaultWindow  = 24 * time.Hour
+	arcSightConnectorQuery = "* | chart count by agentName,agentType,deviceVendor,deviceProduct"
+)
+
+// NewArcSightProvider creates a new ArcSight provider. options.product is
+// "logger" (default) or "esm".
+func NewArcSightProvider(config types.ProviderConfig) (types.Provider, error) {
+	client, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &ArcSightProvider{
+		config:       config,
+		client:       client,
+		baseURL:      strings.TrimSuffix(config.Endpoint, "/"),
+		product:      "logger",
+		searchWindow: arcSightDefaultWindow,
+		pollInterval: time.Second,
+		now:          time.Now,
+	}
+
+	if product := strings.ToLower(config.Options["product"]); product != "" {
+		if product != "logger" && product != "esm" {
+			return nil, fmt.Errorf("unknown ArcSight product: %s", product)
+		}
+		provider.product = product
+	}
+	if window := config.Options["search_window"]; window != "" {
+		provider.searchWindow, err = time.ParseDuration(window)
+		if err != nil || provider.searchWindow <= 0 {
+			return nil, fmt.Errorf("invalid search_window: %s", window)
+		}
+	}
+
+	return provider, nil
+}
+
+func (a *ArcSightProvider) Name() string {
+	return "arcsight"
+}
+
+func (a *ArcSightProvider) FetchDataViews(ctx context.Context) ([]types.DataSource, error) {
+	if a.product == "esm" {
+		return a.fetchESMConnectors(ctx)
+	}
+
+	var storageGroups []ArcSightStorageGroup
+	if err := a.callService(ctx, "StorageGroupService", "getStorageGroups", nil, &storageGroups); err != nil {
+		return nil, fmt.Errorf("failed to fetch storage groups: %w", err)
+	}
+
+	var receivers []ArcSightReceiver
+	if err := a.callService(ctx, "ReceiverService", "getReceivers", nil, &receivers); err != nil {
+		return nil, fmt.Errorf("failed to fetch receivers: %w", err)
+	}
+
+	connectors, err := a.searchConnectors(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch connectors: %w", err)
+	}
+
+	dataSources := []types.DataSource{}
+	storageGroupIDs := make(map[string]string, len(storageGroups))
+	for _, group := range storageGroups {
+		storageGroupIDs[group.Name] = "storage-group:" + group.ID
+		dataSources = append(dataSources, a.convertStorageGroup(group))
+	}
+	for _, receiver := range receivers {
+		dataSources = append(dataSources, a.convertReceiver(receiver, storageGroupIDs))
+	}
+	dataSources = append(dataSources, connectors...)
+	return dataSources, nil
+}
+
+func (a *ArcSightProvider) convertStorageGroup(group ArcSightStorageGroup) types.DataSource {
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(true)
+	n.SizeBytes = int64Ptr(megabytesToBytes(group.UsedSizeGB * 1024))
+	if group.MaxAgeDays > 0 {
+		n.RetentionDays = intPtr(group.MaxAgeDays)
+	}
+
+	status := "active"
+	if group.Status != "" {
+		status = arcSightStatus(group.Status)
+	}
+
+	return types.DataSource{
+		ID:         "storage-group:" + group.ID,
+		Name:       group.Name,
+		Title:      group.Name,
+		Type:       "arcsight-storage-group",
+		Status:     status,
+		Tags:       []string{"arcsight", "storage-group"},
+		Normalized: n,
+		Metadata: map[string]interface{}{
+			"maxSizeGB":  group.MaxSizeGB,
+			"usedSizeGB": group.UsedSizeGB,
+			"maxAgeDays": group.MaxAgeDays,
+		},
+	}
+}
+
+func (a *ArcSightProvider) convertReceiver(receiver ArcSightReceiver, storageGroupIDs map[string]string) types.DataSource {
+	status := arcSightStatus(receiver.Status)
+	if !receiver.Enabled {
+		status = "disabled"
+	}
+
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(receiver.Enabled)
+
+	metadata := map[string]interface{}{
+		"receiverType": receiver.Type,
+	}
+	if receiver.Port > 0 {
+		metadata["port"] = receiver.Port
+	}
+	if receiver.StorageGroup != "" {
+		metadata["storageGroup"] = receiver.StorageGroup
+	}
+
+	ds := types.DataSource{
+		ID:         "receiver:" + receiver.ID,
+		Name:       receiver.Name,
+		Title:      receiver.Name,
+		Type:       "arcsight-receiver",
+		Status:     status,
+		Tags:       []string{"arcsight", "receiver"},
+		Normalized: n,
+		Metadata:   metadata,
+	}
+	if target, ok := storageGroupIDs[receiver.StorageGroup]; ok {
+		ds.Relations = []types.Relation{{Type: types.RelationStoredIn, TargetID: target}}
+	}
+	return ds
+}
+
+// searchConnectors runs a Logger chart search over the search window to find
+// the SmartConnectors that forwarded events and the devices behind them
+func (a *ArcSightProvider) searchConnectors(ctx context.Context) ([]types.DataSource, error) {
+	token, err := a.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	end := a.now().UTC()
+	session := map[string]interface{}{
+		"search_session_id": end.UnixMilli(),
+		"user_session_id":   token,
+	}
+	start := map[string]interface{}{
+		"query":      arcSightConnectorQuery,
+		"start_time": end.Add(-a.searchWindow).Format(time.RFC3339),
+		"end_time":   end.Format(time.RFC3339),
+	}
+	for key, value := range session {
+		start[key] = value
+	}
+
+	if err := a.postJSON(ctx, "/server/search", start, nil); err != nil {
+		return nil, fmt.Errorf("failed to start search: %w", err)
+	}
+	defer a.postJSON(context.Background(), "/server/search/close", session, nil)
+
+	for {
+		var status struct {
+			Status string `json:"status"`
+		}
+		if err := a.postJSON(ctx, "/server/search/status", session, &status); err != nil {
+			return nil, fmt.Errorf("failed to poll search: %w", err)
+		}
+		if status.Status == "complete" {
+			break
+		}
+		if status.Status == "error" {
+			return nil, fmt.Errorf("arcsight search failed")
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(a.pollInterval):
+		}
+	}
+
+	var chart ArcSightSearchChartResponse
+	if err := a.postJSON(ctx, "/server/search/chart_data", session, &chart); err != nil {
+		return nil, fmt.Errorf("failed to read search results: %w", err)
+	}
+
+	column := make(map[string]int, len(chart.Fields))
+	for i, field := range chart.Fields {
+		column[field.Name] = i
+	}
+	value := func(row []interface{}, name string) string {
+		if i, ok := column[name]; ok && i < len(row) && row[i] != nil {
+			return fmt.Sprint(row[i])
+		}
+		return ""
+	}
+
+	connectors := make(map[string]*arcSightConnector)
+	for _, row := range chart.Results {
+		name := value(row, "agentName")
+		if name == "" {
+			continue
+		}
+		connector, ok := connectors[name]
+		if !ok {
+			connector = &arcSightConnector{
+				name:     name,
+				vendors:  make(map[string]bool),
+				products: make(map[string]bool),
+				devices:  make(map[string]bool),
+			}
+			connectors[name] = connector
+		}
+		if agentType := value(row, "agentType"); agentType != "" {
+			connector.agentType = agentType
+		}
+		vendor, product := value(row, "deviceVendor"), value(row, "deviceProduct")
+		if vendor != "" {
+			connector.vendors[vendor] = true
+		}
+		if product != "" {
+			connector.products[product] = true
+		}
+		if vendor != "" || product != "" {
+			connector.devices[vendor+"|"+product] = true
+		}
+		if count, ok := parseInt64(value(row, "count")); ok {
+			connector.eventCount += count
+		}
+	}
+
+	names := make([]string, 0, len(connectors))
+	for name := range connectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	dataSources := make([]types.DataSource, 0, len(names))
+	for _, name := range names {
+		dataSources = append(dataSources, a.convertSearchedConnector(connectors[name]))
+	}
+	return dataSources, nil
+}
+
+func (a *ArcSightProvider) convertSearchedConnector(connector *arcSightConnector) types.DataSource {
+	vendors, products, devices := sortedKeys(connector.vendors), sortedKeys(connector.products), sortedKeys(connector.devices)
+
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(true)
+	n.EventCount = int64Ptr(connector.eventCount)
+	if len(vendors) == 1 {
+		n.Vendor = vendors[0]
+	}
+	if len(products) == 1 {
+		n.Product = products[0]
+	}
+
+	return types.DataSource{
+		ID:         "connector:" + connector.name,
+		Name:       connector.name,
+		Title:      connector.name,
+		Type:       "arcsight-connector",
+		Status:     "active",
+		Tags:       []string{"arcsight", "connector"},
+		Normalized: n,
+		Metadata: map[string]interface{}{
+			"agentType":      connector.agentType,
+			"deviceVendors":  vendors,
+			"deviceProducts": products,
+			"devices":        devices,
+			"eventCount":     connector.eventCount,
+			"searchWindow":   a.searchWindow.String(),
+		},
+	}
+}
+
+// fetchESMConnectors lists the connectors registered with an ESM manager
+func (a *ArcSightProvider) fetchESMConnectors(ctx context.Context) ([]types.DataSource, error) {
+	var ids []string
+	if err := a.callService(ctx, "ConnectorService", "getAllAgentIDs", nil, &ids); err != nil {
+		return nil, fmt.Errorf("failed to fetch connectors: %w", err)
+	}
+
+	dataSources := make([]types.DataSource, 0, len(ids))
+	for _, id := range ids {
+		var agent ArcSightAgent
+		params := url.Values{}
+		params.Set("agentID", id)
+		if err := a.callService(ctx, "ConnectorService", "getAgentByID", params, &agent); err != nil {
+			return nil, fmt.Errorf("failed to fetch connector %s: %w", id, err)
+		}
+
+		status := arcSightStatus(agent.Status)
+		n := types.NewNormalizedFields()
+		n.Enabled = boolPtr(status == "active")
+
+		name := agent.Name
+		if name == "" {
+			name = id
+		}
+		dataSources = append(dataSources, types.DataSource{
+			ID:         "connector:" + id,
+			Name:       name,
+			Title:      name,
+			Type:       "arcsight-connector",
+			Status:     status,
+			Tags:       []string{"arcsight", "connector", "esm"},
+			Normalized: n,
+			Metadata: map[string]interface{}{
+				"resourceId": id,
+				"agentType":  agent.AgentType,
+				"version":    agent.Version,
+				"state":      agent.Status,
+			},
+		})
+	}
+	return dataSources, nil
+}
+
+// arcSightStatus maps connector and receiver states to DataSource statuses
+func arcSightStatus(state string) string {
+	switch strings.ToLower(state) {
+	case "", "running", "connected", "enabled", "active", "ok":
+		return "active"
+	case "down", "disconnected", "offline", "unreachable":
+		return "offline"
+	case "stopped", "paused":
+		return "stopped"
+	case "error", "failed":
+		return "failed"
+	case "warning", "degraded", "caching":
+		return "degraded"
+	}
+	return strings.ToLower(state)
+}
+
+// callService performs a GET against a core-service REST method and unwraps
+// the {"<ns>.<method>Response": {"<ns>.return": ...}} envelope into out
+func (a *ArcSightProvider) callService(ctx context.Context, service, method string, params url.Values, out interface{}) error {
+	token, err := a.authenticate(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	for key, values := range params {
+		query[key] = values
+	}
+	query.Set("authToken", token)
+
+	prefix := "/core-service/rest/"
+	if a.product == "esm" {
+		prefix = arcSightESMPrefix + "/manager-service/rest/"
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", a.baseURL+prefix+service+"/"+method+"?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var envelope json.RawMessage
+	if err := readJSONResponse(resp, "arcsight", &envelope); err != nil {
+		return err
+	}
+	return json.Unmarshal(arcSightReturn(envelope), out)
+}
+
+// arcSightReturn extracts the "*.return" value of a service response, or
+// returns the payload unchanged when it is not wrapped
+func arcSightReturn(payload json.RawMessage) json.RawMessage {
+	var outer map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &outer); err != nil || len(outer) != 1 {
+		return payload
+	}
+	for key, inner := range outer {
+		if !strings.HasSuffix(key, "Response") {
+			return payload
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(inner, &fields); err != nil {
+			return inner
+		}
+		for name, value := range fields {
+			if name == "return" || strings.HasSuffix(name, ".return") {
+				return value
+			}
+		}
+		return json.RawMessage("null")
+	}
+	return payload
+}
+
+// postJSON posts a JSON body to the Logger search API
+func (a *ArcSightProvider) postJSON(ctx context.Context, path string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", a.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return readJSONResponse(resp, "arcsight", out)
+}
+
+// authenticate logs in through the LoginService, or uses a pre-issued token
+// from a bearer auth block. Tokens are reused until the session would idle out.
+func (a *ArcSightProvider) authenticate(ctx context.Context) (string, error) {
+	auth := a.config.Auth
+	if auth != nil && auth.Type == "bearer" {
+		return auth.Token, nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	if a.token.valid(now) {
+		return a.token.value, nil
+	}
+
+	form := url.Values{}
+	if auth != nil {
+		form.Set("login", auth.Username)
+		form.Set("password", auth.Password)
+	}
+	loginURL := a.baseURL + arcSightLoginPath
+	if a.product == "esm" {
+		loginURL = a.baseURL + arcSightESMPrefix + arcSightLoginPath
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", loginURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create login request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to log in: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var envelope json.RawMessage
+	if err := readJSONResponse(resp, "arcsight", &envelope); err != nil {
+		return "", fmt.Errorf("login failed: %w", err)
+	}
+	var token string
+	if err := json.Unmarshal(arcSightReturn(envelope), &token); err != nil || token == "" {
+		return "", fmt.Errorf("arcsight login returned no token")
+	}
+
+	a.token.set(oauthTokenResponse{AccessToken: token, ExpiresIn: int64(arcSightTokenLifetime / time.Second)}, now)
+	return a.token.value, nil
+}
+
+func (a *ArcSightProvider) ValidateConnection(ctx context.Context) error {
+	if _, err := a.authenticate(ctx); err != nil {
+		return fmt.Errorf("failed to connect to ArcSight: %w", err)
+	}
+	return nil
+}
+
+func (a *ArcSightProvider) GetCapabilities() types.ProviderCapabilities {
+	dataTypes := []string{"arcsight-connector"}
+	if a.product == "logger" {
+		dataTypes = []string{"arcsight-storage-group", "arcsight-receiver", "arcsight-connector"}
+	}
+	return types.ProviderCapabilities{
+		SupportsRealTimeQueries: a.product == "logger",
+		SupportsHistoricalData:  true,
+		SupportedDataTypes:      dataTypes,
+		RequiresAuthentication:  true,
+	}
+}
+
+// sortedKeys returns the members of a set in order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,161 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+func arcSightLoginHandler(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("login") != "admin" || r.PostForm.Get("password") != "secret" {
+		http.Error(w, "bad credentials", http.StatusUnauthorized)
+		return
+	}
+	w.Write([]byte(`{"log.loginResponse":{"log.return":"arc-token"}}`))
+}
+
+func TestArcSightLoggerFetchDataViews(t *testing.T) {
+	logins, polls := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/core-service/rest/LoginService/login" {
+			logins++
+			arcSightLoginHandler(w, r)
+			return
+		}
+		if r.Method == "GET" && r.URL.Query().Get("authToken") != "arc-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.Method == "POST" {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["user_session_id"] != "arc-token" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+		switch r.URL.Path {
+		case "/core-service/rest/StorageGroupService/getStorageGroups":
+			w.Write([]byte(`{"sg.getStorageGroupsResponse":{"sg.return":[
+				{"id":"1","name":"Default Storage Group","maxSizeGB":500,"usedSizeGB":120.5,"maxAgeDays":90}]}}`))
+		case "/core-service/rest/ReceiverService/getReceivers":
+			w.Write([]byte(`{"rcv.getReceiversResponse":{"rcv.return":[
+				{"id":"r1","name":"SmartMessage Receiver","type":"SmartMessage","enabled":true,"status":"running","storageGroup":"Default Storage Group"},
+				{"id":"r2","name":"UDP 514","type":"UDP","port":514,"enabled":false}]}}`))
+		case "/server/search":
+			w.Write([]byte(`{"sessionId":1}`))
+		case "/server/search/status":
+			polls++
+			if polls < 2 {
+				w.Write([]byte(`{"status":"running"}`))
+				return
+			}
+			w.Write([]byte(`{"status":"complete"}`))
+		case "/server/search/chart_data":
+			w.Write([]byte(`{"fields":[{"name":"agentName"},{"name":"agentType"},{"name":"deviceVendor"},{"name":"deviceProduct"},{"name":"count"}],
+				"results":[["win-connector","windowsfg","Microsoft","Microsoft Windows","1500"],
+				           ["syslog-connector","syslog","Cisco","ASA",20],
+				           ["syslog-connector","syslog","Palo Alto Networks","PAN-OS",30]]}`))
+		case "/server/search/close":
+			w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewArcSightProvider(types.ProviderConfig{
+		Type:     "arcsight",
+		Endpoint: server.URL,
+		Auth:     &types.AuthConfig{Type: "basic", Username: "admin", Password: "secret"},
+		Timeout:  5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider.(*ArcSightProvider).pollInterval = time.Millisecond
+
+	sources, err := provider.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+	if logins != 1 {
+		t.Errorf("expected the login token to be reused, got %d logins", logins)
+	}
+
+	byID := make(map[string]types.DataSource)
+	for _, ds := range sources {
+		byID[ds.ID] = ds
+	}
+	if len(byID) != 5 {
+		t.Fatalf("expected 5 data sources, got %d", len(byID))
+	}
+
+	sg := byID["storage-group:1"]
+	if sg.Normalized.RetentionDays == nil || *sg.Normalized.RetentionDays != 90 || sg.Normalized.SizeBytes == nil {
+		t.Errorf("unexpected storage group %+v", sg.Normalized)
+	}
+	smart := byID["receiver:r1"]
+	if smart.Status != "active" || len(smart.Relations) != 1 || smart.Relations[0].TargetID != "storage-group:1" {
+		t.Errorf("unexpected receiver %+v", smart)
+	}
+	if ds := byID["receiver:r2"]; ds.Status != "disabled" {
+		t.Errorf("disabled receiver should be disabled, got %s", ds.Status)
+	}
+
+	win := byID["connector:win-connector"]
+	if win.Normalized.Vendor != "Microsoft" || win.Normalized.EventCount == nil || *win.Normalized.EventCount != 1500 {
+		t.Errorf("unexpected Windows connector %+v", win.Normalized)
+	}
+	syslog := byID["connector:syslog-connector"]
+	vendors, _ := syslog.Metadata["deviceVendors"].([]string)
+	if len(vendors) != 2 || syslog.Normalized.Vendor != "" || *syslog.Normalized.EventCount != 50 {
+		t.Errorf("unexpected syslog connector %+v %+v", syslog.Metadata, syslog.Normalized)
+	}
+}
+
+func TestArcSightESMConnectors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/www/core-service/rest/LoginService/login":
+			arcSightLoginHandler(w, r)
+		case "/www/manager-service/rest/ConnectorService/getAllAgentIDs":
+			w.Write([]byte(`{"cns.getAllAgentIDsResponse":{"cns.return":["a1","a2"]}}`))
+		case "/www/manager-service/rest/ConnectorService/getAgentByID":
+			if r.URL.Query().Get("agentID") == "a1" {
+				w.Write([]byte(`{"cns.getAgentByIDResponse":{"cns.return":{"resourceid":"a1","name":"fw-connector","status":"Running","version":"8.4"}}}`))
+				return
+			}
+			w.Write([]byte(`{"cns.getAgentByIDResponse":{"cns.return":{"resourceid":"a2","name":"fw-connector","status":"Down"}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewArcSightProvider(types.ProviderConfig{
+		Endpoint: server.URL,
+		Options:  map[string]string{"product": "esm"},
+		Auth:     &types.AuthConfig{Type: "basic", Username: "admin", Password: "secret"},
+		Timeout:  5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, err := provider.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+	if len(sources) != 2 || sources[0].Status != "active" || sources[1].Status != "offline" {
+		t.Fatalf("unexpected ESM connectors %+v", sources)
+	}
+	// Connector display names are not unique, so IDs come from the resource ID
+	if sources[0].ID != "connector:a1" || sources[1].ID != "connector:a2" || sources[1].Name != "fw-connector" {
+		t.Errorf("unexpected ESM connector IDs %s %s", sources[0].ID, sources[1].ID)
+	}
+}
@@ -0,0 +1,211 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+// STSAssumeRoleResponse represents the STS AssumeRole XML response
+type STSAssumeRoleResponse struct {
+	Credentials struct {
+		AccessKeyID     string `xml:"AccessKeyId"`
+		SecretAccessKey string `xml:"SecretAccessKey"`
+		SessionToken    string `xml:"SessionToken"`
+		Expiration      string `xml:"Expiration"`
+	} `xml:"AssumeRoleResult>Credentials"`
+}
+
+// resolveAWSCredentials finds credentials the way the AWS SDKs do, without
+// instance metadata: static keys in the auth block, then the AWS_* environment
+// variables, then the shared credentials file. With options.role_arn the
+// resolved credentials assume that role through STS, in auth.region or the
+// first of options.regions (the global endpoint without either);
+// options.sts_endpoint points at an STS-compatible service.
+func resolveAWSCredentials(ctx context.Context, client *http.Client, auth *types.AuthConfig, options map[string]string) (awsCredentials, error) {
+	credentials, err := baseAWSCredentials(auth, options)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+
+	roleARN := options["role_arn"]
+	if roleARN == "" {
+		return credentials, nil
+	}
+
+	region := ""
+	if auth != nil {
+		region = auth.Region
+	}
+	if region == "" {
+		region, _, _ = strings.Cut(options["regions"], ",")
+		region = strings.TrimSpace(region)
+	}
+	endpoint := options["sts_endpoint"]
+	if region == "" {
+		// The global endpoint signs as us-east-1
+		region = "us-east-1"
+		if endpoint == "" {
+			endpoint = "https://sts.amazonaws.com"
+		}
+	}
+	if endpoint == "" {
+		endpoint = "https://sts." + region + "." + awsDNSSuffix(region)
+	}
+	return assumeAWSRole(ctx, client, credentials, region, endpoint, roleARN, options["external_id"])
+}
+
+// awsDNSSuffix returns the domain of the partition a region belongs to
+func awsDNSSuffix(region string) string {
+	if strings.HasPrefix(region, "cn-") {
+		return "amazonaws.com.cn"
+	}
+	return "amazonaws.com"
+}
+
+// isAWSHost reports whether host is an AWS service endpoint in any partition
+func isAWSHost(host string) bool {
+	return strings.HasSuffix(host, ".amazonaws.com") || strings.HasSuffix(host, ".amazonaws.com.cn")
+}
+
+func baseAWSCredentials(auth *types.AuthConfig, options map[string]string) (awsCredentials, error) {
+	if auth != nil && auth.AccessKeyID != "" && auth.SecretAccessKey != "" {
+		return awsCredentials{
+			AccessKeyID:     auth.AccessKeyID,
+			SecretAccessKey: auth.SecretAccessKey,
+			SessionToken:    auth.SessionToken,
+		}, nil
+	}
+
+	if id, secret := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"); id != "" && secret != "" {
+		return awsCredentials{AccessKeyID: id, SecretAccessKey: secret, SessionToken: os.Getenv("AWS_SESSION_TOKEN")}, nil
+	}
+
+	path := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return awsCredentials{}, fmt.Errorf("no AWS credentials found: %w", err)
+		}
+		path = filepath.Join(home, ".aws", "credentials")
+	}
+	profile := options["profile"]
+	if profile == "" {
+		profile = os.Getenv("AWS_PROFILE")
+	}
+	if profile == "" {
+		profile = "default"
+	}
+
+	credentials, err := readSharedAWSCredentials(path, profile)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("no AWS credentials found in auth config, environment or %s: %w", path, err)
+	}
+	return credentials, nil
+}
+
+// readSharedAWSCredentials reads one profile from an INI-style credentials file
+func readSharedAWSCredentials(path, profile string) (awsCredentials, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return awsCredentials{}, err
+	}
+
+	var credentials awsCredentials
+	found := false
+	section := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if section == profile {
+				found = true
+			}
+			continue
+		}
+		if section != profile {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "aws_access_key_id":
+			credentials.AccessKeyID = strings.TrimSpace(value)
+		case "aws_secret_access_key":
+			credentials.SecretAccessKey = strings.TrimSpace(value)
+		case "aws_session_token":
+			credentials.SessionToken = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return awsCredentials{}, err
+	}
+
+	if !found {
+		return awsCredentials{}, fmt.Errorf("profile %q not found", profile)
+	}
+	if credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" {
+		return awsCredentials{}, fmt.Errorf("profile %q has no access keys", profile)
+	}
+	return credentials, nil
+}
+
+// assumeAWSRole calls STS AssumeRole signed with the base credentials
+func assumeAWSRole(ctx context.Context, client *http.Client, base awsCredentials, region, endpoint, roleARN, externalID string) (awsCredentials, error) {
+	form := url.Values{}
+	form.Set("Action", "AssumeRole")
+	form.Set("Version", "2011-06-15")
+	form.Set("RoleArn", roleARN)
+	form.Set("RoleSessionName", "logfiend")
+	if externalID != "" {
+		form.Set("ExternalId", externalID)
+	}
+	payload := []byte(form.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "POST", strings.TrimSuffix(endpoint, "/")+"/", bytes.NewReader(payload))
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("failed to create STS request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	signer := &sigV4Signer{credentials: base, region: region, service: "sts", now: time.Now}
+	signer.sign(req, payload)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("failed to assume role: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result STSAssumeRoleResponse
+	if err := readXMLResponse(resp, "sts", &result); err != nil {
+		return awsCredentials{}, fmt.Errorf("failed to assume role %s: %w", roleARN, err)
+	}
+	if result.Credentials.AccessKeyID == "" {
+		return awsCredentials{}, fmt.Errorf("sts returned no credentials for %s", roleARN)
+	}
+	credentials := awsCredentials{
+		AccessKeyID:     result.Credentials.AccessKeyID,
+		SecretAccessKey: result.Credentials.SecretAccessKey,
+		SessionToken:    result.Credentials.SessionToken,
+	}
+	if expiry, err := time.Parse(time.RFC3339, result.Credentials.Expiration); err == nil {
+		credentials.Expiry = expiry
+	}
+	return credentials, nil
+}
@@ -208,6 +208,13 @@ func (s *SentinelProvider) convertToDataSource(table struct {
 		ds.Metadata["columns"] = columns
 	}
 
+	// Log Analytics does not expose volumes or enablement per table
+	n := types.NewNormalizedFields()
+	if table.Properties.RetentionInDays > 0 {
+		n.RetentionDays = intPtr(table.Properties.RetentionInDays)
+	}
+	ds.Normalized = n
+
 	return ds
 }
 
@@ -262,11 +269,103 @@ func (s *SentinelProvider) ValidateConnection(ctx context.Context) error {
 	return nil
 }
 
+// SentinelAlertRulesResponse represents the Sentinel analytics rules response
+type SentinelAlertRulesResponse struct {
+	Value []struct {
+		ID         string `json:"id"`
+		Name       string `json:"name"`
+		Kind       string `json:"kind"`
+		Properties struct {
+			DisplayName string   `json:"displayName"`
+			Enabled     bool     `json:"enabled"`
+			Query       string   `json:"query"`
+			Severity    string   `json:"severity"`
+			Tactics     []string `json:"tactics"`
+			Techniques  []string `json:"techniques"`
+		} `json:"properties"`
+	} `json:"value"`
+	NextLink string `json:"nextLink"`
+}
+
+// FetchDetectionRules lists Sentinel analytics rules, following nextLink paging
+func (s *SentinelProvider) FetchDetectionRules(ctx context.Context) ([]types.DetectionRule, error) {
+	workspaceInfo, err := s.parseWorkspaceFromEndpoint()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse workspace info: %w", err)
+	}
+
+	apiURL := fmt.Sprintf("https://management.azure.com/subscriptions/%s/resourceGroups/%s/providers/Microsoft.OperationalInsights/workspaces/%s/providers/Microsoft.SecurityInsights/alertRules",
+		workspaceInfo["subscriptionId"],
+		workspaceInfo["resourceGroupName"],
+		workspaceInfo["workspaceName"])
+
+	params := url.Values{}
+	params.Add("api-version", "2023-02-01")
+	nextURL := fmt.Sprintf("%s?%s", apiURL, params.Encode())
+
+	rules := []types.DetectionRule{}
+	for nextURL != "" {
+		page, err := s.fetchAlertRulesPage(ctx, nextURL)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rule := range page.Value {
+			rules = append(rules, types.DetectionRule{
+				ID:       rule.ID,
+				Name:     rule.Properties.DisplayName,
+				Type:     "sentinel-analytics-rule",
+				Enabled:  rule.Properties.Enabled,
+				Language: "kql",
+				Query:    rule.Properties.Query,
+				Metadata: map[string]interface{}{
+					"kind":       rule.Kind,
+					"severity":   rule.Properties.Severity,
+					"tactics":    rule.Properties.Tactics,
+					"techniques": rule.Properties.Techniques,
+				},
+			})
+		}
+		nextURL = page.NextLink
+	}
+
+	return rules, nil
+}
+
+func (s *SentinelProvider) fetchAlertRulesPage(ctx context.Context, pageURL string) (*SentinelAlertRulesResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if s.config.Auth != nil {
+		s.addAuth(req)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("azure sentinel returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var page SentinelAlertRulesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &page, nil
+}
+
 func (s *SentinelProvider) GetCapabilities() types.ProviderCapabilities {
 	return types.ProviderCapabilities{
 		SupportsRealTimeQueries: true,
 		SupportsHistoricalData:  true,
 		SupportedDataTypes:      []string{"log-analytics-table", "custom-table"},
 		RequiresAuthentication:  true,
+		SupportsDetectionRules:  true,
 	}
 }
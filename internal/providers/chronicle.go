@@ -0,0 +1,525 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+// ChronicleProvider implements the Provider interface for Google Security
+// Operations (Chronicle). It inventories ingested log types, feeds and
+// forwarders through the SecOps API and reads per-log-type ingestion
+// statistics from Cloud Monitoring.
+type ChronicleProvider struct {
+	config        types.ProviderConfig
+	client        *http.Client
+	tokens        *googleTokenSource
+	projectID     string
+	instancePath  string
+	monitoringURL string
+	statsWindow   time.Duration
+}
+
+// ChronicleLogTypesResponse represents the logTypes list response
+type ChronicleLogTypesResponse struct {
+	LogTypes []struct {
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+		Golden      bool   `json:"golden"`
+	} `json:"logTypes"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// ChronicleFeedsResponse represents the feeds list response
+type ChronicleFeedsResponse struct {
+	Feeds         []ChronicleFeed `json:"feeds"`
+	NextPageToken string          `json:"nextPageToken"`
+}
+
+// ChronicleFeed represents a feed pulling or receiving logs of one log type
+type ChronicleFeed struct {
+	Name              string `json:"name"`
+	DisplayName       string `json:"displayName"`
+	State             string `json:"state"`
+	FailureMsg        string `json:"failureMsg"`
+	LastSuccessfulRun string `json:"lastSuccessfulRun"`
+	Details           struct {
+		LogType        string `json:"logType"`
+		FeedSourceType string `json:"feedSourceType"`
+	} `json:"details"`
+}
+
+// ChronicleForwardersResponse represents the forwarders list response
+type ChronicleForwardersResponse struct {
+	Forwarders []struct {
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+		State       string `json:"state"`
+		Config      struct {
+			UploadCompression bool `json:"uploadCompression"`
+			Metadata          struct {
+				AssetNamespace string           `json:"assetNamespace"`
+				Labels         []ChronicleLabel `json:"labels"`
+			} `json:"metadata"`
+		} `json:"config"`
+	} `json:"forwarders"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// ChronicleLabel is a forwarder metadata label
+type ChronicleLabel struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// MonitoringTimeSeriesResponse represents a Cloud Monitoring timeSeries.list response
+type MonitoringTimeSeriesResponse struct {
+	TimeSeries []struct {
+		Resource struct {
+			Labels map[string]string `json:"labels"`
+		} `json:"resource"`
+		Metric struct {
+			Labels map[string]string `json:"labels"`
+		} `json:"metric"`
+		Points []struct {
+			Interval struct {
+				EndTime string `json:"endTime"`
+			} `json:"interval"`
+			Value struct {
+				Int64Value  string  `json:"int64Value"`
+				DoubleValue float64 `json:"doubleValue"`
+			} `json:"value"`
+		} `json:"points"`
+	} `json:"timeSeries"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// chronicleIngestion summarizes one log type's ingestion over the stats window
+type chronicleIngestion struct {
+	records  int64
+	bytes    int64
+	lastSeen time.Time
+}
+
+const (
+	chronicleMonitoringURL = "https://monitoring.googleapis.com"
+	chronicleRecordsMetric = "chronicle.googleapis.com/ingestion/log/record_count"
+	chronicleBytesMetric   = "chronicle.googleapis.com/ingestion/log/bytes_count"
+	chronicleDefaultWindow = 24 * time.Hour
+	chronicleLogTypeLabel  = "log_type"
+)
+
+// NewChronicleProvider creates a new Chronicle provider. options.location and
+// options.instance_id identify the SecOps instance; options.project_id
+// defaults to the service account's project.
+func NewChronicleProvider(config types.ProviderConfig) (types.Provider, error) {
+	client, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &ChronicleProvider{
+		config:        config,
+		client:        client,
+		projectID:     config.Options["project_id"],
+		monitoringURL: strings.TrimSuffix(config.Options["monitoring_url"], "/"),
+		statsWindow:   chronicleDefaultWindow,
+	}
+
+	if config.Auth != nil && config.Auth.Type == "service_account" {
+		provider.tokens, err = newGoogleTokenSource(client, config.Auth, googleCloudPlatformScope)
+		if err != nil {
+			return nil, err
+		}
+		if provider.projectID == "" {
+			provider.projectID = provider.tokens.account.ProjectID
+		}
+	}
+
+	location, instance := config.Options["location"], config.Options["instance_id"]
+	if provider.projectID == "" || location == "" || instance == "" {
+		return nil, fmt.Errorf("chronicle provider requires options project_id, location and instance_id")
+	}
+	provider.instancePath = fmt.Sprintf("%s/v1alpha/projects/%s/locations/%s/instances/%s",
+		strings.TrimSuffix(config.Endpoint, "/"), url.PathEscape(provider.projectID), url.PathEscape(location), url.PathEscape(instance))
+
+	if provider.monitoringURL == "" {
+		provider.monitoringURL = chronicleMonitoringURL
+	}
+	if window := config.Options["stats_window"]; window != "" {
+		provider.statsWindow, err = time.ParseDuration(window)
+		if err != nil || provider.statsWindow <= 0 {
+			return nil, fmt.Errorf("invalid stats_window: %s", window)
+		}
+	}
+
+	return provider, nil
+}
+
+func (c *ChronicleProvider) Name() string {
+	return "chronicle"
+}
+
+func (c *ChronicleProvider) FetchDataViews(ctx context.Context) ([]types.DataSource, error) {
+	feeds, err := c.fetchFeeds(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch feeds: %w", err)
+	}
+
+	stats, statsAvailable, err := c.fetchIngestionStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ingestion statistics: %w", err)
+	}
+
+	logTypes, err := c.fetchLogTypes(ctx, feeds, stats, statsAvailable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch log types: %w", err)
+	}
+
+	forwarders, err := c.fetchForwarders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch forwarders: %w", err)
+	}
+
+	dataSources := []types.DataSource{}
+	dataSources = append(dataSources, logTypes...)
+	for _, feed := range feeds {
+		dataSources = append(dataSources, c.convertFeed(feed))
+	}
+	dataSources = append(dataSources, forwarders...)
+	return dataSources, nil
+}
+
+func (c *ChronicleProvider) fetchFeeds(ctx context.Context) ([]ChronicleFeed, error) {
+	feeds := []ChronicleFeed{}
+	pageToken := ""
+	for {
+		var resp ChronicleFeedsResponse
+		if err := c.getJSON(ctx, c.instancePath+"/feeds"+pageQuery(pageToken), &resp); err != nil {
+			return nil, err
+		}
+		feeds = append(feeds, resp.Feeds...)
+		if resp.NextPageToken == "" {
+			return feeds, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// fetchLogTypes lists log types that received data in the stats window or are
+// fed by a feed. Set options.all_log_types to include the full catalog.
+func (c *ChronicleProvider) fetchLogTypes(ctx context.Context, feeds []ChronicleFeed, stats map[string]chronicleIngestion, statsAvailable bool) ([]types.DataSource, error) {
+	fed := make(map[string]bool)
+	for _, feed := range feeds {
+		if feed.Details.LogType != "" {
+			fed[lastPathSegment(feed.Details.LogType)] = true
+		}
+	}
+	all := c.config.Options["all_log_types"] == "true"
+
+	dataSources := []types.DataSource{}
+	pageToken := ""
+	for {
+		var resp ChronicleLogTypesResponse
+		if err := c.getJSON(ctx, c.instancePath+"/logTypes"+pageQuery(pageToken), &resp); err != nil {
+			return nil, err
+		}
+
+		for _, logType := range resp.LogTypes {
+			id := lastPathSegment(logType.Name)
+			ingestion, ingested := stats[id]
+			if !all && !fed[id] && !ingested {
+				continue
+			}
+			dataSources = append(dataSources, c.convertLogType(id, logType.DisplayName, ingestion, statsAvailable))
+		}
+
+		if resp.NextPageToken == "" {
+			return dataSources, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+func (c *ChronicleProvider) convertLogType(id, displayName string, ingestion chronicleIngestion, statsAvailable bool) types.DataSource {
+	n := types.NewNormalizedFields()
+	status := "active"
+	metadata := map[string]interface{}{
+		"logType": id,
+	}
+
+	if statsAvailable {
+		window := c.statsWindow.String()
+		metadata["statsWindow"] = window
+		metadata["recordCount"] = ingestion.records
+		metadata["bytesCount"] = ingestion.bytes
+		n.IngestRateEPS = float64Ptr(float64(ingestion.records) / c.statsWindow.Seconds())
+		if !ingestion.lastSeen.IsZero() {
+			n.LastEventTime = timePtr(ingestion.lastSeen)
+		}
+		if ingestion.records == 0 {
+			status = "no_data"
+		}
+	}
+
+	title := displayName
+	if title == "" {
+		title = id
+	}
+	return types.DataSource{
+		ID:         "log-type:" + id,
+		Name:       id,
+		Title:      title,
+		Type:       "chronicle-log-type",
+		Status:     status,
+		Tags:       []string{"chronicle", "log-type"},
+		Normalized: n,
+		Metadata:   metadata,
+	}
+}
+
+func (c *ChronicleProvider) convertFeed(feed ChronicleFeed) types.DataSource {
+	state := feed.State
+	status := "active"
+	switch strings.ToUpper(state) {
+	case "INACTIVE":
+		status = "disabled"
+	case "FAILED":
+		status = "failed"
+	}
+
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(status != "disabled")
+
+	metadata := map[string]interface{}{
+		"state":          state,
+		"feedSourceType": feed.Details.FeedSourceType,
+	}
+	if feed.FailureMsg != "" {
+		metadata["failureMsg"] = feed.FailureMsg
+	}
+
+	ds := types.DataSource{
+		ID:         "feed:" + lastPathSegment(feed.Name),
+		Name:       feed.DisplayName,
+		Title:      feed.DisplayName,
+		Type:       "chronicle-feed",
+		Status:     status,
+		Tags:       []string{"chronicle", "feed"},
+		Normalized: n,
+		Metadata:   metadata,
+	}
+	if feed.LastSuccessfulRun != "" {
+		metadata["lastSuccessfulRun"] = feed.LastSuccessfulRun
+	}
+	if feed.Details.LogType != "" {
+		logType := lastPathSegment(feed.Details.LogType)
+		metadata["logType"] = logType
+		ds.Relations = []types.Relation{{Type: types.RelationRoutesTo, TargetID: "log-type:" + logType}}
+	}
+	return ds
+}
+
+func (c *ChronicleProvider) fetchForwarders(ctx context.Context) ([]types.DataSource, error) {
+	dataSources := []types.DataSource{}
+	pageToken := ""
+	for {
+		var resp ChronicleForwardersResponse
+		if err := c.getJSON(ctx, c.instancePath+"/forwarders"+pageQuery(pageToken), &resp); err != nil {
+			return nil, err
+		}
+
+		for _, forwarder := range resp.Forwarders {
+			labels := make(map[string]string, len(forwarder.Config.Metadata.Labels))
+			for _, label := range forwarder.Config.Metadata.Labels {
+				labels[label.Key] = label.Value
+			}
+			status := "active"
+			if forwarder.State != "" && !strings.EqualFold(forwarder.State, "ACTIVE") {
+				status = strings.ToLower(forwarder.State)
+			}
+
+			dataSources = append(dataSources, types.DataSource{
+				ID:         "forwarder:" + lastPathSegment(forwarder.Name),
+				Name:       forwarder.DisplayName,
+				Title:      forwarder.DisplayName,
+				Type:       "chronicle-forwarder",
+				Status:     status,
+				Tags:       []string{"chronicle", "forwarder"},
+				Normalized: types.NewNormalizedFields(),
+				Metadata: map[string]interface{}{
+					"state":             forwarder.State,
+					"assetNamespace":    forwarder.Config.Metadata.AssetNamespace,
+					"labels":            labels,
+					"uploadCompression": forwarder.Config.UploadCompression,
+				},
+			})
+		}
+
+		if resp.NextPageToken == "" {
+			return dataSources, nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// fetchIngestionStats reads record and byte counts per log type over the
+// stats window. Statistics are best effort: the second result is false if the
+// caller lacks Cloud Monitoring access or disabled them.
+func (c *ChronicleProvider) fetchIngestionStats(ctx context.Context) (map[string]chronicleIngestion, bool, error) {
+	stats := make(map[string]chronicleIngestion)
+	if c.config.Options["ingestion_stats"] == "false" {
+		return stats, false, nil
+	}
+
+	err := c.fetchMetric(ctx, chronicleRecordsMetric, func(logType string, value int64, end time.Time) {
+		s := stats[logType]
+		s.records += value
+		if value > 0 && end.After(s.lastSeen) {
+			s.lastSeen = end
+		}
+		stats[logType] = s
+	})
+	if isStatus(err, http.StatusForbidden) || isStatus(err, http.StatusNotFound) {
+		return stats, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = c.fetchMetric(ctx, chronicleBytesMetric, func(logType string, value int64, _ time.Time) {
+		s := stats[logType]
+		s.bytes += value
+		stats[logType] = s
+	})
+	if err != nil && !isStatus(err, http.StatusForbidden) && !isStatus(err, http.StatusNotFound) {
+		return nil, false, err
+	}
+	return stats, true, nil
+}
+
+// fetchMetric sums a Chronicle ingestion metric per log type in hourly buckets
+func (c *ChronicleProvider) fetchMetric(ctx context.Context, metric string, add func(logType string, value int64, end time.Time)) error {
+	end := time.Now().UTC()
+	start := end.Add(-c.statsWindow)
+	pageToken := ""
+	for {
+		params := url.Values{}
+		params.Set("filter", fmt.Sprintf("metric.type=%q", metric))
+		params.Set("interval.startTime", start.Format(time.RFC3339))
+		params.Set("interval.endTime", end.Format(time.RFC3339))
+		params.Set("aggregation.alignmentPeriod", "3600s")
+		params.Set("aggregation.perSeriesAligner", "ALIGN_SUM")
+		params.Set("aggregation.crossSeriesReducer", "REDUCE_SUM")
+		params.Set("aggregation.groupByFields", "resource.label."+chronicleLogTypeLabel)
+		if pageToken != "" {
+			params.Set("pageToken", pageToken)
+		}
+
+		var resp MonitoringTimeSeriesResponse
+		endpoint := fmt.Sprintf("%s/v3/projects/%s/timeSeries?%s", c.monitoringURL, url.PathEscape(c.projectID), params.Encode())
+		if err := c.getJSON(ctx, endpoint, &resp); err != nil {
+			return err
+		}
+
+		for _, series := range resp.TimeSeries {
+			logType := series.Resource.Labels[chronicleLogTypeLabel]
+			if logType == "" {
+				logType = series.Metric.Labels[chronicleLogTypeLabel]
+			}
+			if logType == "" {
+				continue
+			}
+			for _, point := range series.Points {
+				value, ok := parseInt64(point.Value.Int64Value)
+				if !ok {
+					value = int64(point.Value.DoubleValue)
+				}
+				pointEnd, _ := time.Parse(time.RFC3339Nano, point.Interval.EndTime)
+				add(logType, value, pointEnd)
+			}
+		}
+
+		if resp.NextPageToken == "" {
+			return nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// pageQuery builds the pageSize/pageToken query used by Google list APIs
+func pageQuery(pageToken string) string {
+	params := url.Values{}
+	params.Set("pageSize", "1000")
+	if pageToken != "" {
+		params.Set("pageToken", pageToken)
+	}
+	return "?" + params.Encode()
+}
+
+// lastPathSegment returns the ID from a Google resource name such as
+// "projects/p/locations/us/instances/i/feeds/123"
+func lastPathSegment(name string) string {
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// getJSON performs an authenticated GET against a Google API
+func (c *ChronicleProvider) getJSON(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if err := c.addAuth(ctx, req); err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return readJSONResponse(resp, "chronicle", out)
+}
+
+func (c *ChronicleProvider) addAuth(ctx context.Context, req *http.Request) error {
+	if c.tokens != nil {
+		token, err := c.tokens.Token(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to obtain access token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+	if c.config.Auth != nil && c.config.Auth.Type == "bearer" {
+		req.Header.Set("Authorization", "Bearer "+c.config.Auth.Token)
+	}
+	return nil
+}
+
+func (c *ChronicleProvider) ValidateConnection(ctx context.Context) error {
+	var resp ChronicleFeedsResponse
+	if err := c.getJSON(ctx, c.instancePath+"/feeds?pageSize=1", &resp); err != nil {
+		return fmt.Errorf("failed to connect to Chronicle: %w", err)
+	}
+	return nil
+}
+
+func (c *ChronicleProvider) GetCapabilities() types.ProviderCapabilities {
+	return types.ProviderCapabilities{
+		SupportsRealTimeQueries: true,
+		SupportsHistoricalData:  true,
+		SupportedDataTypes:      []string{"chronicle-log-type", "chronicle-feed", "chronicle-forwarder"},
+		RequiresAuthentication:  true,
+	}
+}
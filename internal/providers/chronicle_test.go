@@ -0,0 +1,200 @@
+package providers
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+// writeServiceAccountKey writes a service-account key to a temp dir and
+// returns its path relative to the working directory, as config requires
+func writeServiceAccountKey(t *testing.T, key *rsa.PrivateKey, tokenURI string) string {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account := map[string]string{
+		"type":           "service_account",
+		"project_id":     "secops-project",
+		"private_key_id": "kid-1",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"client_email":   "logfiend@secops-project.iam.gserviceaccount.com",
+		"token_uri":      tokenURI,
+	}
+	data, _ := json.Marshal(account)
+
+	path := filepath.Join(t.TempDir(), "key.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	wd, _ := os.Getwd()
+	rel, err := filepath.Rel(wd, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rel
+}
+
+// verifyJWT checks an RS256 assertion against the public key and returns its claims
+func verifyJWT(t *testing.T, assertion string, pub *rsa.PublicKey) map[string]interface{} {
+	t.Helper()
+	parts := strings.Split(assertion, ".")
+	if len(parts) != 3 {
+		t.Fatalf("malformed JWT %q", assertion)
+	}
+	signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature); err != nil {
+		t.Fatalf("JWT signature invalid: %v", err)
+	}
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	var claims map[string]interface{}
+	json.Unmarshal(payload, &claims)
+	return claims
+}
+
+func TestChronicleFetchDataViews(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenRequests := 0
+	instance := "/v1alpha/projects/secops-project/locations/eu/instances/cust-1"
+	responses := map[string]string{
+		instance + "/feeds": `{"feeds":[
+			{"name":"projects/p/locations/eu/instances/i/feeds/f1","displayName":"Okta","state":"ACTIVE",
+			 "lastSuccessfulRun":"2024-05-01T10:00:00Z","details":{"logType":"projects/p/locations/eu/instances/i/logTypes/OKTA","feedSourceType":"API"}},
+			{"name":"projects/p/locations/eu/instances/i/feeds/f2","displayName":"Old S3","state":"FAILED","failureMsg":"access denied",
+			 "details":{"logType":"projects/p/locations/eu/instances/i/logTypes/AWS_CLOUDTRAIL","feedSourceType":"AMAZON_S3"}}]}`,
+		instance + "/logTypes": `{"logTypes":[
+			{"name":"projects/p/locations/eu/instances/i/logTypes/OKTA","displayName":"Okta"},
+			{"name":"projects/p/locations/eu/instances/i/logTypes/AWS_CLOUDTRAIL","displayName":"AWS CloudTrail"},
+			{"name":"projects/p/locations/eu/instances/i/logTypes/WINEVTLOG","displayName":"Windows Event Log"},
+			{"name":"projects/p/locations/eu/instances/i/logTypes/UNUSED","displayName":"Unused"}]}`,
+		instance + "/forwarders": `{"forwarders":[{"name":"projects/p/locations/eu/instances/i/forwarders/fw1","displayName":"dc-forwarder","state":"ACTIVE",
+			"config":{"metadata":{"assetNamespace":"corp","labels":[{"key":"site","value":"ams"}]}}}]}`,
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			tokenRequests++
+			r.ParseForm()
+			if r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+				http.Error(w, "bad grant", http.StatusBadRequest)
+				return
+			}
+			claims := verifyJWT(t, r.Form.Get("assertion"), &key.PublicKey)
+			if claims["aud"] != server.URL+"/token" || claims["iss"] != "logfiend@secops-project.iam.gserviceaccount.com" {
+				t.Errorf("unexpected JWT claims %v", claims)
+			}
+			w.Write([]byte(`{"access_token":"ya29.test","expires_in":3600,"token_type":"Bearer"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer ya29.test" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == "/v3/projects/secops-project/timeSeries" {
+			value := "600"
+			if strings.Contains(r.URL.Query().Get("filter"), "bytes_count") {
+				value = "1024"
+			}
+			w.Write([]byte(`{"timeSeries":[
+				{"resource":{"labels":{"log_type":"OKTA"}},"points":[
+					{"interval":{"endTime":"2024-05-01T11:00:00Z"},"value":{"int64Value":"` + value + `"}},
+					{"interval":{"endTime":"2024-05-01T10:00:00Z"},"value":{"int64Value":"` + value + `"}}]},
+				{"resource":{"labels":{"log_type":"WINEVTLOG"}},"points":[
+					{"interval":{"endTime":"2024-05-01T09:00:00Z"},"value":{"int64Value":"` + value + `"}}]}]}`))
+			return
+		}
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	provider, err := NewChronicleProvider(types.ProviderConfig{
+		Type:     "chronicle",
+		Endpoint: server.URL,
+		Options: map[string]string{
+			"location":       "eu",
+			"instance_id":    "cust-1",
+			"monitoring_url": server.URL,
+			"stats_window":   "1h",
+		},
+		Auth: &types.AuthConfig{
+			Type:            "service_account",
+			CredentialsFile: writeServiceAccountKey(t, key, "https://oauth2.googleapis.com/token"),
+			TokenURL:        server.URL + "/token",
+		},
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sources, err := provider.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+	if tokenRequests != 1 {
+		t.Errorf("expected the access token to be cached, got %d token requests", tokenRequests)
+	}
+
+	byID := make(map[string]types.DataSource)
+	for _, ds := range sources {
+		byID[ds.ID] = ds
+	}
+	if _, ok := byID["log-type:UNUSED"]; ok {
+		t.Error("log types without feeds or ingestion should be skipped")
+	}
+	if len(byID) != 6 {
+		t.Fatalf("expected 6 data sources, got %d", len(byID))
+	}
+
+	okta := byID["log-type:OKTA"]
+	if okta.Status != "active" || okta.Metadata["recordCount"] != int64(1200) || okta.Metadata["bytesCount"] != int64(2048) {
+		t.Errorf("unexpected OKTA log type %+v", okta)
+	}
+	if okta.Normalized.IngestRateEPS == nil || *okta.Normalized.IngestRateEPS != 1200.0/3600 {
+		t.Errorf("unexpected ingest rate %v", okta.Normalized.IngestRateEPS)
+	}
+	if want := time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC); okta.Normalized.LastEventTime == nil || !okta.Normalized.LastEventTime.Equal(want) {
+		t.Errorf("unexpected last event time %v", okta.Normalized.LastEventTime)
+	}
+	if ds := byID["log-type:AWS_CLOUDTRAIL"]; ds.Status != "no_data" {
+		t.Errorf("fed log type without ingestion should be no_data, got %s", ds.Status)
+	}
+
+	failed := byID["feed:f2"]
+	if failed.Status != "failed" || len(failed.Relations) != 1 || failed.Relations[0].TargetID != "log-type:AWS_CLOUDTRAIL" {
+		t.Errorf("unexpected failed feed %+v", failed)
+	}
+	if ds := byID["feed:f1"]; ds.Metadata["feedSourceType"] != "API" || ds.Metadata["lastSuccessfulRun"] != "2024-05-01T10:00:00Z" {
+		t.Errorf("unexpected okta feed %+v", ds)
+	}
+	if ds := byID["forwarder:fw1"]; ds.Metadata["assetNamespace"] != "corp" {
+		t.Errorf("unexpected forwarder %+v", ds)
+	}
+}
@@ -0,0 +1,327 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+// CloudWatchProvider implements the Provider interface for Amazon CloudWatch
+// Logs. It lists log groups with their metric and subscription filters in
+// every configured region through the CloudWatch Logs JSON API.
+type CloudWatchProvider struct {
+	config  types.ProviderConfig
+	client  *http.Client
+	regions []string
+	now     func() time.Time
+}
+
+// CloudWatchLogGroup represents a log group from DescribeLogGroups
+type CloudWatchLogGroup struct {
+	LogGroupName         string `json:"logGroupName"`
+	ARN                  string `json:"arn"`
+	CreationTime         int64  `json:"creationTime"`
+	RetentionInDays      int    `json:"retentionInDays"`
+	MetricFilterCount    int    `json:"metricFilterCount"`
+	StoredBytes          int64  `json:"storedBytes"`
+	KMSKeyID             string `json:"kmsKeyId"`
+	DataProtectionStatus string `json:"dataProtectionStatus"`
+	LogGroupClass        string `json:"logGroupClass"`
+}
+
+// CloudWatchMetricFilter represents a metric filter from DescribeMetricFilters
+type CloudWatchMetricFilter struct {
+	FilterName            string `json:"filterName"`
+	FilterPattern         string `json:"filterPattern"`
+	LogGroupName          string `json:"logGroupName"`
+	MetricTransformations []struct {
+		MetricName      string `json:"metricName"`
+		MetricNamespace string `json:"metricNamespace"`
+	} `json:"metricTransformations"`
+}
+
+// CloudWatchSubscriptionFilter represents a subscription filter from
+// DescribeSubscriptionFilters
+type CloudWatchSubscriptionFilter struct {
+	FilterName     string `json:"filterName"`
+	FilterPattern  string `json:"filterPattern"`
+	DestinationARN string `json:"destinationArn"`
+	Distribution   string `json:"distribution"`
+}
+
+const cloudWatchTargetPrefix = "Logs_20140328."
+
+// NewCloudWatchProvider creates a new CloudWatch Logs provider. options.regions
+// lists the regions to inventory (default auth.region). Without an endpoint
+// each region is sent to its own regional endpoint. A configured endpoint
+// receives every request: an AWS one, such as a FIPS endpoint, serves a single
+// region, while LocalStack and other emulators serve them all.
+func NewCloudWatchProvider(config types.ProviderConfig) (types.Provider, error) {
+	client, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &CloudWatchProvider{
+		config: config,
+		client: client,
+		now:    time.Now,
+	}
+
+	for _, region := range strings.Split(config.Options["regions"], ",") {
+		if region = strings.TrimSpace(region); region != "" {
+			provider.regions = append(provider.regions, region)
+		}
+	}
+	if len(provider.regions) == 0 && config.Auth != nil && config.Auth.Region != "" {
+		provider.regions = []string{config.Auth.Region}
+	}
+	if len(provider.regions) == 0 {
+		return nil, fmt.Errorf("cloudwatch requires auth.region or options.regions")
+	}
+	if parsed, err := url.Parse(config.Endpoint); err == nil && isAWSHost(parsed.Hostname()) && len(provider.regions) > 1 {
+		return nil, fmt.Errorf("cloudwatch endpoint %s serves one region; remove it to inventory %s", parsed.Hostname(), strings.Join(provider.regions, ", "))
+	}
+
+	return provider, nil
+}
+
+func (c *CloudWatchProvider) Name() string {
+	return "cloudwatch"
+}
+
+func (c *CloudWatchProvider) FetchDataViews(ctx context.Context) ([]types.DataSource, error) {
+	credentials, err := resolveAWSCredentials(ctx, c.client, c.config.Auth, c.config.Options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve AWS credentials: %w", err)
+	}
+
+	dataSources := []types.DataSource{}
+	for _, region := range c.regions {
+		regionSources, err := c.fetchRegion(ctx, c.signer(credentials, region), region)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inventory %s: %w", region, err)
+		}
+		dataSources = append(dataSources, regionSources...)
+	}
+	return dataSources, nil
+}
+
+func (c *CloudWatchProvider) fetchRegion(ctx context.Context, signer *sigV4Signer, region string) ([]types.DataSource, error) {
+	var groups []CloudWatchLogGroup
+	err := c.paginate(ctx, signer, region, "DescribeLogGroups", map[string]interface{}{"limit": 50}, func(page json.RawMessage) error {
+		var resp struct {
+			LogGroups []CloudWatchLogGroup `json:"logGroups"`
+		}
+		if err := json.Unmarshal(page, &resp); err != nil {
+			return err
+		}
+		groups = append(groups, resp.LogGroups...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch log groups: %w", err)
+	}
+
+	// Metric filters are listed account-wide in one pass
+	metricFilters := make(map[string][]CloudWatchMetricFilter)
+	err = c.paginate(ctx, signer, region, "DescribeMetricFilters", map[string]interface{}{"limit": 50}, func(page json.RawMessage) error {
+		var resp struct {
+			MetricFilters []CloudWatchMetricFilter `json:"metricFilters"`
+		}
+		if err := json.Unmarshal(page, &resp); err != nil {
+			return err
+		}
+		for _, filter := range resp.MetricFilters {
+			metricFilters[filter.LogGroupName] = append(metricFilters[filter.LogGroupName], filter)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch metric filters: %w", err)
+	}
+
+	includeSubscriptions := c.config.Options["subscription_filters"] != "false"
+	dataSources := make([]types.DataSource, 0, len(groups))
+	for _, group := range groups {
+		var subscriptions []CloudWatchSubscriptionFilter
+		if includeSubscriptions {
+			body := map[string]interface{}{"logGroupName": group.LogGroupName}
+			err := c.paginate(ctx, signer, region, "DescribeSubscriptionFilters", body, func(page json.RawMessage) error {
+				var resp struct {
+					SubscriptionFilters []CloudWatchSubscriptionFilter `json:"subscriptionFilters"`
+				}
+				if err := json.Unmarshal(page, &resp); err != nil {
+					return err
+				}
+				subscriptions = append(subscriptions, resp.SubscriptionFilters...)
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch subscription filters for %s: %w", group.LogGroupName, err)
+			}
+		}
+		dataSources = append(dataSources, c.convertLogGroup(region, group, metricFilters[group.LogGroupName], subscriptions))
+	}
+	return dataSources, nil
+}
+
+func (c *CloudWatchProvider) convertLogGroup(region string, group CloudWatchLogGroup, metricFilters []CloudWatchMetricFilter, subscriptions []CloudWatchSubscriptionFilter) types.DataSource {
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(true)
+	n.SizeBytes = int64Ptr(group.StoredBytes)
+	// Without a retention policy CloudWatch keeps events forever
+	if group.RetentionInDays > 0 {
+		n.RetentionDays = intPtr(group.RetentionInDays)
+	}
+
+	metrics := make([]map[string]interface{}, 0, len(metricFilters))
+	for _, filter := range metricFilters {
+		names := make([]string, 0, len(filter.MetricTransformations))
+		for _, transformation := range filter.MetricTransformations {
+			names = append(names, transformation.MetricNamespace+"/"+transformation.MetricName)
+		}
+		metrics = append(metrics, map[string]interface{}{
+			"name":    filter.FilterName,
+			"pattern": filter.FilterPattern,
+			"metrics": names,
+		})
+	}
+
+	subscriptionFilters := make([]map[string]interface{}, 0, len(subscriptions))
+	for _, filter := range subscriptions {
+		subscriptionFilters = append(subscriptionFilters, map[string]interface{}{
+			"name":           filter.FilterName,
+			"pattern":        filter.FilterPattern,
+			"destinationArn": filter.DestinationARN,
+			"distribution":   filter.Distribution,
+		})
+	}
+
+	metadata := map[string]interface{}{
+		"region":              region,
+		"arn":                 group.ARN,
+		"storedBytes":         group.StoredBytes,
+		"metricFilters":       metrics,
+		"subscriptionFilters": subscriptionFilters,
+	}
+	if group.KMSKeyID != "" {
+		metadata["kmsKeyId"] = group.KMSKeyID
+	}
+	if group.LogGroupClass != "" {
+		metadata["logGroupClass"] = group.LogGroupClass
+	}
+	if group.DataProtectionStatus != "" {
+		metadata["dataProtectionStatus"] = group.DataProtectionStatus
+	}
+	if group.CreationTime > 0 {
+		metadata["creationTime"] = unixMillis(group.CreationTime).Format(time.RFC3339)
+	}
+
+	tags := []string{"cloudwatch", "log-group", "region:" + region}
+	if group.KMSKeyID != "" {
+		tags = append(tags, "kms-encrypted")
+	}
+
+	return types.DataSource{
+		ID:         "log-group:" + region + ":" + group.LogGroupName,
+		Name:       group.LogGroupName,
+		Title:      group.LogGroupName,
+		Type:       "cloudwatch-log-group",
+		Status:     "active",
+		Tags:       tags,
+		Normalized: n,
+		Metadata:   metadata,
+	}
+}
+
+// paginate calls a CloudWatch Logs action until nextToken runs out
+func (c *CloudWatchProvider) paginate(ctx context.Context, signer *sigV4Signer, region, action string, body map[string]interface{}, handle func(json.RawMessage) error) error {
+	request := make(map[string]interface{}, len(body)+1)
+	for key, value := range body {
+		request[key] = value
+	}
+
+	for {
+		var page json.RawMessage
+		if err := c.call(ctx, signer, region, action, request, &page); err != nil {
+			return err
+		}
+		if err := handle(page); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		var next struct {
+			NextToken string `json:"nextToken"`
+		}
+		if err := json.Unmarshal(page, &next); err != nil || next.NextToken == "" {
+			return nil
+		}
+		request["nextToken"] = next.NextToken
+	}
+}
+
+// call performs one signed CloudWatch Logs JSON API request
+func (c *CloudWatchProvider) call(ctx context.Context, signer *sigV4Signer, region, action string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint(region), bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", cloudWatchTargetPrefix+action)
+	signer.sign(req, payload)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return readJSONResponse(resp, "cloudwatch", out)
+}
+
+// endpoint returns the configured endpoint, or the regional service URL in
+// the region's partition
+func (c *CloudWatchProvider) endpoint(region string) string {
+	if c.config.Endpoint != "" {
+		return strings.TrimSuffix(c.config.Endpoint, "/") + "/"
+	}
+	return "https://logs." + region + "." + awsDNSSuffix(region) + "/"
+}
+
+func (c *CloudWatchProvider) signer(credentials awsCredentials, region string) *sigV4Signer {
+	return &sigV4Signer{credentials: credentials, region: region, service: "logs", now: c.now}
+}
+
+func (c *CloudWatchProvider) ValidateConnection(ctx context.Context) error {
+	credentials, err := resolveAWSCredentials(ctx, c.client, c.config.Auth, c.config.Options)
+	if err != nil {
+		return fmt.Errorf("failed to connect to CloudWatch Logs: %w", err)
+	}
+	region := c.regions[0]
+	if err := c.call(ctx, c.signer(credentials, region), region, "DescribeLogGroups", map[string]interface{}{"limit": 1}, nil); err != nil {
+		return fmt.Errorf("failed to connect to CloudWatch Logs: %w", err)
+	}
+	return nil
+}
+
+func (c *CloudWatchProvider) GetCapabilities() types.ProviderCapabilities {
+	return types.ProviderCapabilities{
+		SupportsRealTimeQueries: false,
+		SupportsHistoricalData:  true,
+		SupportedDataTypes:      []string{"cloudwatch-log-group"},
+		RequiresAuthentication:  true,
+	}
+}
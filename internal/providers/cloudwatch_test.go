@@ -0,0 +1,224 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+func TestCloudWatchFetchDataViews(t *testing.T) {
+	regions := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+
+		// STS AssumeRole, signed with the base credentials
+		if r.Header.Get("X-Amz-Target") == "" {
+			if !strings.Contains(authorization, "Credential=AKIDBASE/") || !strings.Contains(authorization, "/sts/aws4_request") {
+				http.Error(w, "bad signature", http.StatusForbidden)
+				return
+			}
+			if !strings.Contains(string(body), "Action=AssumeRole") || !strings.Contains(string(body), "RoleArn=arn%3Aaws%3Aiam%3A%3A123456789012%3Arole%2Finventory") {
+				http.Error(w, "bad request", http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/"><AssumeRoleResult><Credentials>
+				<AccessKeyId>ASIAROLE</AccessKeyId><SecretAccessKey>role-secret</SecretAccessKey><SessionToken>role-session</SessionToken>
+				<Expiration>2030-01-01T00:00:00Z</Expiration></Credentials></AssumeRoleResult></AssumeRoleResponse>`))
+			return
+		}
+
+		if !strings.Contains(authorization, "Credential=ASIAROLE/") || r.Header.Get("X-Amz-Security-Token") != "role-session" {
+			http.Error(w, `{"__type":"UnrecognizedClientException"}`, http.StatusBadRequest)
+			return
+		}
+		region := strings.Split(strings.SplitN(authorization, "Credential=ASIAROLE/", 2)[1], "/")[1]
+		regions[region]++
+
+		var req map[string]interface{}
+		json.Unmarshal(body, &req)
+		switch r.Header.Get("X-Amz-Target") {
+		case "Logs_20140328.DescribeLogGroups":
+			if region == "eu-west-1" {
+				w.Write([]byte(`{"logGroups":[{"logGroupName":"/aws/lambda/billing","arn":"arn:aws:logs:eu-west-1:123456789012:log-group:/aws/lambda/billing:*","storedBytes":2048}]}`))
+				return
+			}
+			if req["nextToken"] == nil {
+				w.Write([]byte(`{"logGroups":[{"logGroupName":"/aws/vpc/flow","retentionInDays":90,"storedBytes":1073741824,"kmsKeyId":"arn:aws:kms:us-east-1:123456789012:key/abc","logGroupClass":"STANDARD","creationTime":1700000000000}],"nextToken":"page2"}`))
+				return
+			}
+			w.Write([]byte(`{"logGroups":[{"logGroupName":"/aws/cloudtrail","retentionInDays":365,"storedBytes":10}]}`))
+		case "Logs_20140328.DescribeMetricFilters":
+			if region == "us-east-1" {
+				w.Write([]byte(`{"metricFilters":[{"filterName":"rejects","filterPattern":"[action=REJECT]","logGroupName":"/aws/vpc/flow","metricTransformations":[{"metricName":"Rejects","metricNamespace":"VPC"}]}]}`))
+				return
+			}
+			w.Write([]byte(`{"metricFilters":[]}`))
+		case "Logs_20140328.DescribeSubscriptionFilters":
+			if req["logGroupName"] == "/aws/cloudtrail" {
+				w.Write([]byte(`{"subscriptionFilters":[{"filterName":"to-siem","filterPattern":"","destinationArn":"arn:aws:firehose:us-east-1:123456789012:deliverystream/siem"}]}`))
+				return
+			}
+			w.Write([]byte(`{"subscriptionFilters":[]}`))
+		default:
+			http.Error(w, "unknown target", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewCloudWatchProvider(types.ProviderConfig{
+		Type:     "cloudwatch",
+		Endpoint: server.URL,
+		Auth: &types.AuthConfig{
+			Type:            "aws_sigv4",
+			AccessKeyID:     "AKIDBASE",
+			SecretAccessKey: "base-secret",
+			Region:          "us-east-1",
+		},
+		Options: map[string]string{
+			"regions":      "us-east-1, eu-west-1",
+			"role_arn":     "arn:aws:iam::123456789012:role/inventory",
+			"sts_endpoint": server.URL,
+		},
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, err := provider.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+	if regions["us-east-1"] == 0 || regions["eu-west-1"] == 0 {
+		t.Errorf("expected requests signed for both regions, got %v", regions)
+	}
+
+	byID := make(map[string]types.DataSource)
+	for _, ds := range sources {
+		byID[ds.ID] = ds
+	}
+	if len(byID) != 3 {
+		t.Fatalf("expected 3 log groups, got %d", len(byID))
+	}
+
+	flow := byID["log-group:us-east-1:/aws/vpc/flow"]
+	if flow.Normalized.RetentionDays == nil || *flow.Normalized.RetentionDays != 90 || *flow.Normalized.SizeBytes != 1073741824 {
+		t.Errorf("unexpected flow log group %+v", flow.Normalized)
+	}
+	if flow.Metadata["kmsKeyId"] == nil || len(flow.Metadata["metricFilters"].([]map[string]interface{})) != 1 {
+		t.Errorf("unexpected flow log group metadata %+v", flow.Metadata)
+	}
+	trail := byID["log-group:us-east-1:/aws/cloudtrail"]
+	if subs := trail.Metadata["subscriptionFilters"].([]map[string]interface{}); len(subs) != 1 || !strings.Contains(subs[0]["destinationArn"].(string), "firehose") {
+		t.Errorf("unexpected subscription filters %+v", trail.Metadata["subscriptionFilters"])
+	}
+	if ds := byID["log-group:eu-west-1:/aws/lambda/billing"]; ds.Normalized.RetentionDays != nil {
+		t.Errorf("log group without retention policy should have no retention_days")
+	}
+}
+
+func TestBaseAWSCredentials(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_PROFILE", "")
+
+	path := filepath.Join(t.TempDir(), "credentials")
+	os.WriteFile(path, []byte("[default]\naws_access_key_id = AKIDDEFAULT\naws_secret_access_key = default-secret\n\n[audit]\naws_access_key_id=AKIDAUDIT\naws_secret_access_key=audit-secret\naws_session_token=audit-token\n"), 0600)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", path)
+
+	credentials, err := baseAWSCredentials(&types.AuthConfig{Region: "us-east-1"}, map[string]string{"profile": "audit"})
+	if err != nil || credentials.AccessKeyID != "AKIDAUDIT" || credentials.SessionToken != "audit-token" {
+		t.Errorf("unexpected shared file credentials %+v, %v", credentials, err)
+	}
+	if _, err := baseAWSCredentials(nil, map[string]string{"profile": "missing"}); err == nil {
+		t.Error("expected an error for a missing profile")
+	}
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDENV")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "env-secret")
+	credentials, err = baseAWSCredentials(nil, nil)
+	if err != nil || credentials.AccessKeyID != "AKIDENV" {
+		t.Errorf("environment should take precedence over the shared file, got %+v", credentials)
+	}
+
+	credentials, _ = baseAWSCredentials(&types.AuthConfig{AccessKeyID: "AKIDSTATIC", SecretAccessKey: "static"}, nil)
+	if credentials.AccessKeyID != "AKIDSTATIC" {
+		t.Errorf("static keys should take precedence, got %+v", credentials)
+	}
+}
+
+func TestCloudWatchEndpoint(t *testing.T) {
+	newProvider := func(endpoint, regions string) (*CloudWatchProvider, error) {
+		provider, err := NewCloudWatchProvider(types.ProviderConfig{
+			Type:     "cloudwatch",
+			Endpoint: endpoint,
+			Auth:     &types.AuthConfig{Type: "aws_sigv4"},
+			Options:  map[string]string{"regions": regions},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return provider.(*CloudWatchProvider), nil
+	}
+
+	regional, err := newProvider("", "us-east-1,cn-north-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := regional.endpoint("us-east-1"); got != "https://logs.us-east-1.amazonaws.com/" {
+		t.Errorf("unexpected regional endpoint %s", got)
+	}
+	if got := regional.endpoint("cn-north-1"); got != "https://logs.cn-north-1.amazonaws.com.cn/" {
+		t.Errorf("unexpected China endpoint %s", got)
+	}
+
+	fips, err := newProvider("https://logs-fips.us-east-1.amazonaws.com", "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fips.endpoint("us-east-1"); got != "https://logs-fips.us-east-1.amazonaws.com/" {
+		t.Errorf("a configured AWS endpoint should be honored, got %s", got)
+	}
+	for _, endpoint := range []string{"https://logs-fips.us-east-1.amazonaws.com", "https://logs.cn-north-1.amazonaws.com.cn"} {
+		if _, err := newProvider(endpoint, "us-east-1,cn-north-1"); err == nil {
+			t.Errorf("%s: expected an AWS endpoint with several regions to be rejected", endpoint)
+		}
+	}
+	if _, err := newProvider("http://localhost:4566", "us-east-1,eu-west-1"); err != nil {
+		t.Errorf("an emulator should serve every region: %v", err)
+	}
+}
+
+func TestAssumeRoleRegion(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDENV")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "env-secret")
+
+	var scope string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scope = strings.SplitN(r.Header.Get("Authorization"), "Credential=AKIDENV/", 2)[1]
+		w.Write([]byte(`<AssumeRoleResponse><AssumeRoleResult><Credentials>
+			<AccessKeyId>ASIAROLE</AccessKeyId><SecretAccessKey>role-secret</SecretAccessKey><SessionToken>role-session</SessionToken>
+			</Credentials></AssumeRoleResult></AssumeRoleResponse>`))
+	}))
+	defer server.Close()
+
+	// No auth block: the region comes from options.regions, then defaults
+	for regions, want := range map[string]string{"eu-west-1, us-east-1": "/eu-west-1/sts/", "": "/us-east-1/sts/"} {
+		options := map[string]string{"role_arn": "arn:aws:iam::123456789012:role/inventory", "sts_endpoint": server.URL, "regions": regions}
+		if _, err := resolveAWSCredentials(context.Background(), server.Client(), nil, options); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(scope, want) {
+			t.Errorf("regions %q: expected STS signed with %s, got %s", regions, want, scope)
+		}
+	}
+}
@@ -0,0 +1,600 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+// CriblProvider implements the Provider interface for Cribl Stream. It lists
+// sources, destinations, routes and pipelines per worker group and traces
+// each source through the routing table to the destinations it reaches.
+type CriblProvider struct {
+	config  types.ProviderConfig
+	client  *http.Client
+	baseURL string
+
+	mu    sync.Mutex
+	token cachedToken
+}
+
+// CriblListResponse is the envelope of Cribl list endpoints
+type CriblListResponse struct {
+	Count int               `json:"count"`
+	Items []json.RawMessage `json:"items"`
+}
+
+// CriblGroup represents a worker group (or fleet)
+type CriblGroup struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	WorkerCount int    `json:"workerCount"`
+	IsFleet     bool   `json:"isFleet"`
+}
+
+// CriblInput represents a source
+type CriblInput struct {
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	Disabled     bool   `json:"disabled"`
+	Description  string `json:"description"`
+	Pipeline     string `json:"pipeline"`
+	SendToRoutes *bool  `json:"sendToRoutes"`
+	Connections  []struct {
+		Output   string `json:"output"`
+		Pipeline string `json:"pipeline"`
+	} `json:"connections"`
+}
+
+// CriblOutput represents a destination
+type CriblOutput struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	Disabled    bool   `json:"disabled"`
+	Description string `json:"description"`
+	DefaultID   string `json:"defaultId"`
+	Pipeline    string `json:"pipeline"`
+}
+
+// CriblRouteTable represents a routing table
+type CriblRouteTable struct {
+	ID     string       `json:"id"`
+	Routes []CriblRoute `json:"routes"`
+}
+
+// CriblRoute represents a single route
+type CriblRoute struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Filter      string `json:"filter"`
+	Pipeline    string `json:"pipeline"`
+	Output      string `json:"output"`
+	Final       bool   `json:"final"`
+	Disabled    bool   `json:"disabled"`
+	Description string `json:"description"`
+}
+
+// CriblPipeline represents a pipeline
+type CriblPipeline struct {
+	ID   string `json:"id"`
+	Conf struct {
+		Description string `json:"description"`
+		Functions   []struct {
+			ID       string `json:"id"`
+			Disabled bool   `json:"disabled"`
+		} `json:"functions"`
+	} `json:"conf"`
+}
+
+// criblRouteMatch says whether a route filter applies to a source
+type criblRouteMatch int
+
+const (
+	criblMatchNone criblRouteMatch = iota
+	criblMatchMaybe
+	criblMatchAlways
+)
+
+// criblInputIDPattern finds __inputId comparisons in route filters
+var criblInputIDPattern = regexp.MustCompile(`__inputId\s*(==|===|\.startsWith\()\s*['"]([^'"]+)['"]`)
+
+// NewCriblProvider creates a new Cribl Stream provider. options.worker_groups
+// restricts the inventory to a comma-separated list of groups.
+func NewCriblProvider(config types.ProviderConfig) (types.Provider, error) {
+	client, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CriblProvider{
+		config:  config,
+		client:  client,
+		baseURL: strings.TrimSuffix(config.Endpoint, "/") + "/api/v1",
+	}, nil
+}
+
+func (c *CriblProvider) Name() string {
+	return "cribl"
+}
+
+func (c *CriblProvider) FetchDataViews(ctx context.Context) ([]types.DataSource, error) {
+	groups, err := c.fetchGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch worker groups: %w", err)
+	}
+
+	dataSources := []types.DataSource{}
+	for _, group := range groups {
+		groupSources, err := c.fetchGroup(ctx, group)
+		if err != nil {
+			name := group
+			if name == "" {
+				name = "single instance"
+			}
+			return nil, fmt.Errorf("failed to inventory worker group %s: %w", name, err)
+		}
+		dataSources = append(dataSources, groupSources...)
+	}
+	return dataSources, nil
+}
+
+// fetchGroups lists worker groups on a leader. A single-instance deployment
+// has no groups endpoint and is inventoried as the unnamed group "".
+func (c *CriblProvider) fetchGroups(ctx context.Context) ([]string, error) {
+	if selected := c.config.Options["worker_groups"]; selected != "" {
+		var groups []string
+		for _, group := range strings.Split(selected, ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				groups = append(groups, group)
+			}
+		}
+		return groups, nil
+	}
+
+	var resp CriblListResponse
+	err := c.getJSON(ctx, "/master/groups", &resp)
+	if isStatus(err, http.StatusNotFound) {
+		return []string{""}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]string, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		var group CriblGroup
+		if err := json.Unmarshal(item, &group); err != nil {
+			return nil, fmt.Errorf("failed to decode worker group: %w", err)
+		}
+		groups = append(groups, group.ID)
+	}
+	return groups, nil
+}
+
+func (c *CriblProvider) fetchGroup(ctx context.Context, group string) ([]types.DataSource, error) {
+	prefix := ""
+	if group != "" {
+		prefix = "/m/" + group
+	}
+
+	var inputs []CriblInput
+	if err := c.list(ctx, prefix+"/system/inputs", &inputs); err != nil {
+		return nil, fmt.Errorf("failed to fetch sources: %w", err)
+	}
+	var outputs []CriblOutput
+	if err := c.list(ctx, prefix+"/system/outputs", &outputs); err != nil {
+		return nil, fmt.Errorf("failed to fetch destinations: %w", err)
+	}
+	var tables []CriblRouteTable
+	if err := c.list(ctx, prefix+"/routes", &tables); err != nil {
+		return nil, fmt.Errorf("failed to fetch routes: %w", err)
+	}
+	var pipelines []CriblPipeline
+	if err := c.list(ctx, prefix+"/pipelines", &pipelines); err != nil {
+		return nil, fmt.Errorf("failed to fetch pipelines: %w", err)
+	}
+
+	// The "default" destination forwards to the output named by defaultId
+	defaultOutput := "default"
+	for _, output := range outputs {
+		if output.ID == "default" && output.DefaultID != "" {
+			defaultOutput = output.DefaultID
+		}
+	}
+	resolveOutput := func(id string) string {
+		if id == "" || id == "default" {
+			return defaultOutput
+		}
+		return id
+	}
+
+	var routes []CriblRoute
+	for _, table := range tables {
+		routes = append(routes, table.Routes...)
+	}
+
+	dataSources := []types.DataSource{}
+	for _, input := range inputs {
+		dataSources = append(dataSources, c.convertInput(group, input, routes, resolveOutput))
+	}
+	for _, route := range routes {
+		dataSources = append(dataSources, c.convertRoute(group, route, resolveOutput))
+	}
+	for _, output := range outputs {
+		if output.ID == "default" {
+			continue
+		}
+		dataSources = append(dataSources, c.convertOutput(group, output))
+	}
+	for _, pipeline := range pipelines {
+		dataSources = append(dataSources, c.convertPipeline(group, pipeline))
+	}
+	return dataSources, nil
+}
+
+// convertInput maps a source and traces it to its destinations: QuickConnect
+// connections first, then every route whose filter can match it, stopping at
+// the first final route that always matches
+func (c *CriblProvider) convertInput(group string, input CriblInput, routes []CriblRoute, resolveOutput func(string) string) types.DataSource {
+	status := "active"
+	if input.Disabled {
+		status = "disabled"
+	}
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(!input.Disabled)
+
+	rel := newCriblRelations(group)
+	if input.Pipeline != "" {
+		rel.add(types.RelationProcessedBy, "pipeline", input.Pipeline)
+	}
+
+	for _, connection := range input.Connections {
+		rel.add(types.RelationProcessedBy, "pipeline", connection.Pipeline)
+		rel.add(types.RelationRoutesTo, "destination", resolveOutput(connection.Output))
+	}
+
+	sendToRoutes := input.SendToRoutes == nil || *input.SendToRoutes
+	var routeNames, conditionalRoutes []string
+	if sendToRoutes {
+		inputRef := input.Type + ":" + input.ID
+		for _, route := range routes {
+			if route.Disabled {
+				continue
+			}
+			match := criblRouteMatches(route.Filter, inputRef)
+			if match == criblMatchNone {
+				continue
+			}
+			routeNames = append(routeNames, route.Name)
+			if match == criblMatchMaybe {
+				conditionalRoutes = append(conditionalRoutes, route.Name)
+			}
+			rel.add(types.RelationProcessedBy, "pipeline", route.Pipeline)
+			rel.add(types.RelationRoutesTo, "destination", resolveOutput(route.Output))
+			if route.Final && match == criblMatchAlways {
+				break
+			}
+		}
+	}
+
+	metadata := map[string]interface{}{
+		"inputType":    input.Type,
+		"sendToRoutes": sendToRoutes,
+		"routes":       routeNames,
+	}
+	if len(conditionalRoutes) > 0 {
+		metadata["conditionalRoutes"] = conditionalRoutes
+	}
+	if group != "" {
+		metadata["workerGroup"] = group
+	}
+
+	return types.DataSource{
+		ID:          criblID("source", group, input.ID),
+		Name:        input.ID,
+		Title:       input.ID,
+		Type:        "cribl-source",
+		Description: input.Description,
+		Status:      status,
+		Tags:        criblTags("source", group),
+		Normalized:  n,
+		Relations:   rel.relations,
+		Metadata:    metadata,
+	}
+}
+
+func (c *CriblProvider) convertRoute(group string, route CriblRoute, resolveOutput func(string) string) types.DataSource {
+	status := "active"
+	if route.Disabled {
+		status = "disabled"
+	}
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(!route.Disabled)
+
+	rel := newCriblRelations(group)
+	rel.add(types.RelationProcessedBy, "pipeline", route.Pipeline)
+	rel.add(types.RelationRoutesTo, "destination", resolveOutput(route.Output))
+
+	name := route.Name
+	if name == "" {
+		name = route.ID
+	}
+	metadata := map[string]interface{}{
+		"filter":   route.Filter,
+		"final":    route.Final,
+		"pipeline": route.Pipeline,
+		"output":   route.Output,
+	}
+	if group != "" {
+		metadata["workerGroup"] = group
+	}
+
+	return types.DataSource{
+		ID:          criblID("route", group, route.ID),
+		Name:        name,
+		Title:       name,
+		Type:        "cribl-route",
+		Pattern:     route.Filter,
+		Description: route.Description,
+		Status:      status,
+		Tags:        criblTags("route", group),
+		Normalized:  n,
+		Relations:   rel.relations,
+		Metadata:    metadata,
+	}
+}
+
+func (c *CriblProvider) convertOutput(group string, output CriblOutput) types.DataSource {
+	status := "active"
+	if output.Disabled {
+		status = "disabled"
+	}
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(!output.Disabled)
+
+	rel := newCriblRelations(group)
+	if output.Pipeline != "" {
+		rel.add(types.RelationProcessedBy, "pipeline", output.Pipeline)
+	}
+
+	metadata := map[string]interface{}{
+		"outputType": output.Type,
+	}
+	if group != "" {
+		metadata["workerGroup"] = group
+	}
+
+	return types.DataSource{
+		ID:          criblID("destination", group, output.ID),
+		Name:        output.ID,
+		Title:       output.ID,
+		Type:        "cribl-destination",
+		Description: output.Description,
+		Status:      status,
+		Tags:        criblTags("destination", group),
+		Normalized:  n,
+		Relations:   rel.relations,
+		Metadata:    metadata,
+	}
+}
+
+func (c *CriblProvider) convertPipeline(group string, pipeline CriblPipeline) types.DataSource {
+	functions := make([]string, 0, len(pipeline.Conf.Functions))
+	for _, function := range pipeline.Conf.Functions {
+		if !function.Disabled {
+			functions = append(functions, function.ID)
+		}
+	}
+	metadata := map[string]interface{}{
+		"functions": functions,
+	}
+	if group != "" {
+		metadata["workerGroup"] = group
+	}
+
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(true)
+
+	return types.DataSource{
+		ID:          criblID("pipeline", group, pipeline.ID),
+		Name:        pipeline.ID,
+		Title:       pipeline.ID,
+		Type:        "cribl-pipeline",
+		Description: pipeline.Conf.Description,
+		Status:      "active",
+		Tags:        criblTags("pipeline", group),
+		Normalized:  n,
+		Metadata:    metadata,
+	}
+}
+
+// criblRouteMatches classifies a route filter for one source. Only filters
+// made of __inputId comparisons joined by || are decisive; anything else
+// around them (negation, &&, other fields) means the route may match.
+func criblRouteMatches(filter, inputRef string) criblRouteMatch {
+	filter = strings.TrimSpace(filter)
+	if filter == "" || filter == "true" {
+		return criblMatchAlways
+	}
+
+	comparisons := criblInputIDPattern.FindAllStringSubmatch(filter, -1)
+	if len(comparisons) == 0 {
+		return criblMatchMaybe
+	}
+
+	// A filter made only of __inputId comparisons joined by || is decisive
+	residual := criblInputIDPattern.ReplaceAllString(filter, "")
+	residual = strings.NewReplacer("||", "", "(", "", ")", "", " ", "").Replace(residual)
+	onlyInputIDs := residual == ""
+
+	for _, comparison := range comparisons {
+		operator, value := comparison[1], comparison[2]
+		matched := inputRef == value
+		if strings.HasPrefix(operator, ".startsWith") {
+			matched = strings.HasPrefix(inputRef, value)
+		}
+		if matched {
+			if onlyInputIDs {
+				return criblMatchAlways
+			}
+			return criblMatchMaybe
+		}
+	}
+	if !onlyInputIDs {
+		return criblMatchMaybe
+	}
+	return criblMatchNone
+}
+
+// criblRelations collects deduplicated relations within a worker group
+type criblRelations struct {
+	group     string
+	seen      map[string]bool
+	relations []types.Relation
+}
+
+func newCriblRelations(group string) *criblRelations {
+	return &criblRelations{group: group, seen: make(map[string]bool)}
+}
+
+func (r *criblRelations) add(relationType, kind, id string) {
+	if id == "" {
+		return
+	}
+	target := criblID(kind, r.group, id)
+	if r.seen[relationType+target] {
+		return
+	}
+	r.seen[relationType+target] = true
+	r.relations = append(r.relations, types.Relation{Type: relationType, TargetID: target})
+}
+
+func criblID(kind, group, id string) string {
+	if group == "" {
+		return kind + ":" + id
+	}
+	return kind + ":" + group + "/" + id
+}
+
+func criblTags(kind, group string) []string {
+	tags := []string{"cribl", kind}
+	if group != "" {
+		tags = append(tags, "group:"+group)
+	}
+	return tags
+}
+
+// list fetches a list endpoint and decodes its items into out, a pointer to a slice
+func (c *CriblProvider) list(ctx context.Context, path string, out interface{}) error {
+	var resp CriblListResponse
+	if err := c.getJSON(ctx, path, &resp); err != nil {
+		return err
+	}
+	items, err := json.Marshal(resp.Items)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(items, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// getJSON performs an authenticated GET against the Cribl REST API
+func (c *CriblProvider) getJSON(ctx context.Context, path string, out interface{}) error {
+	token, err := c.authenticate(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return readJSONResponse(resp, "cribl", out)
+}
+
+// authenticate exchanges username and password for a token at
+// /api/v1/auth/login, or uses a pre-issued bearer token
+func (c *CriblProvider) authenticate(ctx context.Context) (string, error) {
+	auth := c.config.Auth
+	if auth == nil {
+		return "", nil
+	}
+	if auth.Type == "bearer" {
+		return auth.Token, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if c.token.valid(now) {
+		return c.token.value, nil
+	}
+
+	payload, err := json.Marshal(map[string]string{"username": auth.Username, "password": auth.Password})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode login request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/auth/login", bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to create login request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to log in: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Token string `json:"token"`
+	}
+	if err := readJSONResponse(resp, "cribl", &result); err != nil {
+		return "", fmt.Errorf("login failed: %w", err)
+	}
+	if result.Token == "" {
+		return "", fmt.Errorf("cribl login returned no token")
+	}
+
+	c.token.set(oauthTokenResponse{AccessToken: result.Token}, now)
+	return c.token.value, nil
+}
+
+func (c *CriblProvider) ValidateConnection(ctx context.Context) error {
+	if err := c.getJSON(ctx, "/system/info", nil); err != nil {
+		return fmt.Errorf("failed to connect to Cribl: %w", err)
+	}
+	return nil
+}
+
+func (c *CriblProvider) GetCapabilities() types.ProviderCapabilities {
+	return types.ProviderCapabilities{
+		SupportsRealTimeQueries: false,
+		SupportsHistoricalData:  false,
+		SupportedDataTypes:      []string{"cribl-source", "cribl-route", "cribl-destination", "cribl-pipeline"},
+		RequiresAuthentication:  c.config.Auth != nil,
+	}
+}
@@ -0,0 +1,151 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+func TestCriblFetchDataViews(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/auth/login" {
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["username"] != "admin" || body["password"] != "secret" {
+				http.Error(w, "bad credentials", http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"token":"cribl-token","forcePasswordChange":false}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer cribl-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/master/groups":
+			w.Write([]byte(`{"count":1,"items":[{"id":"default","workerCount":3}]}`))
+		case "/api/v1/m/default/system/inputs":
+			w.Write([]byte(`{"count":3,"items":[
+				{"id":"in_syslog","type":"syslog"},
+				{"id":"in_wef","type":"wef","pipeline":"win_pre"},
+				{"id":"in_hec","type":"splunk_hec","sendToRoutes":false,"connections":[{"output":"splunk_idx","pipeline":"passthru"}]}]}`))
+		case "/api/v1/m/default/system/outputs":
+			w.Write([]byte(`{"count":4,"items":[
+				{"id":"default","type":"default","defaultId":"devnull"},
+				{"id":"devnull","type":"devnull"},
+				{"id":"splunk_idx","type":"splunk_lb"},
+				{"id":"s3_archive","type":"s3","disabled":true}]}`))
+		case "/api/v1/m/default/routes":
+			w.Write([]byte(`{"count":1,"items":[{"id":"default","routes":[
+				{"id":"r1","name":"syslog_to_splunk","filter":"__inputId=='syslog:in_syslog'","pipeline":"syslog_clean","output":"splunk_idx","final":true},
+				{"id":"r2","name":"archive_windows","filter":"__inputId.startsWith('wef:') && sourcetype=='XmlWinEventLog'","pipeline":"passthru","output":"s3_archive","final":false},
+				{"id":"r3","name":"catch_all","filter":"true","pipeline":"main","output":"default","final":true}]}]}`))
+		case "/api/v1/m/default/pipelines":
+			w.Write([]byte(`{"count":4,"items":[
+				{"id":"main","conf":{"functions":[{"id":"eval"}]}},
+				{"id":"passthru","conf":{"functions":[]}},
+				{"id":"syslog_clean","conf":{"description":"Drop noise","functions":[{"id":"drop"},{"id":"mask","disabled":true}]}},
+				{"id":"win_pre","conf":{"functions":[{"id":"serde"}]}}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewCriblProvider(types.ProviderConfig{
+		Type:     "cribl",
+		Endpoint: server.URL,
+		Auth:     &types.AuthConfig{Type: "basic", Username: "admin", Password: "secret"},
+		Timeout:  5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, err := provider.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+
+	byID := make(map[string]types.DataSource)
+	for _, ds := range sources {
+		byID[ds.ID] = ds
+	}
+	// 3 sources, 3 routes, 3 destinations (default is an alias), 4 pipelines
+	if len(byID) != 13 {
+		t.Fatalf("expected 13 data sources, got %d", len(byID))
+	}
+
+	targets := func(ds types.DataSource, relationType string) []string {
+		var ids []string
+		for _, relation := range ds.Relations {
+			if relation.Type == relationType {
+				ids = append(ids, relation.TargetID)
+			}
+		}
+		return ids
+	}
+
+	syslog := byID["source:default/in_syslog"]
+	if got := targets(syslog, types.RelationRoutesTo); len(got) != 1 || got[0] != "destination:default/splunk_idx" {
+		t.Errorf("syslog should stop at its final route, got %v", got)
+	}
+	if got := targets(syslog, types.RelationProcessedBy); len(got) != 1 || got[0] != "pipeline:default/syslog_clean" {
+		t.Errorf("unexpected syslog pipelines %v", got)
+	}
+
+	wef := byID["source:default/in_wef"]
+	if got := targets(wef, types.RelationRoutesTo); len(got) != 2 || got[0] != "destination:default/s3_archive" || got[1] != "destination:default/devnull" {
+		t.Errorf("unexpected wef destinations %v", got)
+	}
+	if got := targets(wef, types.RelationProcessedBy); len(got) != 3 || got[0] != "pipeline:default/win_pre" {
+		t.Errorf("unexpected wef pipelines %v", got)
+	}
+	if conditional, _ := wef.Metadata["conditionalRoutes"].([]string); len(conditional) != 1 || conditional[0] != "archive_windows" {
+		t.Errorf("expected archive_windows to be conditional, got %v", wef.Metadata["conditionalRoutes"])
+	}
+
+	hec := byID["source:default/in_hec"]
+	if got := targets(hec, types.RelationRoutesTo); len(got) != 1 || got[0] != "destination:default/splunk_idx" {
+		t.Errorf("QuickConnect source should only use its connections, got %v", got)
+	}
+
+	if ds := byID["destination:default/s3_archive"]; ds.Status != "disabled" {
+		t.Errorf("expected disabled destination, got %s", ds.Status)
+	}
+	if ds := byID["route:default/r3"]; targets(ds, types.RelationRoutesTo)[0] != "destination:default/devnull" {
+		t.Errorf("default output should resolve to its defaultId, got %+v", ds.Relations)
+	}
+	if functions, _ := byID["pipeline:default/syslog_clean"].Metadata["functions"].([]string); len(functions) != 1 {
+		t.Errorf("disabled functions should be skipped, got %v", functions)
+	}
+}
+
+func TestCriblRouteMatches(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   criblRouteMatch
+	}{
+		{"true", criblMatchAlways},
+		{"", criblMatchAlways},
+		{"__inputId=='syslog:in_syslog'", criblMatchAlways},
+		{"__inputId=='http:in_http' || __inputId=='syslog:in_syslog'", criblMatchAlways},
+		{"__inputId.startsWith('syslog:')", criblMatchAlways},
+		{"__inputId=='http:in_http'", criblMatchNone},
+		{"__inputId=='syslog:in_syslog' && severity < 4", criblMatchMaybe},
+		{"__inputId=='http:in_http' || host=='fw01'", criblMatchMaybe},
+		{"!(__inputId=='http:in_http')", criblMatchMaybe},
+		{"__inputId!='http:in_http'", criblMatchMaybe},
+		{"sourcetype=='syslog'", criblMatchMaybe},
+	}
+	for _, tt := range tests {
+		if got := criblRouteMatches(tt.filter, "syslog:in_syslog"); got != tt.want {
+			t.Errorf("criblRouteMatches(%q) = %d, want %d", tt.filter, got, tt.want)
+		}
+	}
+}
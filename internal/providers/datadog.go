@@ -0,0 +1,329 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/logfiend/internal/types"
+)
+
+// DatadogProvider implements the Provider interface for Datadog Log
+// Management. It inventories log indexes, pipelines and archives.
+type DatadogProvider struct {
+	config  types.ProviderConfig
+	client  *http.Client
+	baseURL string
+}
+
+// DatadogIndexesResponse represents the /api/v1/logs/config/indexes response
+type DatadogIndexesResponse struct {
+	Indexes []DatadogIndex `json:"indexes"`
+}
+
+// DatadogIndex represents a log index
+type DatadogIndex struct {
+	Name   string `json:"name"`
+	Filter struct {
+		Query string `json:"query"`
+	} `json:"filter"`
+	NumRetentionDays                     int     `json:"num_retention_days"`
+	NumFlexLogsRetentionDays             int     `json:"num_flex_logs_retention_days"`
+	DailyLimit                           *int64  `json:"daily_limit"`
+	DailyLimitWarningThresholdPercentage float64 `json:"daily_limit_warning_threshold_percentage"`
+	DailyLimitReset                      *struct {
+		ResetTime      string `json:"reset_time"`
+		ResetUTCOffset string `json:"reset_utc_offset"`
+	} `json:"daily_limit_reset"`
+	IsRateLimited    bool `json:"is_rate_limited"`
+	ExclusionFilters []struct {
+		Name      string `json:"name"`
+		IsEnabled bool   `json:"is_enabled"`
+		Filter    struct {
+			Query      string  `json:"query"`
+			SampleRate float64 `json:"sample_rate"`
+		} `json:"filter"`
+	} `json:"exclusion_filters"`
+}
+
+// DatadogPipeline represents a log processing pipeline
+type DatadogPipeline struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	IsEnabled  bool   `json:"is_enabled"`
+	IsReadOnly bool   `json:"is_read_only"`
+	Filter     struct {
+		Query string `json:"query"`
+	} `json:"filter"`
+	Processors []DatadogProcessor `json:"processors"`
+}
+
+// DatadogProcessor represents a pipeline processor; nested pipelines carry
+// their own processors
+type DatadogProcessor struct {
+	Name       string             `json:"name"`
+	Type       string             `json:"type"`
+	IsEnabled  bool               `json:"is_enabled"`
+	Processors []DatadogProcessor `json:"processors,omitempty"`
+}
+
+// DatadogArchivesResponse represents the /api/v2/logs/config/archives response
+type DatadogArchivesResponse struct {
+	Data []struct {
+		ID         string `json:"id"`
+		Attributes struct {
+			Name        string `json:"name"`
+			Query       string `json:"query"`
+			State       string `json:"state"`
+			IncludeTags bool   `json:"include_tags"`
+			Destination struct {
+				Type      string `json:"type"`
+				Bucket    string `json:"bucket"`
+				Path      string `json:"path"`
+				Container string `json:"container"`
+			} `json:"destination"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// datadogSites maps site names to API endpoints
+var datadogSites = map[string]string{
+	"us1":     "https://api.datadoghq.com",
+	"us3":     "https://api.us3.datadoghq.com",
+	"us5":     "https://api.us5.datadoghq.com",
+	"eu":      "https://api.datadoghq.eu",
+	"eu1":     "https://api.datadoghq.eu",
+	"ap1":     "https://api.ap1.datadoghq.com",
+	"ap2":     "https://api.ap2.datadoghq.com",
+	"us1-fed": "https://api.ddog-gov.com",
+}
+
+// NewDatadogProvider creates a new Datadog provider. Every configuration
+// endpoint needs an application key as well as the API key.
+func NewDatadogProvider(config types.ProviderConfig) (types.Provider, error) {
+	if config.Auth == nil || config.Auth.APIKey == "" || config.Auth.ApplicationKey == "" {
+		return nil, fmt.Errorf("datadog requires auth.api_key and auth.application_key")
+	}
+
+	client, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL, err := datadogBaseURL(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DatadogProvider{
+		config:  config,
+		client:  client,
+		baseURL: baseURL,
+	}, nil
+}
+
+// datadogBaseURL resolves the API endpoint from options.site, which accepts
+// site names (us1, eu, ...) or site domains (datadoghq.eu), or the endpoint
+func datadogBaseURL(config types.ProviderConfig) (string, error) {
+	site := strings.ToLower(strings.TrimSpace(config.Options["site"]))
+	if site == "" {
+		return strings.TrimSuffix(config.Endpoint, "/"), nil
+	}
+	if baseURL, ok := datadogSites[site]; ok {
+		return baseURL, nil
+	}
+	if strings.Contains(site, ".") && !strings.Contains(site, "/") {
+		return "https://api." + strings.TrimPrefix(site, "app."), nil
+	}
+	return "", fmt.Errorf("unknown Datadog site: %s", site)
+}
+
+func (d *DatadogProvider) Name() string {
+	return "datadog"
+}
+
+func (d *DatadogProvider) FetchDataViews(ctx context.Context) ([]types.DataSource, error) {
+	var indexes DatadogIndexesResponse
+	if err := d.getJSON(ctx, "/api/v1/logs/config/indexes", &indexes); err != nil {
+		return nil, fmt.Errorf("failed to fetch indexes: %w", err)
+	}
+
+	var pipelines []DatadogPipeline
+	if err := d.getJSON(ctx, "/api/v1/logs/config/pipelines", &pipelines); err != nil {
+		return nil, fmt.Errorf("failed to fetch pipelines: %w", err)
+	}
+
+	var archives DatadogArchivesResponse
+	if err := d.getJSON(ctx, "/api/v2/logs/config/archives", &archives); err != nil {
+		return nil, fmt.Errorf("failed to fetch archives: %w", err)
+	}
+
+	dataSources := []types.DataSource{}
+	for _, index := range indexes.Indexes {
+		dataSources = append(dataSources, d.convertIndex(index))
+	}
+	for _, pipeline := range pipelines {
+		dataSources = append(dataSources, d.convertPipeline(pipeline))
+	}
+	for _, archive := range archives.Data {
+		attrs := archive.Attributes
+		status := "active"
+		switch strings.ToUpper(attrs.State) {
+		case "FAILING":
+			status = "failed"
+		case "WORKING_AFTER_FAILURE":
+			status = "degraded"
+		}
+
+		location := attrs.Destination.Bucket
+		if location == "" {
+			location = attrs.Destination.Container
+		}
+		dataSources = append(dataSources, types.DataSource{
+			ID:         "archive:" + archive.ID,
+			Name:       attrs.Name,
+			Title:      attrs.Name,
+			Type:       "datadog-archive",
+			Pattern:    attrs.Query,
+			Status:     status,
+			Tags:       []string{"datadog", "archive"},
+			Normalized: types.NewNormalizedFields(),
+			Metadata: map[string]interface{}{
+				"state":           attrs.State,
+				"destinationType": attrs.Destination.Type,
+				"location":        location,
+				"path":            attrs.Destination.Path,
+				"includeTags":     attrs.IncludeTags,
+			},
+		})
+	}
+
+	return dataSources, nil
+}
+
+// convertIndex maps a log index. Indexes over their daily quota drop every
+// further log until the quota resets, so they get a status of their own.
+func (d *DatadogProvider) convertIndex(index DatadogIndex) types.DataSource {
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(true)
+	if index.NumRetentionDays > 0 {
+		n.RetentionDays = intPtr(index.NumRetentionDays)
+	}
+
+	status := "active"
+	tags := []string{"datadog", "index"}
+	if index.IsRateLimited {
+		status = "daily_limit_reached"
+		tags = append(tags, "daily-limit-reached")
+	}
+
+	exclusions := make([]map[string]interface{}, 0, len(index.ExclusionFilters))
+	for _, filter := range index.ExclusionFilters {
+		exclusions = append(exclusions, map[string]interface{}{
+			"name":       filter.Name,
+			"enabled":    filter.IsEnabled,
+			"query":      filter.Filter.Query,
+			"sampleRate": filter.Filter.SampleRate,
+		})
+	}
+
+	metadata := map[string]interface{}{
+		"filter":           index.Filter.Query,
+		"isRateLimited":    index.IsRateLimited,
+		"exclusionFilters": exclusions,
+	}
+	if index.DailyLimit != nil {
+		metadata["dailyLimit"] = *index.DailyLimit
+		if index.DailyLimitWarningThresholdPercentage > 0 {
+			metadata["dailyLimitWarningThresholdPercentage"] = index.DailyLimitWarningThresholdPercentage
+		}
+	}
+	if index.DailyLimitReset != nil {
+		metadata["dailyLimitReset"] = index.DailyLimitReset.ResetTime + " " + index.DailyLimitReset.ResetUTCOffset
+	}
+	if index.NumFlexLogsRetentionDays > 0 {
+		metadata["flexRetentionDays"] = index.NumFlexLogsRetentionDays
+	}
+
+	return types.DataSource{
+		ID:         "index:" + index.Name,
+		Name:       index.Name,
+		Title:      index.Name,
+		Type:       "datadog-index",
+		Pattern:    index.Filter.Query,
+		Status:     status,
+		Tags:       tags,
+		Normalized: n,
+		Metadata:   metadata,
+	}
+}
+
+func (d *DatadogProvider) convertPipeline(pipeline DatadogPipeline) types.DataSource {
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(pipeline.IsEnabled)
+
+	status := "active"
+	if !pipeline.IsEnabled {
+		status = "disabled"
+	}
+
+	return types.DataSource{
+		ID:         "pipeline:" + pipeline.ID,
+		Name:       pipeline.Name,
+		Title:      pipeline.Name,
+		Type:       "datadog-pipeline",
+		Pattern:    pipeline.Filter.Query,
+		Status:     status,
+		Tags:       []string{"datadog", "pipeline"},
+		Normalized: n,
+		Metadata: map[string]interface{}{
+			"pipelineType": pipeline.Type,
+			"readOnly":     pipeline.IsReadOnly,
+			"processors":   pipeline.Processors,
+		},
+	}
+}
+
+// getJSON performs a GET with the DD-API-KEY and DD-APPLICATION-KEY headers
+func (d *DatadogProvider) getJSON(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", d.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("DD-API-KEY", d.config.Auth.APIKey)
+	req.Header.Set("DD-APPLICATION-KEY", d.config.Auth.ApplicationKey)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return readJSONResponse(resp, "datadog", out)
+}
+
+func (d *DatadogProvider) ValidateConnection(ctx context.Context) error {
+	var resp struct {
+		Valid bool `json:"valid"`
+	}
+	if err := d.getJSON(ctx, "/api/v1/validate", &resp); err != nil {
+		return fmt.Errorf("failed to connect to Datadog: %w", err)
+	}
+	if !resp.Valid {
+		return fmt.Errorf("datadog rejected the API key")
+	}
+	return nil
+}
+
+func (d *DatadogProvider) GetCapabilities() types.ProviderCapabilities {
+	return types.ProviderCapabilities{
+		SupportsRealTimeQueries: true,
+		SupportsHistoricalData:  true,
+		SupportedDataTypes:      []string{"datadog-index", "datadog-pipeline", "datadog-archive"},
+		RequiresAuthentication:  true,
+	}
+}
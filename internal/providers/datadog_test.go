@@ -0,0 +1,99 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+func TestDatadogFetchDataViews(t *testing.T) {
+	responses := map[string]string{
+		"/api/v1/logs/config/indexes": `{"indexes":[
+			{"name":"main","filter":{"query":"*"},"num_retention_days":15,"daily_limit":1000000,"is_rate_limited":true,
+			 "exclusion_filters":[{"name":"drop debug","is_enabled":true,"filter":{"query":"status:debug","sample_rate":1}}]},
+			{"name":"security","filter":{"query":"source:cloudtrail"},"num_retention_days":90}]}`,
+		"/api/v1/logs/config/pipelines": `[{"id":"pl1","name":"CloudTrail","type":"integration","is_enabled":true,"is_read_only":true,
+			"filter":{"query":"source:cloudtrail"},"processors":[{"name":"grok","type":"grok-parser","is_enabled":true}]},
+			{"id":"pl2","name":"Legacy","type":"pipeline","is_enabled":false}]`,
+		"/api/v2/logs/config/archives": `{"data":[{"id":"a1","type":"archives","attributes":{"name":"s3-archive","query":"*","state":"FAILING",
+			"destination":{"type":"s3","bucket":"dd-archive","path":"/logs"}}}]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("DD-API-KEY") != "api" || r.Header.Get("DD-APPLICATION-KEY") != "app" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	provider, err := NewDatadogProvider(types.ProviderConfig{
+		Type:     "datadog",
+		Endpoint: server.URL,
+		Auth:     &types.AuthConfig{Type: "api_key", APIKey: "api", ApplicationKey: "app"},
+		Timeout:  5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, err := provider.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+
+	byID := make(map[string]types.DataSource)
+	for _, ds := range sources {
+		byID[ds.ID] = ds
+	}
+	if len(byID) != 5 {
+		t.Fatalf("expected 5 data sources, got %d", len(byID))
+	}
+
+	mainIndex := byID["index:main"]
+	if mainIndex.Status != "daily_limit_reached" || mainIndex.Metadata["dailyLimit"] != int64(1000000) {
+		t.Errorf("rate limited index should be flagged, got %s %v", mainIndex.Status, mainIndex.Metadata)
+	}
+	if exclusions := mainIndex.Metadata["exclusionFilters"].([]map[string]interface{}); len(exclusions) != 1 || exclusions[0]["query"] != "status:debug" {
+		t.Errorf("unexpected exclusion filters %v", exclusions)
+	}
+	if ds := byID["index:security"]; ds.Status != "active" || *ds.Normalized.RetentionDays != 90 {
+		t.Errorf("unexpected security index %+v", ds)
+	}
+	if ds := byID["pipeline:pl2"]; ds.Status != "disabled" {
+		t.Errorf("expected disabled pipeline, got %s", ds.Status)
+	}
+	if ds := byID["archive:a1"]; ds.Status != "failed" || ds.Metadata["location"] != "dd-archive" {
+		t.Errorf("unexpected archive %+v", ds)
+	}
+
+	if _, err := NewDatadogProvider(types.ProviderConfig{Type: "datadog", Endpoint: server.URL, Auth: &types.AuthConfig{Type: "api_key", APIKey: "api"}}); err == nil {
+		t.Error("expected a missing application_key to be rejected")
+	}
+}
+
+func TestDatadogBaseURL(t *testing.T) {
+	cases := map[string]string{
+		"":                  "https://example.test",
+		"EU":                "https://api.datadoghq.eu",
+		"us3":               "https://api.us3.datadoghq.com",
+		"us5.datadoghq.com": "https://api.us5.datadoghq.com",
+	}
+	for site, want := range cases {
+		got, err := datadogBaseURL(types.ProviderConfig{Endpoint: "https://example.test/", Options: map[string]string{"site": site}})
+		if err != nil || got != want {
+			t.Errorf("site %q: got %q, %v; want %q", site, got, err, want)
+		}
+	}
+	if _, err := datadogBaseURL(types.ProviderConfig{Options: map[string]string{"site": "mars"}}); err == nil {
+		t.Error("expected error for unknown site")
+	}
+}
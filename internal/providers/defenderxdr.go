@@ -0,0 +1,337 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+// DefenderXDRProvider implements the Provider interface for Microsoft Defender
+// XDR. It inventories the advanced hunting tables, their columns and when each
+// last received events through the Graph security runHuntingQuery API.
+type DefenderXDRProvider struct {
+	config       types.ProviderConfig
+	client       *http.Client
+	baseURL      string
+	tokenURL     string
+	tables       []string
+	lookbackDays int
+
+	mu    sync.Mutex
+	token cachedToken
+}
+
+// DefenderHuntingResponse represents the runHuntingQuery response
+type DefenderHuntingResponse struct {
+	Schema []struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"schema"`
+	Results json.RawMessage `json:"results"`
+}
+
+// defenderSchemaRow is one getschema row tagged with its table
+type defenderSchemaRow struct {
+	TableName     string `json:"TableName"`
+	ColumnName    string `json:"ColumnName"`
+	ColumnType    string `json:"ColumnType"`
+	ColumnOrdinal int    `json:"ColumnOrdinal"`
+}
+
+// defenderStatsRow is one table's ingestion summary
+type defenderStatsRow struct {
+	TableName    string `json:"TableName"`
+	LastEvent    string `json:"LastEvent"`
+	LastIngested string `json:"LastIngested"`
+	EventCount   int64  `json:"EventCount"`
+}
+
+const (
+	defenderGraphURL     = "https://graph.microsoft.com"
+	defenderGraphScope   = "https://graph.microsoft.com/.default"
+	defenderRetention    = 30 // advanced hunting keeps 30 days of data
+	defenderHuntingQuery = "/v1.0/security/runHuntingQuery"
+)
+
+// defenderTables are the advanced hunting tables queried unless
+// options.tables lists others; tables the tenant is not licensed for are
+// skipped
+var defenderTables = []string{
+	"AlertEvidence", "AlertInfo", "BehaviorEntities", "BehaviorInfo",
+	"CloudAppEvents",
+	"DeviceEvents", "DeviceFileCertificateInfo", "DeviceFileEvents", "DeviceImageLoadEvents",
+	"DeviceInfo", "DeviceLogonEvents", "DeviceNetworkEvents", "DeviceNetworkInfo",
+	"DeviceProcessEvents", "DeviceRegistryEvents",
+	"EmailAttachmentInfo", "EmailEvents", "EmailPostDeliveryEvents", "EmailUrlInfo", "UrlClickEvents",
+	"IdentityDirectoryEvents", "IdentityInfo", "IdentityLogonEvents", "IdentityQueryEvents",
+}
+
+// defenderProducts maps table name prefixes to the Defender workload that
+// feeds them
+var defenderProducts = []struct {
+	prefix  string
+	product string
+}{
+	{"Device", "Microsoft Defender for Endpoint"},
+	{"Email", "Microsoft Defender for Office 365"},
+	{"UrlClick", "Microsoft Defender for Office 365"},
+	{"Identity", "Microsoft Defender for Identity"},
+	{"CloudApp", "Microsoft Defender for Cloud Apps"},
+}
+
+// NewDefenderXDRProvider creates a new Defender XDR provider. auth.token_url
+// defaults to the Azure AD v2 endpoint of options.tenant_id.
+func NewDefenderXDRProvider(config types.ProviderConfig) (types.Provider, error) {
+	client, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &DefenderXDRProvider{
+		config:       config,
+		client:       client,
+		baseURL:      strings.TrimSuffix(config.Endpoint, "/"),
+		tables:       defenderTables,
+		lookbackDays: defenderRetention,
+	}
+	if provider.baseURL == "" {
+		provider.baseURL = defenderGraphURL
+	}
+
+	if config.Auth != nil && config.Auth.Type != "bearer" {
+		provider.tokenURL = config.Auth.TokenURL
+		if provider.tokenURL == "" && config.Options["tenant_id"] != "" {
+			provider.tokenURL = "https://login.microsoftonline.com/" + url.PathEscape(config.Options["tenant_id"]) + "/oauth2/v2.0/token"
+		}
+		if provider.tokenURL == "" {
+			return nil, fmt.Errorf("defenderxdr requires auth.token_url or options.tenant_id")
+		}
+	}
+
+	if list := config.Options["tables"]; list != "" {
+		provider.tables = nil
+		for _, table := range strings.Split(list, ",") {
+			table = strings.TrimSpace(table)
+			if !defenderTableName(table) {
+				return nil, fmt.Errorf("invalid table name: %q", table)
+			}
+			provider.tables = append(provider.tables, table)
+		}
+	}
+
+	if days := config.Options["lookback_days"]; days != "" {
+		n, ok := parseInt64(days)
+		if !ok || n < 1 || n > defenderRetention {
+			return nil, fmt.Errorf("invalid lookback_days: %s (1-%d)", days, defenderRetention)
+		}
+		provider.lookbackDays = int(n)
+	}
+
+	return provider, nil
+}
+
+func (d *DefenderXDRProvider) Name() string {
+	return "defenderxdr"
+}
+
+func (d *DefenderXDRProvider) FetchDataViews(ctx context.Context) ([]types.DataSource, error) {
+	// One fuzzy union returns every schema; tables the tenant lacks drop out
+	schemas := make([]string, 0, len(d.tables))
+	for _, table := range d.tables {
+		schemas = append(schemas, fmt.Sprintf("(%s | getschema | extend TableName = %q)", table, table))
+	}
+	var schemaRows []defenderSchemaRow
+	if err := d.runQuery(ctx, "union isfuzzy=true "+strings.Join(schemas, ", ")+" | project TableName, ColumnName, ColumnType, ColumnOrdinal", &schemaRows); err != nil {
+		return nil, fmt.Errorf("failed to fetch table schemas: %w", err)
+	}
+
+	columns := make(map[string][]defenderSchemaRow)
+	for _, row := range schemaRows {
+		columns[row.TableName] = append(columns[row.TableName], row)
+	}
+
+	var statsRows []defenderStatsRow
+	stats := fmt.Sprintf("union isfuzzy=true withsource=TableName %s | where Timestamp > ago(%dd) | summarize LastEvent = max(Timestamp), LastIngested = max(ingestion_time()), EventCount = count() by TableName",
+		strings.Join(d.tables, ", "), d.lookbackDays)
+	if err := d.runQuery(ctx, stats, &statsRows); err != nil {
+		return nil, fmt.Errorf("failed to fetch ingestion statistics: %w", err)
+	}
+	ingested := make(map[string]*defenderStatsRow, len(statsRows))
+	for i := range statsRows {
+		ingested[statsRows[i].TableName] = &statsRows[i]
+	}
+
+	dataSources := make([]types.DataSource, 0, len(columns))
+	for _, table := range d.tables {
+		if len(columns[table]) == 0 {
+			continue
+		}
+		dataSources = append(dataSources, d.convertTable(table, columns[table], ingested[table]))
+	}
+	return dataSources, nil
+}
+
+func (d *DefenderXDRProvider) convertTable(table string, rows []defenderSchemaRow, stats *defenderStatsRow) types.DataSource {
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].ColumnOrdinal < rows[j].ColumnOrdinal })
+	columns := make([]map[string]string, 0, len(rows))
+	for _, row := range rows {
+		columns = append(columns, map[string]string{"name": row.ColumnName, "type": row.ColumnType})
+	}
+
+	product := "Microsoft Defender XDR"
+	for _, p := range defenderProducts {
+		if strings.HasPrefix(table, p.prefix) {
+			product = p.product
+			break
+		}
+	}
+
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(true)
+	n.RetentionDays = intPtr(defenderRetention)
+	n.Vendor = "Microsoft"
+	n.Product = product
+
+	status := "no_data"
+	metadata := map[string]interface{}{
+		"columns":      columns,
+		"columnCount":  len(columns),
+		"lookbackDays": d.lookbackDays,
+	}
+	if stats != nil {
+		status = "active"
+		n.EventCount = int64Ptr(stats.EventCount)
+		if last, err := time.Parse(time.RFC3339Nano, stats.LastEvent); err == nil {
+			n.LastEventTime = timePtr(last)
+		}
+		if ingested, err := time.Parse(time.RFC3339Nano, stats.LastIngested); err == nil {
+			metadata["lastIngested"] = ingested.Format(time.RFC3339)
+		}
+	}
+
+	return types.DataSource{
+		ID:         "table:" + table,
+		Name:       table,
+		Title:      table,
+		Type:       "defenderxdr-table",
+		Pattern:    table,
+		Status:     status,
+		Tags:       []string{"defenderxdr", "advanced-hunting"},
+		Normalized: n,
+		Metadata:   metadata,
+	}
+}
+
+// defenderTableName reports whether name is safe to splice into KQL
+func defenderTableName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !(r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')) {
+			return false
+		}
+	}
+	return true
+}
+
+// runQuery runs one advanced hunting query and decodes its result rows
+func (d *DefenderXDRProvider) runQuery(ctx context.Context, query string, rows interface{}) error {
+	token, err := d.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(map[string]string{"Query": query})
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", d.baseURL+defenderHuntingQuery, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result DefenderHuntingResponse
+	if err := readJSONResponse(resp, "defenderxdr", &result); err != nil {
+		return err
+	}
+	if rows == nil || len(result.Results) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(result.Results, rows); err != nil {
+		return fmt.Errorf("failed to decode results: %w", err)
+	}
+	return nil
+}
+
+// accessToken returns a cached client-credentials token, or a pre-issued
+// bearer token
+func (d *DefenderXDRProvider) accessToken(ctx context.Context) (string, error) {
+	auth := d.config.Auth
+	if auth == nil {
+		return "", fmt.Errorf("defenderxdr requires client_credentials or bearer auth")
+	}
+	if auth.Type == "bearer" {
+		return auth.Token, nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	if d.token.valid(now) {
+		return d.token.value, nil
+	}
+
+	scopes := auth.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{defenderGraphScope}
+	}
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", auth.ClientID)
+	form.Set("client_secret", auth.ClientSecret)
+	form.Set("scope", strings.Join(scopes, " "))
+
+	resp, err := requestToken(ctx, d.client, d.tokenURL, form, "defenderxdr")
+	if err != nil {
+		return "", err
+	}
+	d.token.set(resp, now)
+	return d.token.value, nil
+}
+
+func (d *DefenderXDRProvider) ValidateConnection(ctx context.Context) error {
+	query := "union isfuzzy=true " + strings.Join(d.tables, ", ") + " | take 1 | project Timestamp"
+	if err := d.runQuery(ctx, query, nil); err != nil {
+		return fmt.Errorf("failed to connect to Defender XDR: %w", err)
+	}
+	return nil
+}
+
+func (d *DefenderXDRProvider) GetCapabilities() types.ProviderCapabilities {
+	return types.ProviderCapabilities{
+		SupportsRealTimeQueries: true,
+		SupportsHistoricalData:  true,
+		SupportedDataTypes:      []string{"defenderxdr-table"},
+		RequiresAuthentication:  true,
+	}
+}
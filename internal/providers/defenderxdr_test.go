@@ -0,0 +1,125 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+func TestDefenderXDRFetchDataViews(t *testing.T) {
+	tokenRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/tenant/oauth2/v2.0/token" {
+			tokenRequests++
+			r.ParseForm()
+			if r.Form.Get("grant_type") != "client_credentials" || r.Form.Get("client_id") != "app-id" ||
+				r.Form.Get("client_secret") != "app-secret" || r.Form.Get("scope") != defenderGraphScope {
+				http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"access_token":"graph-token","expires_in":3599}`))
+			return
+		}
+		if r.URL.Path != defenderHuntingQuery || r.Method != "POST" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer graph-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var body struct {
+			Query string `json:"Query"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch {
+		case strings.Contains(body.Query, "getschema"):
+			if !strings.Contains(body.Query, `(EmailEvents | getschema | extend TableName = "EmailEvents")`) {
+				t.Errorf("unexpected schema query %s", body.Query)
+			}
+			// The tenant has no Defender for Identity, so IdentityLogonEvents is absent
+			w.Write([]byte(`{"schema":[{"name":"TableName","type":"String"}],"results":[
+				{"TableName":"DeviceEvents","ColumnName":"DeviceId","ColumnType":"string","ColumnOrdinal":1},
+				{"TableName":"DeviceEvents","ColumnName":"Timestamp","ColumnType":"datetime","ColumnOrdinal":0},
+				{"TableName":"EmailEvents","ColumnName":"Timestamp","ColumnType":"datetime","ColumnOrdinal":0}]}`))
+		case strings.Contains(body.Query, "summarize"):
+			if !strings.Contains(body.Query, "ago(7d)") || !strings.Contains(body.Query, "max(ingestion_time())") {
+				t.Errorf("expected the lookback window in %s", body.Query)
+			}
+			w.Write([]byte(`{"results":[{"TableName":"DeviceEvents","LastEvent":"2024-05-01T10:15:00.1234567Z","LastIngested":"2024-05-01T10:17:30Z","EventCount":125000}]}`))
+		default:
+			http.Error(w, "unexpected query", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewDefenderXDRProvider(types.ProviderConfig{
+		Type:     "defenderxdr",
+		Endpoint: server.URL,
+		Auth: &types.AuthConfig{
+			Type:         "client_credentials",
+			ClientID:     "app-id",
+			ClientSecret: "app-secret",
+			TokenURL:     server.URL + "/tenant/oauth2/v2.0/token",
+		},
+		Options: map[string]string{
+			"tables":        "DeviceEvents, EmailEvents, IdentityLogonEvents",
+			"lookback_days": "7",
+		},
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sources, err := provider.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+	if tokenRequests != 1 {
+		t.Errorf("expected the access token to be cached, got %d token requests", tokenRequests)
+	}
+	if len(sources) != 2 {
+		t.Fatalf("expected 2 tables, got %d", len(sources))
+	}
+
+	device, email := sources[0], sources[1]
+	if device.ID != "table:DeviceEvents" || device.Status != "active" || device.Normalized.Product != "Microsoft Defender for Endpoint" {
+		t.Errorf("unexpected DeviceEvents table %+v", device)
+	}
+	if *device.Normalized.EventCount != 125000 || *device.Normalized.RetentionDays != 30 {
+		t.Errorf("unexpected DeviceEvents normalized fields %+v", device.Normalized)
+	}
+	if want := time.Date(2024, 5, 1, 10, 15, 0, 123456700, time.UTC); device.Normalized.LastEventTime == nil || !device.Normalized.LastEventTime.Equal(want) {
+		t.Errorf("unexpected last event time %v", device.Normalized.LastEventTime)
+	}
+	if device.Metadata["lastIngested"] != "2024-05-01T10:17:30Z" {
+		t.Errorf("unexpected last ingested time %v", device.Metadata["lastIngested"])
+	}
+	columns := device.Metadata["columns"].([]map[string]string)
+	if len(columns) != 2 || columns[0]["name"] != "Timestamp" || columns[1]["type"] != "string" {
+		t.Errorf("expected columns in ordinal order, got %v", columns)
+	}
+	if email.Status != "no_data" || email.Normalized.EventCount != nil {
+		t.Errorf("table without events in the window should be no_data, got %+v", email)
+	}
+}
+
+func TestDefenderXDRRejectsUnsafeTableNames(t *testing.T) {
+	_, err := NewDefenderXDRProvider(types.ProviderConfig{
+		Type:     "defenderxdr",
+		Endpoint: "https://graph.microsoft.com",
+		Auth:     &types.AuthConfig{Type: "bearer", Token: "t"},
+		Options:  map[string]string{"tables": "DeviceEvents | take 1"},
+	})
+	if err == nil {
+		t.Fatal("expected an error for a table name containing KQL")
+	}
+}
@@ -180,9 +180,11 @@ func (e *ElasticsearchProvider) convertToDataSource(hit struct {
 		attributes = hit.Source.DataView
 	}
 
+	// Kibana saved objects carry no volume or retention statistics
 	ds := types.DataSource{
-		ID:   hit.ID,
-		Type: sourceType,
+		ID:         hit.ID,
+		Type:       sourceType,
+		Normalized: types.NewNormalizedFields(),
 	}
 
 	// Extract common fields
@@ -245,11 +247,95 @@ func (e *ElasticsearchProvider) ValidateConnection(ctx context.Context) error {
 	return nil
 }
 
+// KibanaRulesResponse represents a page of the detection engine _find API
+type KibanaRulesResponse struct {
+	Page    int `json:"page"`
+	PerPage int `json:"perPage"`
+	Total   int `json:"total"`
+	Data    []struct {
+		ID       string   `json:"id"`
+		RuleID   string   `json:"rule_id"`
+		Name     string   `json:"name"`
+		Type     string   `json:"type"`
+		Enabled  bool     `json:"enabled"`
+		Language string   `json:"language"`
+		Query    string   `json:"query"`
+		Index    []string `json:"index"`
+		Severity string   `json:"severity"`
+		Tags     []string `json:"tags"`
+	} `json:"data"`
+}
+
+// FetchDetectionRules lists Elastic Security detection rules. The detection
+// engine lives in Kibana, so options.kibana_url must point at it.
+func (e *ElasticsearchProvider) FetchDetectionRules(ctx context.Context) ([]types.DetectionRule, error) {
+	kibanaURL := strings.TrimSuffix(e.config.Options["kibana_url"], "/")
+	if kibanaURL == "" {
+		return nil, fmt.Errorf("options.kibana_url is required to fetch detection rules")
+	}
+
+	const perPage = 100
+	rules := []types.DetectionRule{}
+	for page := 1; ; page++ {
+		url := fmt.Sprintf("%s/api/detection_engine/rules/_find?page=%d&per_page=%d", kibanaURL, page, perPage)
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		if e.config.Auth != nil {
+			e.addAuth(req)
+		}
+
+		resp, err := e.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute request: %w", err)
+		}
+
+		var rulesResp KibanaRulesResponse
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("kibana returned status %d: %s", resp.StatusCode, string(body))
+		}
+		err = json.NewDecoder(resp.Body).Decode(&rulesResp)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		for _, rule := range rulesResp.Data {
+			rules = append(rules, types.DetectionRule{
+				ID:       rule.ID,
+				Name:     rule.Name,
+				Type:     "elastic-detection-rule",
+				Enabled:  rule.Enabled,
+				Language: rule.Language,
+				Query:    rule.Query,
+				Indices:  rule.Index,
+				Metadata: map[string]interface{}{
+					"ruleId":   rule.RuleID,
+					"ruleType": rule.Type,
+					"severity": rule.Severity,
+					"tags":     rule.Tags,
+				},
+			})
+		}
+
+		if len(rulesResp.Data) == 0 || page*perPage >= rulesResp.Total {
+			break
+		}
+	}
+
+	return rules, nil
+}
+
 func (e *ElasticsearchProvider) GetCapabilities() types.ProviderCapabilities {
 	return types.ProviderCapabilities{
 		SupportsRealTimeQueries: true,
 		SupportsHistoricalData:  true,
 		SupportedDataTypes:      []string{"index-pattern", "data-view"},
 		RequiresAuthentication:  e.config.Auth != nil,
+		SupportsDetectionRules:  e.config.Options["kibana_url"] != "",
 	}
 }
@@ -0,0 +1,342 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+// ExabeamProvider implements the Provider interface for the Exabeam Security
+// Operations Platform. It inventories site and cloud collectors, and the log
+// sources Exabeam has parsed, grouped by vendor, product and parser over a
+// search window.
+type ExabeamProvider struct {
+	config       types.ProviderConfig
+	client       *http.Client
+	baseURL      string
+	tokenURL     string
+	searchWindow time.Duration
+	now          func() time.Time
+
+	mu    sync.Mutex
+	token cachedToken
+}
+
+// ExabeamCollector represents a site or cloud collector
+type ExabeamCollector struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	Status        string `json:"status"`
+	Version       string `json:"version"`
+	Site          string `json:"site"`
+	LastHeartbeat string `json:"lastHeartbeat"`
+}
+
+// ExabeamCollectorsResponse represents the collector list responses
+type ExabeamCollectorsResponse struct {
+	Collectors []ExabeamCollector `json:"collectors"`
+}
+
+// ExabeamSearchResponse represents the /search/v2/events response
+type ExabeamSearchResponse struct {
+	Rows      []map[string]interface{} `json:"rows"`
+	TotalRows int                      `json:"totalRows"`
+}
+
+const (
+	exabeamDefaultWindow = 7 * 24 * time.Hour
+	exabeamSearchLimit   = 3000
+)
+
+// NewExabeamProvider creates a new Exabeam provider. auth.token_url defaults
+// to <endpoint>/auth/v1/token.
+func NewExabeamProvider(config types.ProviderConfig) (types.Provider, error) {
+	client, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &ExabeamProvider{
+		config:       config,
+		client:       client,
+		baseURL:      strings.TrimSuffix(config.Endpoint, "/"),
+		searchWindow: exabeamDefaultWindow,
+		now:          time.Now,
+	}
+	provider.tokenURL = provider.baseURL + "/auth/v1/token"
+	if config.Auth != nil && config.Auth.TokenURL != "" {
+		provider.tokenURL = config.Auth.TokenURL
+	}
+
+	if window := config.Options["search_window"]; window != "" {
+		provider.searchWindow, err = time.ParseDuration(window)
+		if err != nil || provider.searchWindow <= 0 {
+			return nil, fmt.Errorf("invalid search_window: %s", window)
+		}
+	}
+
+	return provider, nil
+}
+
+func (e *ExabeamProvider) Name() string {
+	return "exabeam"
+}
+
+func (e *ExabeamProvider) FetchDataViews(ctx context.Context) ([]types.DataSource, error) {
+	dataSources := []types.DataSource{}
+
+	// Tenants only see the collector kinds they have deployed
+	for _, kind := range []string{"site", "cloud"} {
+		var resp ExabeamCollectorsResponse
+		err := e.doJSON(ctx, "GET", "/"+kind+"-collectors/v1/collectors", nil, &resp)
+		if isStatus(err, http.StatusNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s collectors: %w", kind, err)
+		}
+		for _, collector := range resp.Collectors {
+			dataSources = append(dataSources, e.convertCollector(collector, kind))
+		}
+	}
+
+	logSources, err := e.fetchLogSources(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch log sources: %w", err)
+	}
+	return append(dataSources, logSources...), nil
+}
+
+func (e *ExabeamProvider) convertCollector(collector ExabeamCollector, kind string) types.DataSource {
+	status := exabeamStatus(collector.Status)
+
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(status != "stopped" && status != "disabled")
+
+	metadata := map[string]interface{}{
+		"collectorKind": kind,
+		"collectorType": collector.Type,
+		"state":         collector.Status,
+	}
+	if collector.Version != "" {
+		metadata["version"] = collector.Version
+	}
+	if collector.Site != "" {
+		metadata["site"] = collector.Site
+	}
+	if collector.LastHeartbeat != "" {
+		metadata["lastHeartbeat"] = collector.LastHeartbeat
+	}
+
+	return types.DataSource{
+		ID:         "collector:" + collector.ID,
+		Name:       collector.Name,
+		Title:      collector.Name,
+		Type:       "exabeam-" + kind + "-collector",
+		Status:     status,
+		Tags:       []string{"exabeam", "collector", kind},
+		Normalized: n,
+		Metadata:   metadata,
+	}
+}
+
+// fetchLogSources groups the events parsed within the search window by
+// vendor, product and parser
+func (e *ExabeamProvider) fetchLogSources(ctx context.Context) ([]types.DataSource, error) {
+	end := e.now().UTC()
+	body := map[string]interface{}{
+		"filter":    "",
+		"startTime": end.Add(-e.searchWindow).Format(time.RFC3339),
+		"endTime":   end.Format(time.RFC3339),
+		"fields":    []string{"vendor", "product", "parser_name", "count(*) as event_count", "max(approxLogTime) as last_event_time"},
+		"groupBy":   []string{"vendor", "product", "parser_name"},
+		"limit":     exabeamSearchLimit,
+	}
+
+	var resp ExabeamSearchResponse
+	if err := e.doJSON(ctx, "POST", "/search/v2/events", body, &resp); err != nil {
+		return nil, err
+	}
+	// The grouped search cannot be paged, so a truncated result is an error
+	// rather than a silently incomplete inventory
+	if resp.TotalRows > len(resp.Rows) {
+		return nil, fmt.Errorf("search returned %d of %d vendor/product/parser groups; shorten options.search_window", len(resp.Rows), resp.TotalRows)
+	}
+
+	dataSources := make([]types.DataSource, 0, len(resp.Rows))
+	for _, row := range resp.Rows {
+		vendor, product, parser := exabeamString(row["vendor"]), exabeamString(row["product"]), exabeamString(row["parser_name"])
+		if parser == "" {
+			continue
+		}
+
+		n := types.NewNormalizedFields()
+		n.Enabled = boolPtr(true)
+		n.Vendor = vendor
+		n.Product = product
+		if count, ok := parseInt64(exabeamString(row["event_count"])); ok {
+			n.EventCount = int64Ptr(count)
+		}
+		if last, ok := exabeamTime(row["last_event_time"]); ok {
+			n.LastEventTime = timePtr(last)
+		}
+
+		name := strings.TrimSpace(vendor + " " + product)
+		if name == "" {
+			name = parser
+		}
+		dataSources = append(dataSources, types.DataSource{
+			ID:         "log-source:" + vendor + "/" + product + "/" + parser,
+			Name:       name,
+			Title:      name,
+			Type:       "exabeam-log-source",
+			Status:     "active",
+			Tags:       []string{"exabeam", "log-source"},
+			Normalized: n,
+			Metadata: map[string]interface{}{
+				"parser":       parser,
+				"searchWindow": e.searchWindow.String(),
+			},
+		})
+	}
+	return dataSources, nil
+}
+
+// exabeamStatus maps collector states to DataSource statuses
+func exabeamStatus(state string) string {
+	switch strings.ToLower(state) {
+	case "running", "healthy", "online", "ok":
+		return "active"
+	case "offline", "disconnected", "unreachable":
+		return "offline"
+	case "stopped":
+		return "stopped"
+	case "error", "failed":
+		return "failed"
+	case "warning", "degraded":
+		return "degraded"
+	case "":
+		return "unknown"
+	}
+	return strings.ToLower(state)
+}
+
+func exabeamString(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// exabeamTime parses the search API's timestamps, which are epoch
+// microseconds or RFC 3339 strings depending on the field
+func exabeamTime(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case float64:
+		return time.UnixMicro(int64(v)).UTC(), true
+	case string:
+		if micros, ok := parseInt64(v); ok {
+			return time.UnixMicro(micros).UTC(), true
+		}
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// doJSON performs an authenticated request with an optional JSON body
+func (e *ExabeamProvider) doJSON(ctx context.Context, method, path string, body, out interface{}) error {
+	token, err := e.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, e.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return readJSONResponse(resp, "exabeam", out)
+}
+
+// accessToken returns a cached client-credentials token, or a pre-issued
+// bearer token
+func (e *ExabeamProvider) accessToken(ctx context.Context) (string, error) {
+	auth := e.config.Auth
+	if auth == nil {
+		return "", fmt.Errorf("exabeam requires client_credentials or bearer auth")
+	}
+	if auth.Type == "bearer" {
+		return auth.Token, nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := time.Now()
+	if e.token.valid(now) {
+		return e.token.value, nil
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", auth.ClientID)
+	form.Set("client_secret", auth.ClientSecret)
+	if len(auth.Scopes) > 0 {
+		form.Set("scope", strings.Join(auth.Scopes, " "))
+	}
+
+	resp, err := requestToken(ctx, e.client, e.tokenURL, form, "exabeam")
+	if err != nil {
+		return "", err
+	}
+	e.token.set(resp, now)
+	return e.token.value, nil
+}
+
+func (e *ExabeamProvider) ValidateConnection(ctx context.Context) error {
+	if _, err := e.accessToken(ctx); err != nil {
+		return fmt.Errorf("failed to connect to Exabeam: %w", err)
+	}
+	return nil
+}
+
+func (e *ExabeamProvider) GetCapabilities() types.ProviderCapabilities {
+	return types.ProviderCapabilities{
+		SupportsRealTimeQueries: true,
+		SupportsHistoricalData:  true,
+		SupportedDataTypes:      []string{"exabeam-site-collector", "exabeam-cloud-collector", "exabeam-log-source"},
+		RequiresAuthentication:  true,
+	}
+}
@@ -0,0 +1,111 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+func TestExabeamFetchDataViews(t *testing.T) {
+	tokenRequests := 0
+	cloudStatus, totalRows := http.StatusNotFound, 2
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/token" {
+			tokenRequests++
+			r.ParseForm()
+			if r.PostForm.Get("grant_type") != "client_credentials" || r.PostForm.Get("client_id") != "exa-id" || r.PostForm.Get("client_secret") != "exa-secret" {
+				http.Error(w, "invalid_client", http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"access_token":"exa-token","token_type":"Bearer","expires_in":14400}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer exa-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/site-collectors/v1/collectors":
+			w.Write([]byte(`{"collectors":[
+				{"id":"sc1","name":"dc-collector","type":"Syslog","status":"Running","site":"HQ"},
+				{"id":"sc2","name":"branch-collector","type":"Windows Log Collector","status":"Offline"}]}`))
+		case "/cloud-collectors/v1/collectors":
+			http.Error(w, "no cloud collectors", cloudStatus)
+		case "/search/v2/events":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["groupBy"] == nil {
+				t.Error("expected a grouped search")
+			}
+			fmt.Fprintf(w, `{"totalRows":%d,"rows":[`, totalRows)
+			w.Write([]byte(`
+				{"vendor":"Microsoft","product":"Windows","parser_name":"microsoft-windows-xml-4624","event_count":1200,"last_event_time":1714557600000000},
+				{"vendor":"Palo Alto Networks","product":"NGFW","parser_name":"pan-ngfw-cef-traffic","event_count":"34","last_event_time":"2024-05-01T09:00:00Z"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewExabeamProvider(types.ProviderConfig{
+		Type:     "exabeam",
+		Endpoint: server.URL,
+		Auth: &types.AuthConfig{
+			Type:         "client_credentials",
+			ClientID:     "exa-id",
+			ClientSecret: "exa-secret",
+			TokenURL:     server.URL + "/oauth/token",
+		},
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, err := provider.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+	if tokenRequests != 1 {
+		t.Errorf("expected one token request, got %d", tokenRequests)
+	}
+
+	byID := make(map[string]types.DataSource)
+	for _, ds := range sources {
+		byID[ds.ID] = ds
+	}
+	if len(byID) != 4 {
+		t.Fatalf("expected 4 data sources, got %d", len(byID))
+	}
+
+	if ds := byID["collector:sc2"]; ds.Status != "offline" || ds.Type != "exabeam-site-collector" {
+		t.Errorf("unexpected collector %+v", ds)
+	}
+	win := byID["log-source:Microsoft/Windows/microsoft-windows-xml-4624"]
+	if win.Normalized.Vendor != "Microsoft" || win.Normalized.Product != "Windows" || win.Metadata["parser"] != "microsoft-windows-xml-4624" {
+		t.Errorf("unexpected log source %+v", win)
+	}
+	if win.Normalized.LastEventTime == nil || !win.Normalized.LastEventTime.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected last event time %v", win.Normalized.LastEventTime)
+	}
+	pan := byID["log-source:Palo Alto Networks/NGFW/pan-ngfw-cef-traffic"]
+	if pan.Normalized.EventCount == nil || *pan.Normalized.EventCount != 34 || pan.Normalized.LastEventTime == nil {
+		t.Errorf("unexpected log source %+v", pan.Normalized)
+	}
+
+	// Only a 404 means the tenant has no collectors of a kind
+	cloudStatus = http.StatusForbidden
+	if _, err := provider.FetchDataViews(context.Background()); err == nil {
+		t.Error("expected a 403 on the collector list to fail")
+	}
+	cloudStatus, totalRows = http.StatusNotFound, 3001
+	if _, err := provider.FetchDataViews(context.Background()); err == nil || !strings.Contains(err.Error(), "2 of 3001") {
+		t.Errorf("expected a truncated search to fail, got %v", err)
+	}
+}
@@ -0,0 +1,455 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/logfiend/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+// FileProvider reads manually maintained data sources from local YAML, JSON
+// or CSV files listed in options.paths. YAML and JSON files hold either a list
+// of records or an inventory document with a data_sources list; CSV files have
+// a header row naming the record fields.
+type FileProvider struct {
+	config types.ProviderConfig
+	paths  []string
+}
+
+// fileRelationTypes are the relation types a record may declare
+var fileRelationTypes = map[string]bool{
+	types.RelationRoutesTo:    true,
+	types.RelationStoredIn:    true,
+	types.RelationProcessedBy: true,
+}
+
+// fileTimeLayouts are accepted for created_at, updated_at and event times
+var fileTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+// fileRecordError is a validation problem at a line of a source file
+type fileRecordError struct {
+	path string
+	line int
+	msg  string
+}
+
+func (e fileRecordError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.path, e.line, e.msg)
+}
+
+// fileRecord is a data source being read, with the line it started on
+type fileRecord struct {
+	ds   types.DataSource
+	path string
+	line int
+	// relationLines holds the line each relation was declared on
+	relationLines []int
+}
+
+func NewFileProvider(config types.ProviderConfig) (types.Provider, error) {
+	var paths []string
+	for _, path := range strings.Split(config.Options["paths"], ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("file provider requires options.paths")
+	}
+	for _, path := range paths {
+		if filepath.IsAbs(filepath.Clean(path)) {
+			return nil, fmt.Errorf("absolute paths not allowed for security: %s", path)
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yml", ".yaml", ".json", ".csv":
+		default:
+			return nil, fmt.Errorf("unsupported file type %q: use .yml, .yaml, .json or .csv", path)
+		}
+	}
+
+	return &FileProvider{config: config, paths: paths}, nil
+}
+
+func (f *FileProvider) Name() string {
+	return "file"
+}
+
+func (f *FileProvider) FetchDataViews(ctx context.Context) ([]types.DataSource, error) {
+	var records []*fileRecord
+	var problems []string
+	for _, path := range f.paths {
+		data, err := readRelativeFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		fileRecords, errs := parseFileRecords(path, data)
+		records = append(records, fileRecords...)
+		for _, err := range errs {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	for _, err := range validateFileRecords(records) {
+		problems = append(problems, err.Error())
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid data source files:\n  %s", strings.Join(problems, "\n  "))
+	}
+
+	dataSources := make([]types.DataSource, 0, len(records))
+	for _, record := range records {
+		dataSources = append(dataSources, record.ds)
+	}
+	return dataSources, nil
+}
+
+// ValidateConnection reads and validates every file, since there is no
+// remote system to reach
+func (f *FileProvider) ValidateConnection(ctx context.Context) error {
+	_, err := f.FetchDataViews(ctx)
+	return err
+}
+
+func (f *FileProvider) GetCapabilities() types.ProviderCapabilities {
+	return types.ProviderCapabilities{
+		SupportsRealTimeQueries: false,
+		SupportsHistoricalData:  false,
+		SupportedDataTypes:      []string{"static"},
+		RequiresAuthentication:  false,
+	}
+}
+
+// parseFileRecords reads the records of one file, returning every problem
+// found rather than stopping at the first
+func parseFileRecords(path string, data []byte) ([]*fileRecord, []error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return parseCSVRecords(path, data)
+	case ".json":
+		if err := json.Unmarshal(data, new(interface{})); err != nil {
+			line := 1
+			if syntaxErr, ok := err.(*json.SyntaxError); ok {
+				line += bytes.Count(data[:syntaxErr.Offset], []byte("\n"))
+			}
+			return nil, []error{fileRecordError{path, line, err.Error()}}
+		}
+	}
+	// JSON is read through the YAML parser too, which keeps line numbers
+	return parseYAMLRecords(path, data)
+}
+
+func parseYAMLRecords(path string, data []byte) ([]*fileRecord, []error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", path, err)}
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	list := doc.Content[0]
+	if list.Kind == yaml.MappingNode {
+		// An inventory document; everything but data_sources is ignored
+		var found *yaml.Node
+		for i := 0; i+1 < len(list.Content); i += 2 {
+			if list.Content[i].Value == "data_sources" {
+				found = list.Content[i+1]
+			}
+		}
+		if found == nil {
+			return nil, []error{fileRecordError{path, list.Line, "expected a list of data sources or a data_sources key"}}
+		}
+		list = found
+	}
+	if list.Kind != yaml.SequenceNode {
+		return nil, []error{fileRecordError{path, list.Line, "expected a list of data sources"}}
+	}
+
+	var records []*fileRecord
+	var errs []error
+	for _, item := range list.Content {
+		if item.Kind != yaml.MappingNode {
+			errs = append(errs, fileRecordError{path, item.Line, "expected a data source mapping"})
+			continue
+		}
+		record := newFileRecord(path, item.Line)
+		for i := 0; i+1 < len(item.Content); i += 2 {
+			key, value := item.Content[i], item.Content[i+1]
+			if err := record.setNode(key.Value, value); err != nil {
+				errs = append(errs, fileRecordError{path, value.Line, err.Error()})
+			}
+		}
+		records = append(records, record)
+	}
+	return records, errs
+}
+
+// parseCSVRecords reads a CSV file whose header names the record fields,
+// using normalized.<field> and metadata.<key> columns for nested values.
+// tags and relations cells hold ";"-separated lists.
+func parseCSVRecords(path string, data []byte) ([]*fileRecord, []error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", path, err)}
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	var records []*fileRecord
+	var errs []error
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// csv.ParseError already names the line
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		record := newFileRecord(path, line)
+		for i, value := range row {
+			if value = strings.TrimSpace(value); value == "" {
+				continue
+			}
+			if err := record.setString(header[i], value, line); err != nil {
+				errs = append(errs, fileRecordError{path, line, err.Error()})
+			}
+		}
+		records = append(records, record)
+	}
+	return records, errs
+}
+
+func newFileRecord(path string, line int) *fileRecord {
+	record := &fileRecord{path: path, line: line}
+	record.ds.Normalized = types.NewNormalizedFields()
+	record.ds.Metadata = map[string]interface{}{
+		"sourceFile": fmt.Sprintf("%s:%d", path, line),
+	}
+	return record
+}
+
+// setNode applies a YAML or JSON value to the record
+func (r *fileRecord) setNode(key string, node *yaml.Node) error {
+	switch key {
+	case "normalized", "metadata":
+		if node.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s must be a mapping", key)
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			name, value := node.Content[i].Value, node.Content[i+1]
+			if key == "metadata" {
+				var decoded interface{}
+				if err := value.Decode(&decoded); err != nil {
+					return fmt.Errorf("metadata.%s: %w", name, err)
+				}
+				r.ds.Metadata[name] = decoded
+				continue
+			}
+			if value.Kind != yaml.ScalarNode {
+				return fmt.Errorf("normalized.%s must be a scalar", name)
+			}
+			if err := r.setString("normalized."+name, value.Value, value.Line); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "tags":
+		if node.Kind == yaml.ScalarNode {
+			return r.setString(key, node.Value, node.Line)
+		}
+		if node.Kind != yaml.SequenceNode {
+			return fmt.Errorf("tags must be a list")
+		}
+		for _, tag := range node.Content {
+			if tag.Kind != yaml.ScalarNode || strings.TrimSpace(tag.Value) == "" {
+				return fmt.Errorf("tags must be non-empty strings")
+			}
+			r.ds.Tags = append(r.ds.Tags, strings.TrimSpace(tag.Value))
+		}
+		return nil
+	case "relations":
+		if node.Kind != yaml.SequenceNode {
+			return fmt.Errorf("relations must be a list")
+		}
+		for _, item := range node.Content {
+			var relation struct {
+				Type     string `yaml:"type"`
+				TargetID string `yaml:"target_id"`
+			}
+			if item.Kind != yaml.MappingNode || item.Decode(&relation) != nil {
+				return fmt.Errorf("relations entries need type and target_id")
+			}
+			r.ds.Relations = append(r.ds.Relations, types.Relation{Type: relation.Type, TargetID: relation.TargetID})
+			r.relationLines = append(r.relationLines, item.Line)
+		}
+		return nil
+	case "ocsf":
+		// Classification is recomputed from the catalog
+		return nil
+	}
+	if node.Kind != yaml.ScalarNode {
+		return fmt.Errorf("%s must be a scalar", key)
+	}
+	return r.setString(key, node.Value, node.Line)
+}
+
+// setString applies a textual value, as read from a CSV cell or YAML scalar
+func (r *fileRecord) setString(key, value string, line int) error {
+	ds, n := &r.ds, r.ds.Normalized
+	switch key {
+	case "id":
+		ds.ID = value
+	case "name":
+		ds.Name = value
+	case "title":
+		ds.Title = value
+	case "type":
+		ds.Type = value
+	case "pattern":
+		ds.Pattern = value
+	case "description":
+		ds.Description = value
+	case "status":
+		ds.Status = strings.ToLower(value)
+	case "created_at", "updated_at":
+		t, ok := parseTimeLayouts(value, fileTimeLayouts...)
+		if !ok {
+			return fmt.Errorf("%s: invalid time %q", key, value)
+		}
+		if key == "created_at" {
+			ds.CreatedAt = timePtr(t)
+		} else {
+			ds.UpdatedAt = timePtr(t)
+		}
+	case "tags":
+		for _, tag := range strings.FieldsFunc(value, func(c rune) bool { return c == ';' || c == ',' }) {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				ds.Tags = append(ds.Tags, tag)
+			}
+		}
+	case "relations":
+		// type=target pairs
+		for _, pair := range strings.Split(value, ";") {
+			relationType, target, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				return fmt.Errorf("relations: expected type=target_id, got %q", pair)
+			}
+			ds.Relations = append(ds.Relations, types.Relation{Type: strings.TrimSpace(relationType), TargetID: strings.TrimSpace(target)})
+			r.relationLines = append(r.relationLines, line)
+		}
+	case "normalized.enabled":
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s: expected true or false, got %q", key, value)
+		}
+		n.Enabled = boolPtr(enabled)
+	case "normalized.event_count", "normalized.size_bytes":
+		count, ok := parseInt64(value)
+		if !ok || count < 0 {
+			return fmt.Errorf("%s: expected a non-negative integer, got %q", key, value)
+		}
+		if key == "normalized.event_count" {
+			n.EventCount = int64Ptr(count)
+		} else {
+			n.SizeBytes = int64Ptr(count)
+		}
+	case "normalized.retention_days":
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 0 {
+			return fmt.Errorf("%s: expected a non-negative integer, got %q", key, value)
+		}
+		n.RetentionDays = intPtr(days)
+	case "normalized.first_event_time", "normalized.last_event_time":
+		t, ok := parseTimeLayouts(value, fileTimeLayouts...)
+		if !ok {
+			return fmt.Errorf("%s: invalid time %q", key, value)
+		}
+		if key == "normalized.first_event_time" {
+			n.FirstEventTime = timePtr(t)
+		} else {
+			n.LastEventTime = timePtr(t)
+		}
+	case "normalized.ingest_rate_eps":
+		rate, ok := parseFloat64(value)
+		if !ok || rate < 0 {
+			return fmt.Errorf("%s: expected a non-negative number, got %q", key, value)
+		}
+		n.IngestRateEPS = float64Ptr(rate)
+	case "normalized.vendor":
+		n.Vendor = value
+	case "normalized.product":
+		n.Product = value
+	case "normalized.version":
+		// Stamped by logfiend
+	default:
+		if name := strings.TrimPrefix(key, "metadata."); name != key && name != "" {
+			ds.Metadata[name] = value
+			return nil
+		}
+		return fmt.Errorf("unknown field %q", key)
+	}
+	return nil
+}
+
+// validateFileRecords fills defaults and checks required fields, unique IDs
+// and relation targets across all files
+func validateFileRecords(records []*fileRecord) []error {
+	var errs []error
+	seen := make(map[string]*fileRecord)
+	for _, record := range records {
+		ds := &record.ds
+		if ds.ID == "" {
+			errs = append(errs, fileRecordError{record.path, record.line, "id is required"})
+			continue
+		}
+		if ds.Type == "" {
+			errs = append(errs, fileRecordError{record.path, record.line, fmt.Sprintf("%s: type is required", ds.ID)})
+		}
+		if first, ok := seen[ds.ID]; ok {
+			errs = append(errs, fileRecordError{record.path, record.line, fmt.Sprintf("duplicate id %q, first defined at %s:%d", ds.ID, first.path, first.line)})
+			continue
+		}
+		seen[ds.ID] = record
+
+		if ds.Name == "" {
+			ds.Name = ds.ID
+		}
+		if ds.Title == "" {
+			ds.Title = ds.Name
+		}
+		if ds.Status == "" {
+			ds.Status = "active"
+		}
+	}
+
+	for _, record := range records {
+		for i, relation := range record.ds.Relations {
+			line := record.relationLines[i]
+			switch {
+			case !fileRelationTypes[relation.Type]:
+				errs = append(errs, fileRecordError{record.path, line, fmt.Sprintf("unknown relation type %q (use routes_to, stored_in or processed_by)", relation.Type)})
+			case seen[relation.TargetID] == nil:
+				errs = append(errs, fileRecordError{record.path, line, fmt.Sprintf("relation target %q is not defined in any file", relation.TargetID)})
+			}
+		}
+	}
+	return errs
+}
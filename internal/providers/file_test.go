@@ -0,0 +1,130 @@
+package providers
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/logfiend/internal/types"
+)
+
+func TestFileProviderExamples(t *testing.T) {
+	provider, err := NewFileProvider(types.ProviderConfig{
+		Type:    "file",
+		Options: map[string]string{"paths": "../../examples/static/sources.yml, ../../examples/static/sources.csv"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := provider.ValidateConnection(context.Background()); err != nil {
+		t.Fatalf("ValidateConnection: %v", err)
+	}
+	sources, err := provider.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+	if len(sources) != 4 {
+		t.Fatalf("expected 4 data sources, got %d", len(sources))
+	}
+	byID := make(map[string]types.DataSource)
+	for _, ds := range sources {
+		byID[ds.ID] = ds
+	}
+
+	firewall := byID["syslog:edge-firewalls"]
+	if firewall.Status != "active" || firewall.Title != "Edge firewall syslog" || *firewall.Normalized.RetentionDays != 90 || *firewall.Normalized.IngestRateEPS != 350 {
+		t.Errorf("unexpected firewall source %+v", firewall)
+	}
+	if !reflect.DeepEqual(firewall.Relations, []types.Relation{{Type: types.RelationRoutesTo, TargetID: "archive:cold-storage"}}) {
+		t.Errorf("unexpected relations %v", firewall.Relations)
+	}
+	if firewall.Metadata["owner"] != "network-team" || !strings.HasSuffix(firewall.Metadata["sourceFile"].(string), "sources.yml:5") {
+		t.Errorf("unexpected metadata %v", firewall.Metadata)
+	}
+
+	payroll := byID["app:payroll-audit"]
+	if payroll.Status != "disabled" || payroll.Normalized.Enabled == nil || *payroll.Normalized.Enabled {
+		t.Errorf("unexpected payroll source %+v", payroll)
+	}
+
+	archive := byID["archive:cold-storage"]
+	if archive.Name != "Cold storage archive" || *archive.Normalized.SizeBytes != 1099511627776 || !reflect.DeepEqual(archive.Tags, []string{"archive", "s3"}) {
+		t.Errorf("unexpected archive source %+v", archive)
+	}
+	badge := byID["badge:door-readers"]
+	if badge.Normalized.Vendor != "hid" || badge.Metadata["owner"] != "facilities" || len(badge.Relations) != 1 || !strings.HasSuffix(badge.Metadata["sourceFile"].(string), "sources.csv:3") {
+		t.Errorf("unexpected badge source %+v", badge)
+	}
+}
+
+func TestFileProviderInventoryJSON(t *testing.T) {
+	inventory := `{
+	"metadata": {"provider": "splunk"},
+	"data_sources": [
+		{"id": "main", "type": "splunk-index", "tags": ["splunk"], "ocsf": {"classes": []},
+		 "normalized": {"version": 1, "event_count": 1000, "last_event_time": "2024-05-01T10:00:00Z"}}
+	]
+}`
+	records, errs := parseFileRecords("inventory.json", []byte(inventory))
+	if len(errs) > 0 || len(records) != 1 {
+		t.Fatalf("expected one record, got %d (%v)", len(records), errs)
+	}
+	ds := records[0].ds
+	if *ds.Normalized.EventCount != 1000 || ds.Normalized.LastEventTime == nil || ds.Normalized.Version != types.NormalizedFieldsVersion {
+		t.Errorf("unexpected record %+v", ds)
+	}
+}
+
+func TestFileProviderErrors(t *testing.T) {
+	cases := map[string]struct {
+		data string
+		want []string
+	}{
+		"sources.yml": {
+			data: "- id: a\n  type: t\n  colour: red\n- id: a\n  type: t\n- name: no id\n- id: b\n  type: t\n  normalized:\n    event_count: many\n  relations:\n    - type: feeds\n      target_id: a\n    - type: stored_in\n      target_id: missing\n",
+			want: []string{
+				`sources.yml:3: unknown field "colour"`,
+				`sources.yml:4: duplicate id "a", first defined at sources.yml:1`,
+				`sources.yml:6: id is required`,
+				`sources.yml:10: normalized.event_count: expected a non-negative integer, got "many"`,
+				`sources.yml:12: unknown relation type "feeds"`,
+				`sources.yml:14: relation target "missing" is not defined in any file`,
+			},
+		},
+		"sources.json": {
+			data: "[\n  {\"id\": \"a\",\n   \"type\": \"t\",,}\n]",
+			want: []string{"sources.json:3:"},
+		},
+		"sources.csv": {
+			data: "id,type,normalized.enabled\na,t,yes\nb,,true\n",
+			want: []string{
+				`sources.csv:2: normalized.enabled: expected true or false, got "yes"`,
+				`sources.csv:3: b: type is required`,
+			},
+		},
+	}
+	for path, tc := range cases {
+		records, errs := parseFileRecords(path, []byte(tc.data))
+		errs = append(errs, validateFileRecords(records)...)
+		var messages []string
+		for _, err := range errs {
+			messages = append(messages, err.Error())
+		}
+		got := strings.Join(messages, "\n")
+		for _, want := range tc.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("%s: expected %q in:\n%s", path, want, got)
+			}
+		}
+		if len(messages) != len(tc.want) {
+			t.Errorf("%s: expected %d errors, got %d:\n%s", path, len(tc.want), len(messages), got)
+		}
+	}
+
+	for _, paths := range []string{"", "/etc/sources.yml", "sources.txt"} {
+		if _, err := NewFileProvider(types.ProviderConfig{Type: "file", Options: map[string]string{"paths": paths}}); err == nil {
+			t.Errorf("expected an error for paths %q", paths)
+		}
+	}
+}
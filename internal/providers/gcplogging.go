@@ -0,0 +1,509 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/logfiend/internal/types"
+)
+
+// GCPLoggingProvider implements the Provider interface for Google Cloud
+// Logging. It lists log names, log buckets, sinks and exclusions in every
+// configured project, so routing that sends logs past the SIEM is visible.
+type GCPLoggingProvider struct {
+	config   types.ProviderConfig
+	client   *http.Client
+	tokens   *googleTokenSource
+	baseURL  string
+	projects []string
+}
+
+// GCPLogNamesResponse represents the logs.list response
+type GCPLogNamesResponse struct {
+	LogNames      []string `json:"logNames"`
+	NextPageToken string   `json:"nextPageToken"`
+}
+
+// GCPLogBucket represents a log bucket
+type GCPLogBucket struct {
+	Name             string `json:"name"`
+	Description      string `json:"description"`
+	RetentionDays    int    `json:"retentionDays"`
+	Locked           bool   `json:"locked"`
+	LifecycleState   string `json:"lifecycleState"`
+	AnalyticsEnabled bool   `json:"analyticsEnabled"`
+}
+
+// GCPLogBucketsResponse represents the buckets.list response
+type GCPLogBucketsResponse struct {
+	Buckets       []GCPLogBucket `json:"buckets"`
+	NextPageToken string         `json:"nextPageToken"`
+}
+
+// GCPLogExclusion represents an exclusion, standalone or inside a sink
+type GCPLogExclusion struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Filter      string `json:"filter"`
+	Disabled    bool   `json:"disabled"`
+}
+
+// GCPLogExclusionsResponse represents the exclusions.list response
+type GCPLogExclusionsResponse struct {
+	Exclusions    []GCPLogExclusion `json:"exclusions"`
+	NextPageToken string            `json:"nextPageToken"`
+}
+
+// GCPLogSink represents a sink
+type GCPLogSink struct {
+	Name            string            `json:"name"`
+	Description     string            `json:"description"`
+	Destination     string            `json:"destination"`
+	Filter          string            `json:"filter"`
+	Disabled        bool              `json:"disabled"`
+	IncludeChildren bool              `json:"includeChildren"`
+	WriterIdentity  string            `json:"writerIdentity"`
+	Exclusions      []GCPLogExclusion `json:"exclusions"`
+}
+
+// GCPLogSinksResponse represents the sinks.list response
+type GCPLogSinksResponse struct {
+	Sinks         []GCPLogSink `json:"sinks"`
+	NextPageToken string       `json:"nextPageToken"`
+}
+
+const (
+	gcpLoggingURL          = "https://logging.googleapis.com"
+	gcpLoggingReadScope    = "https://www.googleapis.com/auth/logging.read"
+	gcpAuditLogServiceName = "cloudaudit.googleapis.com"
+)
+
+// NewGCPLoggingProvider creates a new Cloud Logging provider. options.projects
+// lists the projects to inventory; it defaults to the service account's project.
+func NewGCPLoggingProvider(config types.ProviderConfig) (types.Provider, error) {
+	client, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &GCPLoggingProvider{
+		config:  config,
+		client:  client,
+		baseURL: strings.TrimSuffix(config.Endpoint, "/"),
+	}
+	if provider.baseURL == "" {
+		provider.baseURL = gcpLoggingURL
+	}
+
+	for _, project := range strings.Split(config.Options["projects"], ",") {
+		if project = strings.TrimSpace(project); project != "" {
+			provider.projects = append(provider.projects, project)
+		}
+	}
+
+	if config.Auth != nil && config.Auth.Type == "service_account" {
+		provider.tokens, err = newGoogleTokenSource(client, config.Auth, gcpLoggingReadScope)
+		if err != nil {
+			return nil, err
+		}
+		if len(provider.projects) == 0 && provider.tokens.account.ProjectID != "" {
+			provider.projects = []string{provider.tokens.account.ProjectID}
+		}
+	}
+	if len(provider.projects) == 0 {
+		return nil, fmt.Errorf("gcplogging provider requires options.projects")
+	}
+
+	return provider, nil
+}
+
+func (g *GCPLoggingProvider) Name() string {
+	return "gcplogging"
+}
+
+func (g *GCPLoggingProvider) FetchDataViews(ctx context.Context) ([]types.DataSource, error) {
+	dataSources := []types.DataSource{}
+	for _, project := range g.projects {
+		projectSources, err := g.fetchProject(ctx, project)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inventory project %s: %w", project, err)
+		}
+		dataSources = append(dataSources, projectSources...)
+	}
+	return dataSources, nil
+}
+
+func (g *GCPLoggingProvider) fetchProject(ctx context.Context, project string) ([]types.DataSource, error) {
+	parent := g.baseURL + "/v2/projects/" + url.PathEscape(project)
+
+	var logNames []string
+	for pageToken := ""; ; {
+		var resp GCPLogNamesResponse
+		if err := g.getJSON(ctx, parent+"/logs"+pageQuery(pageToken), &resp); err != nil {
+			return nil, fmt.Errorf("failed to fetch log names: %w", err)
+		}
+		logNames = append(logNames, resp.LogNames...)
+		if pageToken = resp.NextPageToken; pageToken == "" {
+			break
+		}
+	}
+
+	var buckets []GCPLogBucket
+	for pageToken := ""; ; {
+		var resp GCPLogBucketsResponse
+		if err := g.getJSON(ctx, parent+"/locations/-/buckets"+pageQuery(pageToken), &resp); err != nil {
+			return nil, fmt.Errorf("failed to fetch log buckets: %w", err)
+		}
+		buckets = append(buckets, resp.Buckets...)
+		if pageToken = resp.NextPageToken; pageToken == "" {
+			break
+		}
+	}
+
+	var sinks []GCPLogSink
+	for pageToken := ""; ; {
+		var resp GCPLogSinksResponse
+		if err := g.getJSON(ctx, parent+"/sinks"+pageQuery(pageToken), &resp); err != nil {
+			return nil, fmt.Errorf("failed to fetch sinks: %w", err)
+		}
+		sinks = append(sinks, resp.Sinks...)
+		if pageToken = resp.NextPageToken; pageToken == "" {
+			break
+		}
+	}
+
+	var exclusions []GCPLogExclusion
+	for pageToken := ""; ; {
+		var resp GCPLogExclusionsResponse
+		if err := g.getJSON(ctx, parent+"/exclusions"+pageQuery(pageToken), &resp); err != nil {
+			return nil, fmt.Errorf("failed to fetch exclusions: %w", err)
+		}
+		exclusions = append(exclusions, resp.Exclusions...)
+		if pageToken = resp.NextPageToken; pageToken == "" {
+			break
+		}
+	}
+
+	dataSources := make([]types.DataSource, 0, len(logNames)+len(buckets)+len(sinks)+len(exclusions))
+	for _, logName := range logNames {
+		dataSources = append(dataSources, g.convertLogName(project, logName))
+	}
+	bucketIDs := make(map[string]bool, len(buckets))
+	for _, bucket := range buckets {
+		bucketIDs[bucket.Name] = true
+		dataSources = append(dataSources, g.convertBucket(project, bucket))
+	}
+	for _, sink := range sinks {
+		dataSources = append(dataSources, g.convertSink(project, sink, bucketIDs))
+	}
+	for _, exclusion := range exclusions {
+		dataSources = append(dataSources, g.convertExclusion(project, exclusion))
+	}
+	return dataSources, nil
+}
+
+func (g *GCPLoggingProvider) convertLogName(project, logName string) types.DataSource {
+	logID := lastPathSegment(logName)
+	if unescaped, err := url.PathUnescape(logID); err == nil {
+		logID = unescaped
+	}
+
+	tags := []string{"gcplogging", "log"}
+	if strings.HasPrefix(logID, gcpAuditLogServiceName+"/") {
+		tags = append(tags, "audit-logs")
+	}
+
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(true)
+
+	return types.DataSource{
+		ID:         "log:" + project + "/" + logID,
+		Name:       logID,
+		Title:      logID,
+		Type:       "gcplogging-log",
+		Pattern:    logName,
+		Status:     "active",
+		Tags:       tags,
+		Normalized: n,
+		Metadata: map[string]interface{}{
+			"project": project,
+			"logName": logName,
+		},
+	}
+}
+
+func (g *GCPLoggingProvider) convertBucket(project string, bucket GCPLogBucket) types.DataSource {
+	status := "active"
+	switch strings.ToUpper(bucket.LifecycleState) {
+	case "DELETE_REQUESTED":
+		status = "disabled"
+	case "CREATING":
+		status = "pending"
+	case "FAILED":
+		status = "failed"
+	}
+
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(status == "active")
+	if bucket.RetentionDays > 0 {
+		n.RetentionDays = intPtr(bucket.RetentionDays)
+	}
+
+	return types.DataSource{
+		ID:          "bucket:" + bucket.Name,
+		Name:        lastPathSegment(bucket.Name),
+		Title:       lastPathSegment(bucket.Name),
+		Type:        "gcplogging-bucket",
+		Description: bucket.Description,
+		Status:      status,
+		Tags:        []string{"gcplogging", "bucket"},
+		Normalized:  n,
+		Metadata: map[string]interface{}{
+			"project":          project,
+			"location":         gcpResourceLocation(bucket.Name),
+			"retentionDays":    bucket.RetentionDays,
+			"locked":           bucket.Locked,
+			"lifecycleState":   bucket.LifecycleState,
+			"analyticsEnabled": bucket.AnalyticsEnabled,
+		},
+	}
+}
+
+// convertSink maps a sink. Sinks whose filter selects Cloud Audit Logs are
+// tagged so audit trails routed away from the SIEM stand out.
+func (g *GCPLoggingProvider) convertSink(project string, sink GCPLogSink, bucketIDs map[string]bool) types.DataSource {
+	status := "active"
+	if sink.Disabled {
+		status = "disabled"
+	}
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(!sink.Disabled)
+
+	destinationType := gcpSinkDestinationType(sink.Destination)
+	tags := []string{"gcplogging", "sink", "destination:" + destinationType}
+	auditLogs := gcpFilterSelectsAuditLogs(sink.Filter)
+	if auditLogs {
+		tags = append(tags, "audit-logs")
+	}
+
+	exclusions := make([]map[string]interface{}, 0, len(sink.Exclusions))
+	for _, exclusion := range sink.Exclusions {
+		exclusions = append(exclusions, map[string]interface{}{
+			"name":     exclusion.Name,
+			"filter":   exclusion.Filter,
+			"disabled": exclusion.Disabled,
+		})
+	}
+
+	ds := types.DataSource{
+		ID:          "sink:" + project + "/" + sink.Name,
+		Name:        sink.Name,
+		Title:       sink.Name,
+		Type:        "gcplogging-sink",
+		Pattern:     sink.Filter,
+		Description: sink.Description,
+		Status:      status,
+		Tags:        tags,
+		Normalized:  n,
+		Metadata: map[string]interface{}{
+			"project":         project,
+			"destination":     sink.Destination,
+			"destinationType": destinationType,
+			"filter":          sink.Filter,
+			"includeChildren": sink.IncludeChildren,
+			"writerIdentity":  sink.WriterIdentity,
+			"exclusions":      exclusions,
+			"auditLogs":       auditLogs,
+		},
+	}
+
+	bucket := strings.TrimPrefix(sink.Destination, "logging.googleapis.com/")
+	if bucketIDs[bucket] {
+		ds.Relations = []types.Relation{{Type: types.RelationRoutesTo, TargetID: "bucket:" + bucket}}
+	}
+	return ds
+}
+
+func (g *GCPLoggingProvider) convertExclusion(project string, exclusion GCPLogExclusion) types.DataSource {
+	status := "active"
+	if exclusion.Disabled {
+		status = "disabled"
+	}
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(!exclusion.Disabled)
+
+	tags := []string{"gcplogging", "exclusion"}
+	if gcpFilterSelectsAuditLogs(exclusion.Filter) {
+		tags = append(tags, "audit-logs")
+	}
+
+	return types.DataSource{
+		ID:          "exclusion:" + project + "/" + exclusion.Name,
+		Name:        exclusion.Name,
+		Title:       exclusion.Name,
+		Type:        "gcplogging-exclusion",
+		Pattern:     exclusion.Filter,
+		Description: exclusion.Description,
+		Status:      status,
+		Tags:        tags,
+		Normalized:  n,
+		Metadata: map[string]interface{}{
+			"project": project,
+			"filter":  exclusion.Filter,
+		},
+	}
+}
+
+// gcpFilterSelectsAuditLogs reports whether a filter has a term on the Cloud
+// Audit Logs that is not negated. Negation is tracked through parenthesized
+// groups and function calls, so neither the _Default sink's
+// NOT LOG_ID("cloudaudit.googleapis.com/activity") nor
+// NOT (LOG_ID("...activity") OR LOG_ID("...system_event")) counts.
+func gcpFilterSelectsAuditLogs(filter string) bool {
+	negated := []bool{false} // per parenthesis depth
+	pending := false         // NOT or - before the current term
+	inTerm := false          // a term just ended; another one is an implicit AND
+	open := false            // the last token was a comparison awaiting its value
+	for i := 0; i < len(filter); {
+		switch c := filter[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case c == '(':
+			negated = append(negated, negated[len(negated)-1] != pending)
+			pending, inTerm, open = false, false, false
+			i++
+			continue
+		case c == ')':
+			if len(negated) > 1 {
+				negated = negated[:len(negated)-1]
+			}
+			inTerm, open = true, false
+			i++
+			continue
+		}
+
+		token, quoted := gcpFilterToken(filter[i:])
+		i += len(token)
+		if !quoted {
+			switch token {
+			case "NOT":
+				pending, inTerm, open = !pending, false, false
+				continue
+			case "AND", "OR":
+				pending, inTerm, open = false, false, false
+				continue
+			}
+		}
+		if !open && (quoted || !strings.ContainsAny(token[:1], ":=<>!~")) {
+			if inTerm {
+				pending = false
+			}
+			if !quoted && strings.HasPrefix(token, "-") {
+				pending = !pending
+			}
+		}
+		if strings.Contains(token, gcpAuditLogServiceName) && negated[len(negated)-1] == pending {
+			return true
+		}
+		open = !quoted && strings.ContainsAny(token[len(token)-1:], ":=<>~")
+		inTerm = !open
+	}
+	return false
+}
+
+// gcpFilterToken returns the quoted string or bare word filter starts with
+func gcpFilterToken(filter string) (string, bool) {
+	if filter[0] == '"' {
+		for i := 1; i < len(filter); i++ {
+			switch filter[i] {
+			case '\\':
+				i++
+			case '"':
+				return filter[:i+1], true
+			}
+		}
+		return filter, true
+	}
+	end := strings.IndexAny(filter, " \t\n\r()\"")
+	if end < 0 {
+		return filter, false
+	}
+	return filter[:end], false
+}
+
+// gcpSinkDestinationType classifies a sink destination by its service
+func gcpSinkDestinationType(destination string) string {
+	switch {
+	case strings.HasPrefix(destination, "storage.googleapis.com/"):
+		return "cloud-storage"
+	case strings.HasPrefix(destination, "bigquery.googleapis.com/"):
+		return "bigquery"
+	case strings.HasPrefix(destination, "pubsub.googleapis.com/"):
+		return "pubsub"
+	case strings.HasPrefix(destination, "logging.googleapis.com/") && strings.Contains(destination, "/buckets/"):
+		return "log-bucket"
+	case strings.HasPrefix(destination, "logging.googleapis.com/"):
+		return "project"
+	}
+	return "other"
+}
+
+// gcpResourceLocation returns the location of a resource name such as
+// "projects/p/locations/global/buckets/_Default"
+func gcpResourceLocation(name string) string {
+	parts := strings.Split(name, "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == "locations" {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// getJSON performs an authenticated GET against the Cloud Logging API
+func (g *GCPLoggingProvider) getJSON(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if g.tokens != nil {
+		token, err := g.tokens.Token(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to obtain access token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	} else if g.config.Auth != nil && g.config.Auth.Type == "bearer" {
+		req.Header.Set("Authorization", "Bearer "+g.config.Auth.Token)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return readJSONResponse(resp, "gcplogging", out)
+}
+
+func (g *GCPLoggingProvider) ValidateConnection(ctx context.Context) error {
+	var resp GCPLogNamesResponse
+	if err := g.getJSON(ctx, g.baseURL+"/v2/projects/"+url.PathEscape(g.projects[0])+"/logs?pageSize=1", &resp); err != nil {
+		return fmt.Errorf("failed to connect to Cloud Logging: %w", err)
+	}
+	return nil
+}
+
+func (g *GCPLoggingProvider) GetCapabilities() types.ProviderCapabilities {
+	return types.ProviderCapabilities{
+		SupportsRealTimeQueries: false,
+		SupportsHistoricalData:  true,
+		SupportedDataTypes:      []string{"gcplogging-log", "gcplogging-bucket", "gcplogging-sink", "gcplogging-exclusion"},
+		RequiresAuthentication:  true,
+	}
+}
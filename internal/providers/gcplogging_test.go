@@ -0,0 +1,169 @@
+package providers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+func TestGCPLoggingFetchDataViews(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	responses := map[string]string{
+		"/v2/projects/secops-project/logs":    `{"logNames":["projects/secops-project/logs/cloudaudit.googleapis.com%2Factivity"],"nextPageToken":"p2"}`,
+		"/v2/projects/secops-project/logs?p2": `{"logNames":["projects/secops-project/logs/syslog"]}`,
+		"/v2/projects/secops-project/locations/-/buckets": `{"buckets":[
+			{"name":"projects/secops-project/locations/global/buckets/_Default","retentionDays":30,"lifecycleState":"ACTIVE"},
+			{"name":"projects/secops-project/locations/europe-west1/buckets/audit","retentionDays":400,"locked":true,"lifecycleState":"ACTIVE"}]}`,
+		"/v2/projects/secops-project/sinks": `{"sinks":[
+			{"name":"_Default","destination":"logging.googleapis.com/projects/secops-project/locations/global/buckets/_Default",
+			 "filter":"NOT LOG_ID(\"cloudaudit.googleapis.com/activity\")"},
+			{"name":"audit-to-bq","destination":"bigquery.googleapis.com/projects/secops-project/datasets/audit",
+			 "filter":"logName:\"cloudaudit.googleapis.com\"","writerIdentity":"serviceAccount:sink@gcp-sa-logging.iam.gserviceaccount.com",
+			 "exclusions":[{"name":"no-data-access","filter":"LOG_ID(\"cloudaudit.googleapis.com/data_access\")"}]},
+			{"name":"old","destination":"storage.googleapis.com/old-logs","disabled":true}]}`,
+		"/v2/projects/secops-project/exclusions":         `{"exclusions":[{"name":"drop-debug","filter":"severity<=DEBUG","disabled":true}]}`,
+		"/v2/projects/other-project/logs":                `{"logNames":[]}`,
+		"/v2/projects/other-project/locations/-/buckets": `{"buckets":[]}`,
+		"/v2/projects/other-project/sinks":               `{"sinks":[]}`,
+		"/v2/projects/other-project/exclusions":          `{}`,
+	}
+
+	tokenRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			tokenRequests++
+			r.ParseForm()
+			if claims := verifyJWT(t, r.Form.Get("assertion"), &key.PublicKey); claims["scope"] != gcpLoggingReadScope {
+				t.Errorf("unexpected scope %v", claims["scope"])
+			}
+			w.Write([]byte(`{"access_token":"ya29.test","expires_in":3600}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer ya29.test" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		path := r.URL.Path
+		if token := r.URL.Query().Get("pageToken"); token != "" {
+			path += "?" + token
+		}
+		body, ok := responses[path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	provider, err := NewGCPLoggingProvider(types.ProviderConfig{
+		Type:     "gcplogging",
+		Endpoint: server.URL,
+		Options:  map[string]string{"projects": "secops-project, other-project"},
+		Auth: &types.AuthConfig{
+			Type:            "service_account",
+			CredentialsFile: writeServiceAccountKey(t, key, server.URL+"/token"),
+		},
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sources, err := provider.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+	if tokenRequests != 1 {
+		t.Errorf("expected the access token to be cached, got %d token requests", tokenRequests)
+	}
+
+	byID := make(map[string]types.DataSource)
+	for _, ds := range sources {
+		byID[ds.ID] = ds
+	}
+	if len(byID) != 8 {
+		t.Fatalf("expected 8 data sources, got %d", len(byID))
+	}
+
+	audit := byID["log:secops-project/cloudaudit.googleapis.com/activity"]
+	if audit.Type != "gcplogging-log" || !strings.Contains(strings.Join(audit.Tags, ","), "audit-logs") {
+		t.Errorf("unexpected audit log %+v", audit)
+	}
+	if _, ok := byID["log:secops-project/syslog"]; !ok {
+		t.Error("second page of log names was not fetched")
+	}
+
+	bucket := byID["bucket:projects/secops-project/locations/europe-west1/buckets/audit"]
+	if bucket.Normalized.RetentionDays == nil || *bucket.Normalized.RetentionDays != 400 ||
+		bucket.Metadata["location"] != "europe-west1" || bucket.Metadata["locked"] != true {
+		t.Errorf("unexpected bucket %+v", bucket)
+	}
+
+	defaultSink := byID["sink:secops-project/_Default"]
+	if defaultSink.Metadata["auditLogs"] != false {
+		t.Error("a negated audit log term should not flag the _Default sink")
+	}
+	if len(defaultSink.Relations) != 1 || defaultSink.Relations[0].TargetID != "bucket:projects/secops-project/locations/global/buckets/_Default" {
+		t.Errorf("expected _Default sink to route to its bucket, got %+v", defaultSink.Relations)
+	}
+
+	bq := byID["sink:secops-project/audit-to-bq"]
+	if bq.Metadata["destinationType"] != "bigquery" || bq.Metadata["auditLogs"] != true || !strings.Contains(strings.Join(bq.Tags, ","), "audit-logs") {
+		t.Errorf("audit sink routing to BigQuery should be flagged, got %+v", bq)
+	}
+	if exclusions, ok := bq.Metadata["exclusions"].([]map[string]interface{}); !ok || len(exclusions) != 1 {
+		t.Errorf("unexpected sink exclusions %v", bq.Metadata["exclusions"])
+	}
+	if len(bq.Relations) != 0 {
+		t.Errorf("sinks outside Cloud Logging should have no relations, got %+v", bq.Relations)
+	}
+
+	if ds := byID["sink:secops-project/old"]; ds.Status != "disabled" || ds.Metadata["destinationType"] != "cloud-storage" {
+		t.Errorf("unexpected disabled sink %+v", ds)
+	}
+	if ds := byID["exclusion:secops-project/drop-debug"]; ds.Status != "disabled" || ds.Pattern != "severity<=DEBUG" {
+		t.Errorf("unexpected exclusion %+v", ds)
+	}
+}
+
+func TestGCPFilterSelectsAuditLogs(t *testing.T) {
+	cases := map[string]bool{
+		`LOG_ID("cloudaudit.googleapis.com/activity")`:                                                             true,
+		`logName:"cloudaudit.googleapis.com"`:                                                                      true,
+		`logName : "projects/p/logs/cloudaudit.googleapis.com%2Factivity" OR severity>=ERROR`:                      true,
+		`NOT LOG_ID("cloudaudit.googleapis.com/activity") AND NOT LOG_ID("externalaudit.googleapis.com/activity")`: false,
+		`-logName:"cloudaudit.googleapis.com"`:                                                                     false,
+		`NOT (LOG_ID("cloudaudit.googleapis.com/activity") OR LOG_ID("cloudaudit.googleapis.com/system_event"))`:   false,
+		`NOT (severity<ERROR) LOG_ID("cloudaudit.googleapis.com/activity")`:                                        true,
+		`NOT LOG_ID("cloudaudit.googleapis.com/activity") OR LOG_ID("cloudaudit.googleapis.com/data_access")`:      true,
+		`resource.type="gce_instance"`:                                                                             false,
+	}
+	for filter, want := range cases {
+		if got := gcpFilterSelectsAuditLogs(filter); got != want {
+			t.Errorf("%s: got %v, want %v", filter, got, want)
+		}
+	}
+}
+
+func TestGCPLoggingRequiresProject(t *testing.T) {
+	_, err := NewGCPLoggingProvider(types.ProviderConfig{
+		Type:     "gcplogging",
+		Endpoint: "https://logging.googleapis.com",
+		Auth:     &types.AuthConfig{Type: "bearer", Token: "ya29.test"},
+	})
+	if err == nil {
+		t.Fatal("expected an error without options.projects")
+	}
+}
@@ -0,0 +1,181 @@
+package providers
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+// googleTokenURL is Google's OAuth token endpoint, used when neither the
+// auth config nor the key file names one
+const googleTokenURL = "https://oauth2.googleapis.com/token"
+
+// googleCloudPlatformScope grants access to all Google Cloud APIs the
+// service account has IAM permissions for
+const googleCloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// googleServiceAccount is the subset of a service-account JSON key we need
+type googleServiceAccount struct {
+	Type         string `json:"type"`
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// googleTokenSource exchanges a locally signed JWT for an access token
+// (RFC 7523) and caches it until it expires
+type googleTokenSource struct {
+	client   *http.Client
+	account  googleServiceAccount
+	key      *rsa.PrivateKey
+	tokenURL string
+	scopes   []string
+	now      func() time.Time
+
+	mu    sync.Mutex
+	token cachedToken
+}
+
+// newGoogleTokenSource loads the service-account key named by
+// auth.credentials_file. auth.token_url overrides the key's token_uri and
+// auth.scopes overrides defaultScopes.
+func newGoogleTokenSource(client *http.Client, auth *types.AuthConfig, defaultScopes ...string) (*googleTokenSource, error) {
+	if auth == nil || auth.CredentialsFile == "" {
+		return nil, fmt.Errorf("service_account auth requires credentials_file")
+	}
+	data, err := readRelativeFile(auth.CredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read service account key: %w", err)
+	}
+	account, key, err := parseGoogleServiceAccount(data)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenURL := auth.TokenURL
+	if tokenURL == "" {
+		tokenURL = account.TokenURI
+	}
+	if tokenURL == "" {
+		tokenURL = googleTokenURL
+	}
+	scopes := auth.Scopes
+	if len(scopes) == 0 {
+		scopes = defaultScopes
+	}
+
+	return &googleTokenSource{
+		client:   client,
+		account:  account,
+		key:      key,
+		tokenURL: tokenURL,
+		scopes:   scopes,
+		now:      time.Now,
+	}, nil
+}
+
+// parseGoogleServiceAccount decodes a JSON key and its PEM private key
+func parseGoogleServiceAccount(data []byte) (googleServiceAccount, *rsa.PrivateKey, error) {
+	var account googleServiceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return account, nil, fmt.Errorf("invalid service account key: %w", err)
+	}
+	if account.Type != "" && account.Type != "service_account" {
+		return account, nil, fmt.Errorf("credentials file is a %q key, expected service_account", account.Type)
+	}
+	if account.ClientEmail == "" || account.PrivateKey == "" {
+		return account, nil, fmt.Errorf("service account key requires client_email and private_key")
+	}
+
+	block, _ := pem.Decode([]byte(account.PrivateKey))
+	if block == nil {
+		return account, nil, fmt.Errorf("service account private_key is not PEM encoded")
+	}
+	var key *rsa.PrivateKey
+	if parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		rsaKey, ok := parsed.(*rsa.PrivateKey)
+		if !ok {
+			return account, nil, fmt.Errorf("service account private_key is not an RSA key")
+		}
+		key = rsaKey
+	} else if rsaKey, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		key = rsaKey
+	} else {
+		return account, nil, fmt.Errorf("failed to parse service account private_key: %w", err)
+	}
+	return account, key, nil
+}
+
+// Token returns a cached access token, fetching a new one when needed
+func (g *googleTokenSource) Token(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	if g.token.valid(now) {
+		return g.token.value, nil
+	}
+
+	assertion, err := g.assertion(now)
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+
+	resp, err := requestToken(ctx, g.client, g.tokenURL, form, "google oauth")
+	if err != nil {
+		return "", err
+	}
+	g.token.set(resp, now)
+	return g.token.value, nil
+}
+
+// assertion builds the RS256-signed JWT presented to the token endpoint
+func (g *googleTokenSource) assertion(now time.Time) (string, error) {
+	header := map[string]string{"alg": "RS256", "typ": "JWT"}
+	if g.account.PrivateKeyID != "" {
+		header["kid"] = g.account.PrivateKeyID
+	}
+	claims := map[string]interface{}{
+		"iss":   g.account.ClientEmail,
+		"scope": strings.Join(g.scopes, " "),
+		"aud":   g.tokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, g.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign JWT: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
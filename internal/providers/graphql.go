@@ -0,0 +1,77 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// graphQLClient posts GraphQL queries to a single endpoint. authorize adds
+// credentials to each request and may be nil.
+type graphQLClient struct {
+	client    *http.Client
+	endpoint  string
+	vendor    string
+	authorize func(ctx context.Context, req *http.Request) error
+}
+
+// graphQLError is an entry of the GraphQL response "errors" list
+type graphQLError struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path"`
+}
+
+// query runs a GraphQL query and decodes its "data" object into out. A
+// response with errors fails even if it also carries partial data.
+func (g *graphQLClient) query(ctx context.Context, query string, variables map[string]interface{}, out interface{}) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode GraphQL request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", g.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if g.authorize != nil {
+		if err := g.authorize(ctx, req); err != nil {
+			return err
+		}
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphQLError  `json:"errors"`
+	}
+	if err := readJSONResponse(resp, g.vendor, &result); err != nil {
+		return err
+	}
+	if len(result.Errors) > 0 {
+		messages := make([]string, 0, len(result.Errors))
+		for _, e := range result.Errors {
+			messages = append(messages, e.Message)
+		}
+		return fmt.Errorf("%s GraphQL error: %s", g.vendor, strings.Join(messages, "; "))
+	}
+	if out == nil || len(result.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(result.Data, out); err != nil {
+		return fmt.Errorf("failed to decode GraphQL data: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+func newGraylogStandIn(t *testing.T) *httptest.Server {
+	t.Helper()
+	responses := map[string]string{
+		"/api/streams": `{"total":3,"streams":[
+			{"id":"s0","title":"All messages","is_default":true,"index_set_id":"is0","rules":[]},
+			{"id":"s1","title":"Firewall","index_set_id":"is1","remove_matches_from_default_stream":true,
+			 "rules":[{"field":"gl2_source_input","value":"in1","type":1}]},
+			{"id":"s2","title":"Old","disabled":true,"index_set_id":"is0","rules":[]}]}`,
+		"/api/system/inputs": `{"total":2,"inputs":[
+			{"id":"in1","title":"Syslog UDP","type":"org.graylog2.inputs.syslog.udp.SyslogUDPInput","global":true,
+			 "attributes":{"port":1514,"bind_address":"0.0.0.0"}},
+			{"id":"in2","title":"Beats","type":"org.graylog.plugins.beats.Beats2Input","node":"node-a",
+			 "attributes":{"port":5044}}]}`,
+		"/api/cluster/inputstates": `{"node-a":[{"id":"in1","state":"RUNNING"},{"id":"in2","state":"FAILED"}],
+			"node-b":[{"id":"in1","state":"STOPPED"}]}`,
+		"/api/system/indices/index_sets": `{"total":2,"index_sets":[
+			{"id":"is0","title":"Default","index_prefix":"graylog","writable":true,"default":true,
+			 "rotation_strategy_class":"org.graylog2.indexer.rotation.strategies.TimeBasedRotationStrategy",
+			 "rotation_strategy":{"type":"org.graylog2.indexer.rotation.strategies.TimeBasedRotationStrategyConfig","rotation_period":"P1D"},
+			 "retention_strategy_class":"org.graylog2.indexer.retention.strategies.DeletionRetentionStrategy",
+			 "retention_strategy":{"type":"org.graylog2.indexer.retention.strategies.DeletionRetentionStrategyConfig","max_number_of_indices":14}},
+			{"id":"is1","title":"Firewall","index_prefix":"fw","writable":true,
+			 "rotation_strategy":{"type":"org.graylog2.indexer.rotation.strategies.TimeBasedSizeOptimizingStrategyConfig","index_lifetime_max":"P90D"},
+			 "retention_strategy":{"type":"org.graylog2.indexer.retention.strategies.DeletionRetentionStrategyConfig"}}],
+			"stats":{"is1":{"indices":3,"documents":1200,"size":40960}}}`,
+		"/api/system/pipelines/pipeline":    `[{"id":"p1","title":"Normalize firewall","stages":[{"stage":0,"rules":["parse cef"]}]}]`,
+		"/api/system/pipelines/connections": `[{"stream_id":"s1","pipeline_ids":["p1"]}]`,
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "graylog-token" || pass != "token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+}
+
+func TestGraylogFetchDataViews(t *testing.T) {
+	server := newGraylogStandIn(t)
+	defer server.Close()
+
+	provider, err := NewGraylogProvider(types.ProviderConfig{
+		Type:     "graylog",
+		Endpoint: server.URL,
+		Auth:     &types.AuthConfig{Type: "bearer", Token: "graylog-token"},
+		Timeout:  5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, err := provider.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+
+	byID := make(map[string]types.DataSource)
+	for _, ds := range sources {
+		byID[ds.ID] = ds
+	}
+	if len(byID) != 8 {
+		t.Fatalf("expected 8 data sources, got %d", len(byID))
+	}
+
+	syslog := byID["input:in1"]
+	if syslog.Status != "degraded" || syslog.Metadata["port"] != float64(1514) {
+		t.Errorf("unexpected syslog input %+v", syslog)
+	}
+	if len(syslog.Relations) != 1 || syslog.Relations[0].TargetID != "stream:s1" {
+		t.Errorf("syslog input should route only to the firewall stream, got %+v", syslog.Relations)
+	}
+	beats := byID["input:in2"]
+	if beats.Status != "failed" || len(beats.Relations) != 1 || beats.Relations[0].TargetID != "stream:s0" {
+		t.Errorf("beats input should be failed and routed to the default stream, got %s %+v", beats.Status, beats.Relations)
+	}
+	if beats.Normalized.Enabled == nil || !*beats.Normalized.Enabled {
+		t.Error("a failed input is not disabled")
+	}
+
+	firewall := byID["stream:s1"]
+	want := []types.Relation{
+		{Type: types.RelationStoredIn, TargetID: "index-set:is1"},
+		{Type: types.RelationProcessedBy, TargetID: "pipeline:p1"},
+	}
+	if len(firewall.Relations) != len(want) || firewall.Relations[0] != want[0] || firewall.Relations[1] != want[1] {
+		t.Errorf("unexpected firewall stream relations %+v", firewall.Relations)
+	}
+	if firewall.Normalized.RetentionDays == nil || *firewall.Normalized.RetentionDays != 90 {
+		t.Errorf("expected stream retention from its index set, got %v", firewall.Normalized.RetentionDays)
+	}
+	if ds := byID["stream:s2"]; ds.Status != "disabled" {
+		t.Errorf("expected disabled stream, got %s", ds.Status)
+	}
+
+	defaultSet := byID["index-set:is0"]
+	if defaultSet.Normalized.RetentionDays == nil || *defaultSet.Normalized.RetentionDays != 14 {
+		t.Errorf("expected 14 day retention, got %v", defaultSet.Normalized.RetentionDays)
+	}
+	if defaultSet.Metadata["rotationStrategy"] != "TimeBasedRotationStrategy" {
+		t.Errorf("unexpected rotation strategy %v", defaultSet.Metadata["rotationStrategy"])
+	}
+	if ds := byID["index-set:is1"]; ds.Normalized.EventCount == nil || *ds.Normalized.EventCount != 1200 {
+		t.Errorf("expected index set stats, got %v", ds.Normalized.EventCount)
+	}
+}
+
+func TestParseISODuration(t *testing.T) {
+	cases := map[string]time.Duration{
+		"P1D":     24 * time.Hour,
+		"PT6H":    6 * time.Hour,
+		"P1W":     7 * 24 * time.Hour,
+		"P1DT12H": 36 * time.Hour,
+	}
+	for value, want := range cases {
+		if got, ok := parseISODuration(value); !ok || got != want {
+			t.Errorf("parseISODuration(%q) = %v, %v; want %v", value, got, ok, want)
+		}
+	}
+	for _, value := range []string{"", "P", "PT", "P1M", "1D"} {
+		if _, ok := parseISODuration(value); ok {
+			t.Errorf("parseISODuration(%q) should fail", value)
+		}
+	}
+}
+
+func TestGraylogInputStreamRules(t *testing.T) {
+	var streams []GraylogStream
+	if err := json.Unmarshal([]byte(`[
+		{"id":"default","is_default":true},
+		{"id":"exact","rules":[{"field":"gl2_source_input","value":"in1","type":1}],"remove_matches_from_default_stream":true},
+		{"id":"regex","rules":[{"field":"gl2_source_input","value":"^in","type":2}]},
+		{"id":"inverted","rules":[{"field":"gl2_source_input","value":"in2","type":1,"inverted":true}]},
+		{"id":"disabled","disabled":true,"rules":[{"field":"gl2_source_input","value":"in2","type":1}]}]`), &streams); err != nil {
+		t.Fatal(err)
+	}
+
+	routes := graylogInputStreams(streams)
+	if !reflect.DeepEqual(routes["in1"], []string{"exact"}) {
+		t.Errorf("in1 should route to the exact stream only, got %v", routes["in1"])
+	}
+	if _, ok := routes["in2"]; ok {
+		t.Errorf("in2 is only named by inverted and disabled streams, got %v", routes["in2"])
+	}
+	if _, ok := routes["^in"]; ok {
+		t.Error("a regex value must not be treated as an input ID")
+	}
+
+	if got := graylogConditionalInputRules(streams[2]); !reflect.DeepEqual(got, []string{"regex:^in"}) {
+		t.Errorf("unexpected conditional rules %v", got)
+	}
+	if got := graylogConditionalInputRules(streams[3]); !reflect.DeepEqual(got, []string{"!exact:in2"}) {
+		t.Errorf("unexpected conditional rules %v", got)
+	}
+}
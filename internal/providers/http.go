@@ -0,0 +1,117 @@
+package providers
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/logfiend/internal/types"
+)
+
+// newHTTPClient builds an HTTP client with the provider's timeout and TLS
+// settings, including a custom CA bundle and client certificate
+func newHTTPClient(config types.ProviderConfig) (*http.Client, error) {
+	client := &http.Client{
+		Timeout: config.Timeout,
+	}
+
+	if config.TLS == nil || !config.TLS.Enabled {
+		return client, nil
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: config.TLS.InsecureSkipVerify,
+	}
+
+	if config.TLS.CAFile != "" {
+		caPEM, err := readRelativeFile(config.TLS.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", config.TLS.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.TLS.CertFile != "" || config.TLS.KeyFile != "" {
+		if filepath.IsAbs(config.TLS.CertFile) || filepath.IsAbs(config.TLS.KeyFile) {
+			return nil, fmt.Errorf("absolute paths not allowed for security")
+		}
+		cert, err := tls.LoadX509KeyPair(filepath.Clean(config.TLS.CertFile), filepath.Clean(config.TLS.KeyFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	client.Transport = &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: tlsConfig,
+	}
+	return client, nil
+}
+
+// readRelativeFile reads a file referenced from config, which must be relative
+func readRelativeFile(path string) ([]byte, error) {
+	cleanPath := filepath.Clean(path)
+	if filepath.IsAbs(cleanPath) {
+		return nil, fmt.Errorf("absolute paths not allowed for security: %s", cleanPath)
+	}
+	return os.ReadFile(cleanPath)
+}
+
+// readJSONResponse checks for a 2xx status and decodes the body into out.
+// vendor names the system in error messages, e.g. "opensearch returned status 401".
+func readJSONResponse(resp *http.Response, vendor string, out interface{}) error {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &statusError{vendor: vendor, code: resp.StatusCode, body: string(body)}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// readXMLResponse is readJSONResponse for the AWS query and S3 APIs, which
+// answer in XML
+func readXMLResponse(resp *http.Response, vendor string, out interface{}) error {
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &statusError{vendor: vendor, code: resp.StatusCode, body: string(body)}
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// statusError is returned for non-2xx responses so callers can react to
+// specific codes such as 404 for optional APIs
+type statusError struct {
+	vendor string
+	code   int
+	body   string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s returned status %d: %s", e.vendor, e.code, e.body)
+}
+
+// isStatus reports whether err is a statusError with the given code
+func isStatus(err error, code int) bool {
+	var statusErr *statusError
+	return errors.As(err, &statusErr) && statusErr.code == code
+}
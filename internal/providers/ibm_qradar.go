@@ -44,6 +44,12 @@ type QRadarLogSource struct {
 	AverageEPS          int    `json:"average_eps"`
 }
 
+// QRadarLogSourceType represents a QRadar log source type (DSM)
+type QRadarLogSourceType struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
 // NewQRadarProvider creates a new QRadar provider
 func NewQRadarProvider(config types.ProviderConfig) (types.Provider, error) {
 	client := &http.Client{
@@ -117,17 +123,66 @@ func (q *QRadarProvider) fetchLogSources(ctx context.Context) ([]types.DataSourc
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
+	// Resolve DSM type names; inventory still succeeds without them, but each
+	// source is marked so the missing names are not mistaken for unknown types
+	typeNames, typeErr := q.fetchLogSourceTypes(ctx)
+	if typeErr != nil {
+		typeNames = map[int]string{}
+	}
+
 	// Convert to DataSource objects
 	dataSources := make([]types.DataSource, 0, len(logSources))
 	for _, logSource := range logSources {
-		ds := q.convertToDataSource(logSource)
+		ds := q.convertToDataSource(logSource, typeNames)
+		if typeErr != nil {
+			ds.Metadata["typeResolution"] = "failed"
+			ds.Metadata["typeResolutionError"] = typeErr.Error()
+		}
 		dataSources = append(dataSources, ds)
 	}
 
 	return dataSources, nil
 }
 
-func (q *QRadarProvider) convertToDataSource(logSource QRadarLogSource) types.DataSource {
+// fetchLogSourceTypes returns DSM type names keyed by type ID
+func (q *QRadarProvider) fetchLogSourceTypes(ctx context.Context) (map[int]string, error) {
+	baseURL := strings.TrimSuffix(q.config.Endpoint, "/")
+	endpoint := fmt.Sprintf("%s/api/config/event_sources/log_source_management/log_source_types?fields=id,name", baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Version", "15.0")
+
+	if q.config.Auth != nil {
+		q.addAuth(req)
+	}
+
+	resp, err := q.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("qradar returned status %d for log source types", resp.StatusCode)
+	}
+
+	var logSourceTypes []QRadarLogSourceType
+	if err := json.NewDecoder(resp.Body).Decode(&logSourceTypes); err != nil {
+		return nil, fmt.Errorf("failed to decode log source types: %w", err)
+	}
+
+	typeNames := make(map[int]string, len(logSourceTypes))
+	for _, lst := range logSourceTypes {
+		typeNames[lst.ID] = lst.Name
+	}
+	return typeNames, nil
+}
+
+func (q *QRadarProvider) convertToDataSource(logSource QRadarLogSource, typeNames map[int]string) types.DataSource {
 	ds := types.DataSource{
 		ID:          fmt.Sprintf("%d", logSource.ID),
 		Name:        logSource.Name,
@@ -195,6 +250,21 @@ func (q *QRadarProvider) convertToDataSource(logSource QRadarLogSource) types.Da
 		ds.Metadata["statusMessages"] = logSource.Status.Messages
 	}
 
+	typeName := typeNames[logSource.TypeID]
+	if typeName != "" {
+		ds.Metadata["typeName"] = typeName
+	}
+
+	// QRadar already reports typed values; only units need converting
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(logSource.Enabled)
+	n.IngestRateEPS = float64Ptr(float64(logSource.AverageEPS))
+	n.Product = typeName
+	if logSource.LastEventTime > 0 {
+		n.LastEventTime = timePtr(unixMillis(logSource.LastEventTime))
+	}
+	ds.Normalized = n
+
 	return ds
 }
 
@@ -238,11 +308,79 @@ func (q *QRadarProvider) ValidateConnection(ctx context.Context) error {
 	return nil
 }
 
+// QRadarRule represents a QRadar custom rule
+type QRadarRule struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	Enabled      bool   `json:"enabled"`
+	Origin       string `json:"origin"`
+	Owner        string `json:"owner"`
+	ModifiedDate int64  `json:"modification_date"`
+}
+
+// FetchDetectionRules lists QRadar custom rules. The rules API does not expose
+// rule tests, so QRadar rules carry no query and cannot be dependency-checked.
+func (q *QRadarProvider) FetchDetectionRules(ctx context.Context) ([]types.DetectionRule, error) {
+	baseURL := strings.TrimSuffix(q.config.Endpoint, "/")
+	endpoint := fmt.Sprintf("%s/api/analytics/rules?fields=id,name,type,enabled,origin,owner,modification_date", baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Version", "15.0")
+
+	if q.config.Auth != nil {
+		q.addAuth(req)
+	}
+
+	resp, err := q.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("qradar returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var qradarRules []QRadarRule
+	if err := json.NewDecoder(resp.Body).Decode(&qradarRules); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	rules := make([]types.DetectionRule, 0, len(qradarRules))
+	for _, rule := range qradarRules {
+		metadata := map[string]interface{}{
+			"ruleType": rule.Type,
+			"origin":   rule.Origin,
+			"owner":    rule.Owner,
+		}
+		if rule.ModifiedDate > 0 {
+			metadata["modifiedDate"] = unixMillis(rule.ModifiedDate).Format(time.RFC3339)
+		}
+
+		rules = append(rules, types.DetectionRule{
+			ID:       fmt.Sprintf("%d", rule.ID),
+			Name:     rule.Name,
+			Type:     "qradar-rule",
+			Enabled:  rule.Enabled,
+			Metadata: metadata,
+		})
+	}
+
+	return rules, nil
+}
+
 func (q *QRadarProvider) GetCapabilities() types.ProviderCapabilities {
 	return types.ProviderCapabilities{
 		SupportsRealTimeQueries: true,
 		SupportsHistoricalData:  true,
 		SupportedDataTypes:      []string{"qradar-log-source", "qradar-flow-source"},
 		RequiresAuthentication:  true,
+		SupportsDetectionRules:  true,
 	}
 }
@@ -0,0 +1,49 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+func TestQRadarTypeResolutionFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/config/event_sources/log_source_management/log_sources":
+			w.Write([]byte(`[{"id":42,"name":"Firewall","type_id":4,"enabled":true}]`))
+		case "/api/config/event_sources/log_source_management/log_source_types":
+			http.Error(w, `{"message":"insufficient capabilities"}`, http.StatusForbidden)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewQRadarProvider(types.ProviderConfig{
+		Type:     "qradar",
+		Endpoint: server.URL,
+		Auth:     &types.AuthConfig{Type: "api_key", APIKey: "key"},
+		Timeout:  5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, err := provider.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+	if len(sources) != 1 {
+		t.Fatalf("expected 1 log source, got %d", len(sources))
+	}
+	ds := sources[0]
+	if ds.Metadata["typeResolution"] != "failed" || ds.Metadata["typeResolutionError"] == nil {
+		t.Errorf("expected the failed type lookup to be recorded, got %v", ds.Metadata)
+	}
+	if _, ok := ds.Metadata["typeName"]; ok {
+		t.Errorf("unexpected typeName %v", ds.Metadata["typeName"])
+	}
+}
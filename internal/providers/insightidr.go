@@ -0,0 +1,340 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+// InsightIDRProvider implements the Provider interface for Rapid7
+// InsightIDR. Event sources and collectors come from the health metrics API;
+// log sets come from the Log Search management API.
+type InsightIDRProvider struct {
+	config       types.ProviderConfig
+	client       *http.Client
+	baseURL      string
+	logSearchURL string
+}
+
+// InsightIDRHealthMetricsResponse represents the /idr/v1/health-metrics response
+type InsightIDRHealthMetricsResponse struct {
+	Data     []json.RawMessage `json:"data"`
+	Metadata struct {
+		Index      int `json:"index"`
+		Size       int `json:"size"`
+		TotalData  int `json:"total_data"`
+		TotalPages int `json:"total_pages"`
+	} `json:"metadata"`
+}
+
+// InsightIDRCollector represents a collector's health
+type InsightIDRCollector struct {
+	RRN        string `json:"rrn"`
+	Name       string `json:"name"`
+	State      string `json:"state"`
+	LastActive string `json:"last_active"`
+	Issue      string `json:"issue"`
+	Version    string `json:"version"`
+}
+
+// InsightIDREventSource represents an event source's health
+type InsightIDREventSource struct {
+	RRN           string `json:"rrn"`
+	Name          string `json:"name"`
+	State         string `json:"state"`
+	LastActive    string `json:"last_active"`
+	Issue         string `json:"issue"`
+	EventType     string `json:"event_source_type"`
+	CollectorRRN  string `json:"collector_rrn"`
+	CollectorName string `json:"collector_name"`
+}
+
+// InsightIDRLogsetsResponse represents the /management/logsets response
+type InsightIDRLogsetsResponse struct {
+	Logsets []struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		LogsInfo    []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"logs_info"`
+	} `json:"logsets"`
+}
+
+// insightRegions are the Insight platform regions
+var insightRegions = map[string]bool{
+	"us": true, "us2": true, "us3": true, "eu": true, "ca": true, "au": true, "ap": true,
+}
+
+// NewInsightIDRProvider creates a new InsightIDR provider. options.region
+// selects the regional API and Log Search endpoints in place of the
+// endpoint; setting both is an error.
+func NewInsightIDRProvider(config types.ProviderConfig) (types.Provider, error) {
+	client, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &InsightIDRProvider{
+		config:       config,
+		client:       client,
+		baseURL:      strings.TrimSuffix(config.Endpoint, "/"),
+		logSearchURL: strings.TrimSuffix(config.Options["log_search_url"], "/"),
+	}
+
+	if region := strings.ToLower(config.Options["region"]); region != "" {
+		if !insightRegions[region] {
+			return nil, fmt.Errorf("unknown Insight region: %s", region)
+		}
+		if provider.baseURL != "" {
+			return nil, fmt.Errorf("insightidr takes an endpoint or options.region, not both")
+		}
+		provider.baseURL = fmt.Sprintf("https://%s.api.insight.rapid7.com", region)
+		if provider.logSearchURL == "" {
+			provider.logSearchURL = fmt.Sprintf("https://%s.rest.logs.insight.rapid7.com", region)
+		}
+	}
+	if provider.logSearchURL == "" {
+		provider.logSearchURL = strings.Replace(provider.baseURL, ".api.insight.", ".rest.logs.insight.", 1)
+	}
+
+	return provider, nil
+}
+
+func (i *InsightIDRProvider) Name() string {
+	return "insightidr"
+}
+
+func (i *InsightIDRProvider) FetchDataViews(ctx context.Context) ([]types.DataSource, error) {
+	collectorItems, err := i.fetchHealthMetrics(ctx, "collectors")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch collectors: %w", err)
+	}
+	eventSourceItems, err := i.fetchHealthMetrics(ctx, "event_sources")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch event sources: %w", err)
+	}
+
+	dataSources := []types.DataSource{}
+	collectorStatus := make(map[string]string)
+	collectorNames := make(map[string]string)
+	for _, item := range collectorItems {
+		var collector InsightIDRCollector
+		if err := json.Unmarshal(item, &collector); err != nil {
+			return nil, fmt.Errorf("failed to decode collector: %w", err)
+		}
+		ds := i.convertCollector(collector)
+		collectorStatus[collector.RRN] = ds.Status
+		collectorNames[collector.Name] = collector.RRN
+		dataSources = append(dataSources, ds)
+	}
+
+	for _, item := range eventSourceItems {
+		var source InsightIDREventSource
+		if err := json.Unmarshal(item, &source); err != nil {
+			return nil, fmt.Errorf("failed to decode event source: %w", err)
+		}
+		collectorRRN := source.CollectorRRN
+		if collectorRRN == "" {
+			collectorRRN = collectorNames[source.CollectorName]
+		}
+		dataSources = append(dataSources, i.convertEventSource(source, collectorRRN, collectorStatus[collectorRRN]))
+	}
+
+	var logsets InsightIDRLogsetsResponse
+	if err := i.getJSON(ctx, i.logSearchURL+"/management/logsets", &logsets); err != nil {
+		return nil, fmt.Errorf("failed to fetch log sets: %w", err)
+	}
+	for _, logset := range logsets.Logsets {
+		logs := make([]string, 0, len(logset.LogsInfo))
+		for _, log := range logset.LogsInfo {
+			logs = append(logs, log.Name)
+		}
+		dataSources = append(dataSources, types.DataSource{
+			ID:          "logset:" + logset.ID,
+			Name:        logset.Name,
+			Title:       logset.Name,
+			Type:        "insightidr-logset",
+			Description: logset.Description,
+			Status:      "active",
+			Tags:        []string{"insightidr", "logset"},
+			Normalized:  types.NewNormalizedFields(),
+			Metadata: map[string]interface{}{
+				"logs":     logs,
+				"logCount": len(logs),
+			},
+		})
+	}
+
+	return dataSources, nil
+}
+
+// fetchHealthMetrics pages through the health metrics of one resource type
+func (i *InsightIDRProvider) fetchHealthMetrics(ctx context.Context, resourceType string) ([]json.RawMessage, error) {
+	const size = 100
+	items := []json.RawMessage{}
+	for index := 0; ; index++ {
+		params := url.Values{}
+		params.Set("resourceType", resourceType)
+		params.Set("index", strconv.Itoa(index))
+		params.Set("size", strconv.Itoa(size))
+
+		var resp InsightIDRHealthMetricsResponse
+		if err := i.getJSON(ctx, i.baseURL+"/idr/v1/health-metrics?"+params.Encode(), &resp); err != nil {
+			return nil, err
+		}
+		items = append(items, resp.Data...)
+		if len(resp.Data) < size || index+1 >= resp.Metadata.TotalPages {
+			return items, nil
+		}
+	}
+}
+
+func (i *InsightIDRProvider) convertCollector(collector InsightIDRCollector) types.DataSource {
+	status := insightHealthStatus(collector.State)
+	n := types.NewNormalizedFields()
+	n.Enabled = insightEnabled(status)
+
+	metadata := map[string]interface{}{
+		"rrn":   collector.RRN,
+		"state": collector.State,
+	}
+	if collector.Version != "" {
+		metadata["version"] = collector.Version
+	}
+	if collector.Issue != "" {
+		metadata["issue"] = collector.Issue
+	}
+	if collector.LastActive != "" {
+		metadata["lastActive"] = collector.LastActive
+	}
+
+	return types.DataSource{
+		ID:         "collector:" + collector.RRN,
+		Name:       collector.Name,
+		Title:      collector.Name,
+		Type:       "insightidr-collector",
+		Status:     status,
+		Tags:       []string{"insightidr", "collector"},
+		Normalized: n,
+		Metadata:   metadata,
+	}
+}
+
+// convertEventSource maps an event source. A healthy event source behind an
+// offline or unhealthy collector cannot deliver events, so it is degraded.
+func (i *InsightIDRProvider) convertEventSource(source InsightIDREventSource, collectorRRN, collectorStatus string) types.DataSource {
+	status := insightHealthStatus(source.State)
+	if status == "active" && collectorStatus != "" && collectorStatus != "active" {
+		status = "degraded"
+	}
+
+	n := types.NewNormalizedFields()
+	n.Enabled = insightEnabled(status)
+	if lastActive, err := time.Parse(time.RFC3339Nano, source.LastActive); err == nil {
+		n.LastEventTime = timePtr(lastActive)
+	}
+
+	metadata := map[string]interface{}{
+		"rrn":             source.RRN,
+		"state":           source.State,
+		"eventSourceType": source.EventType,
+	}
+	if source.CollectorName != "" {
+		metadata["collectorName"] = source.CollectorName
+	}
+	if collectorStatus != "" {
+		metadata["collectorStatus"] = collectorStatus
+	}
+	if source.Issue != "" {
+		metadata["issue"] = source.Issue
+	}
+
+	ds := types.DataSource{
+		ID:         "event-source:" + source.RRN,
+		Name:       source.Name,
+		Title:      source.Name,
+		Type:       "insightidr-event-source",
+		Status:     status,
+		Tags:       []string{"insightidr", "event-source"},
+		Normalized: n,
+		Metadata:   metadata,
+	}
+	if collectorRRN != "" {
+		ds.Relations = []types.Relation{{Type: types.RelationRoutesTo, TargetID: "collector:" + collectorRRN}}
+	}
+	return ds
+}
+
+// insightHealthStatus maps health metric states to DataSource statuses
+// insightEnabled reports a stopped collector or event source as disabled.
+// The health metrics carry no other configuration, so connectivity and
+// health states leave enabled unset.
+func insightEnabled(status string) *bool {
+	if status == "stopped" {
+		return boolPtr(false)
+	}
+	return nil
+}
+
+func insightHealthStatus(state string) string {
+	switch strings.ToUpper(state) {
+	case "RUNNING", "ONLINE", "ACTIVE", "HEALTHY", "OK":
+		return "active"
+	case "OFFLINE", "DISCONNECTED":
+		return "offline"
+	case "STOPPED":
+		return "stopped"
+	case "ERROR", "WARNING", "UNHEALTHY":
+		return "degraded"
+	case "":
+		return "unknown"
+	}
+	return strings.ToLower(state)
+}
+
+// getJSON performs a GET with the X-Api-Key header
+func (i *InsightIDRProvider) getJSON(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if i.config.Auth != nil {
+		req.Header.Set("X-Api-Key", i.config.Auth.APIKey)
+	}
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return readJSONResponse(resp, "insightidr", out)
+}
+
+func (i *InsightIDRProvider) ValidateConnection(ctx context.Context) error {
+	var resp InsightIDRHealthMetricsResponse
+	if err := i.getJSON(ctx, i.baseURL+"/idr/v1/health-metrics?resourceType=collectors&size=1", &resp); err != nil {
+		return fmt.Errorf("failed to connect to InsightIDR: %w", err)
+	}
+	return nil
+}
+
+func (i *InsightIDRProvider) GetCapabilities() types.ProviderCapabilities {
+	return types.ProviderCapabilities{
+		SupportsRealTimeQueries: true,
+		SupportsHistoricalData:  true,
+		SupportedDataTypes:      []string{"insightidr-event-source", "insightidr-collector", "insightidr-logset"},
+		RequiresAuthentication:  true,
+	}
+}
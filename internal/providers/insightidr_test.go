@@ -0,0 +1,109 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+func TestInsightIDRFetchDataViews(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "idr-key" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/idr/v1/health-metrics":
+			switch r.URL.Query().Get("resourceType") {
+			case "collectors":
+				w.Write([]byte(`{"data":[
+					{"rrn":"rrn:collector:1","name":"hq-collector","state":"RUNNING"},
+					{"rrn":"rrn:collector:2","name":"branch-collector","state":"OFFLINE","last_active":"2024-04-01T00:00:00Z"}],
+					"metadata":{"index":0,"size":100,"total_data":2,"total_pages":1}}`))
+			case "event_sources":
+				w.Write([]byte(`{"data":[
+					{"rrn":"rrn:es:1","name":"AD","state":"RUNNING","event_source_type":"Microsoft Active Directory","collector_rrn":"rrn:collector:1","last_active":"2024-05-01T12:00:00Z"},
+					{"rrn":"rrn:es:2","name":"Branch firewall","state":"RUNNING","event_source_type":"Palo Alto Firewall","collector_name":"branch-collector"}],
+					"metadata":{"index":0,"size":100,"total_data":2,"total_pages":1}}`))
+			}
+		case "/management/logsets":
+			w.Write([]byte(`{"logsets":[{"id":"ls1","name":"Firewall Activity","logs_info":[{"id":"l1","name":"Palo Alto"}]}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewInsightIDRProvider(types.ProviderConfig{
+		Type:     "insightidr",
+		Endpoint: server.URL,
+		Options:  map[string]string{"log_search_url": server.URL},
+		Auth:     &types.AuthConfig{Type: "api_key", APIKey: "idr-key"},
+		Timeout:  5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, err := provider.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+
+	byID := make(map[string]types.DataSource)
+	for _, ds := range sources {
+		byID[ds.ID] = ds
+	}
+	if len(byID) != 5 {
+		t.Fatalf("expected 5 data sources, got %d", len(byID))
+	}
+
+	if ds := byID["collector:rrn:collector:2"]; ds.Status != "offline" || ds.Normalized.Enabled != nil {
+		t.Errorf("expected an offline collector with no enabled state, got %s %v", ds.Status, ds.Normalized.Enabled)
+	}
+	ad := byID["event-source:rrn:es:1"]
+	if ad.Status != "active" || ad.Normalized.LastEventTime == nil || ad.Relations[0].TargetID != "collector:rrn:collector:1" {
+		t.Errorf("unexpected AD event source %+v", ad)
+	}
+	branch := byID["event-source:rrn:es:2"]
+	if branch.Status != "degraded" || branch.Metadata["collectorStatus"] != "offline" {
+		t.Errorf("event source behind an offline collector should be degraded, got %s", branch.Status)
+	}
+	if len(branch.Relations) != 1 || branch.Relations[0].TargetID != "collector:rrn:collector:2" {
+		t.Errorf("event source should be linked to its collector by name, got %+v", branch.Relations)
+	}
+	if ds := byID["logset:ls1"]; ds.Metadata["logCount"] != 1 {
+		t.Errorf("unexpected log set %+v", ds)
+	}
+}
+
+func TestInsightEnabled(t *testing.T) {
+	if enabled := insightEnabled(insightHealthStatus("STOPPED")); enabled == nil || *enabled {
+		t.Errorf("a stopped source should be disabled, got %v", enabled)
+	}
+	for _, state := range []string{"RUNNING", "OFFLINE", "ERROR"} {
+		if enabled := insightEnabled(insightHealthStatus(state)); enabled != nil {
+			t.Errorf("%s: health should not set enabled, got %v", state, *enabled)
+		}
+	}
+}
+
+func TestInsightIDRRegion(t *testing.T) {
+	provider, err := NewInsightIDRProvider(types.ProviderConfig{Options: map[string]string{"region": "EU"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := provider.(*InsightIDRProvider)
+	if p.baseURL != "https://eu.api.insight.rapid7.com" || p.logSearchURL != "https://eu.rest.logs.insight.rapid7.com" {
+		t.Errorf("unexpected regional URLs %s %s", p.baseURL, p.logSearchURL)
+	}
+	if _, err := NewInsightIDRProvider(types.ProviderConfig{Options: map[string]string{"region": "mars"}}); err == nil {
+		t.Error("expected error for unknown region")
+	}
+	if _, err := NewInsightIDRProvider(types.ProviderConfig{Endpoint: "https://example.test", Options: map[string]string{"region": "eu"}}); err == nil {
+		t.Error("expected error for both an endpoint and a region")
+	}
+}
@@ -0,0 +1,132 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a compiled subset of JSONPath: $, .name, ['name'], [n] and
+// [*] / .* wildcards. Paths without a leading $ are relative to the root, so
+// "entry" and "$.entry" are the same.
+type jsonPath []jsonPathStep
+
+type jsonPathStep struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// compileJSONPath parses a path expression
+func compileJSONPath(expr string) (jsonPath, error) {
+	rest := strings.TrimSpace(expr)
+	rest = strings.TrimPrefix(rest, "$")
+
+	var path jsonPath
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "[*]"):
+			path = append(path, jsonPathStep{wildcard: true})
+			rest = rest[3:]
+		case strings.HasPrefix(rest, ".*"):
+			path = append(path, jsonPathStep{wildcard: true})
+			rest = rest[2:]
+		case strings.HasPrefix(rest, "['") || strings.HasPrefix(rest, `["`):
+			quote := rest[1:2]
+			end := strings.Index(rest[2:], quote+"]")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated bracket in %q", expr)
+			}
+			path = append(path, jsonPathStep{key: rest[2 : 2+end]})
+			rest = rest[2+end+2:]
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated bracket in %q", expr)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid index %q in %q", rest[1:end], expr)
+			}
+			path = append(path, jsonPathStep{index: index, isIndex: true})
+			rest = rest[end+1:]
+		default:
+			rest = strings.TrimPrefix(rest, ".")
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty segment in %q", expr)
+			}
+			path = append(path, jsonPathStep{key: rest[:end]})
+			rest = rest[end:]
+		}
+	}
+	return path, nil
+}
+
+// find returns every value the path selects
+func (p jsonPath) find(root interface{}) []interface{} {
+	current := []interface{}{root}
+	for _, step := range p {
+		var next []interface{}
+		for _, value := range current {
+			switch v := value.(type) {
+			case map[string]interface{}:
+				if step.wildcard {
+					for _, child := range v {
+						next = append(next, child)
+					}
+				} else if child, ok := v[step.key]; ok && !step.isIndex {
+					next = append(next, child)
+				}
+			case []interface{}:
+				if step.wildcard {
+					next = append(next, v...)
+				} else if step.isIndex {
+					index := step.index
+					if index < 0 {
+						index += len(v)
+					}
+					if index >= 0 && index < len(v) {
+						next = append(next, v[index])
+					}
+				}
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+// first returns the first selected value
+func (p jsonPath) first(root interface{}) (interface{}, bool) {
+	values := p.find(root)
+	if len(values) == 0 {
+		return nil, false
+	}
+	return values[0], true
+}
+
+// jsonString renders a decoded JSON value as text; objects and arrays are
+// re-encoded
+func jsonString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
@@ -0,0 +1,311 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+// LogRhythmProvider implements the Provider interface for LogRhythm SIEM.
+// Log sources and System Monitor agents are listed through the Admin API.
+type LogRhythmProvider struct {
+	config   types.ProviderConfig
+	client   *http.Client
+	baseURL  string
+	pageSize int
+}
+
+// LogRhythmNamedRef is the {id, name} reference LogRhythm embeds in records
+type LogRhythmNamedRef struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// LogRhythmLogSource represents a log source from /lr-admin-api/logsources
+type LogRhythmLogSource struct {
+	ID                int               `json:"id"`
+	Name              string            `json:"name"`
+	ShortDescription  string            `json:"shortDescription"`
+	Host              LogRhythmNamedRef `json:"host"`
+	Entity            LogRhythmNamedRef `json:"entity"`
+	LogSourceType     LogRhythmNamedRef `json:"logSourceType"`
+	MPEPolicy         LogRhythmNamedRef `json:"mpePolicy"`
+	SystemMonitorID   int               `json:"systemMonitorId"`
+	SystemMonitorName string            `json:"systemMonitorName"`
+	RecordStatus      string            `json:"recordStatus"`
+	Status            string            `json:"status"`
+	IsVirtual         bool              `json:"isVirtual"`
+	MaxLogDate        string            `json:"maxLogDate"`
+	MaxMsgCount       int64             `json:"maxMsgCount"`
+	FilePath          string            `json:"filePath"`
+}
+
+// LogRhythmAgent represents a System Monitor agent from /lr-admin-api/agents
+type LogRhythmAgent struct {
+	ID            int               `json:"id"`
+	GUID          string            `json:"guid"`
+	Name          string            `json:"name"`
+	HostName      string            `json:"hostName"`
+	Host          LogRhythmNamedRef `json:"host"`
+	AgentType     string            `json:"agentType"`
+	Version       string            `json:"version"`
+	OS            string            `json:"os"`
+	OSVersion     string            `json:"osVersion"`
+	RecordStatus  string            `json:"recordStatus"`
+	Status        string            `json:"status"`
+	LastHeartbeat string            `json:"lastHeartbeat"`
+}
+
+// logRhythmTimeLayouts covers the timestamp formats the Admin API returns
+var logRhythmTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02T15:04:05"}
+
+const logRhythmDefaultPageSize = 1000
+
+// NewLogRhythmProvider creates a new LogRhythm provider. The endpoint is the
+// API gateway (https://host:8501); /lr-admin-api is appended when missing.
+func NewLogRhythmProvider(config types.ProviderConfig) (types.Provider, error) {
+	client, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := strings.TrimSuffix(config.Endpoint, "/")
+	if !strings.HasSuffix(baseURL, "/lr-admin-api") {
+		baseURL += "/lr-admin-api"
+	}
+
+	pageSize := logRhythmDefaultPageSize
+	if value := config.Options["page_size"]; value != "" {
+		pageSize, err = strconv.Atoi(value)
+		if err != nil || pageSize <= 0 {
+			return nil, fmt.Errorf("invalid page_size: %s", value)
+		}
+	}
+
+	return &LogRhythmProvider{
+		config:   config,
+		client:   client,
+		baseURL:  baseURL,
+		pageSize: pageSize,
+	}, nil
+}
+
+func (l *LogRhythmProvider) Name() string {
+	return "logrhythm"
+}
+
+func (l *LogRhythmProvider) FetchDataViews(ctx context.Context) ([]types.DataSource, error) {
+	var agents []LogRhythmAgent
+	err := l.listAll(ctx, "/agents", func(page json.RawMessage) (int, error) {
+		var items []LogRhythmAgent
+		if err := json.Unmarshal(page, &items); err != nil {
+			return 0, err
+		}
+		agents = append(agents, items...)
+		return len(items), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch agents: %w", err)
+	}
+
+	var logSources []LogRhythmLogSource
+	err = l.listAll(ctx, "/logsources", func(page json.RawMessage) (int, error) {
+		var items []LogRhythmLogSource
+		if err := json.Unmarshal(page, &items); err != nil {
+			return 0, err
+		}
+		logSources = append(logSources, items...)
+		return len(items), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch log sources: %w", err)
+	}
+
+	dataSources := make([]types.DataSource, 0, len(agents)+len(logSources))
+	for _, agent := range agents {
+		dataSources = append(dataSources, l.convertAgent(agent))
+	}
+	for _, logSource := range logSources {
+		dataSources = append(dataSources, l.convertLogSource(logSource))
+	}
+	return dataSources, nil
+}
+
+// listAll pages through an Admin API collection with offset/count until a
+// short page or the X-Total-Count header says there is nothing left
+func (l *LogRhythmProvider) listAll(ctx context.Context, path string, handle func(json.RawMessage) (int, error)) error {
+	for offset := 0; ; {
+		params := url.Values{}
+		params.Set("offset", strconv.Itoa(offset))
+		params.Set("count", strconv.Itoa(l.pageSize))
+		params.Set("recordStatus", "all")
+
+		var page json.RawMessage
+		total, err := l.getJSON(ctx, path+"?"+params.Encode(), &page)
+		if err != nil {
+			return err
+		}
+		n, err := handle(page)
+		if err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		offset += n
+		if n < l.pageSize || (total >= 0 && offset >= total) {
+			return nil
+		}
+	}
+}
+
+func (l *LogRhythmProvider) convertAgent(agent LogRhythmAgent) types.DataSource {
+	status := logRhythmStatus(agent.RecordStatus, agent.Status)
+
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(status == "active")
+
+	hostName := agent.HostName
+	if hostName == "" {
+		hostName = agent.Host.Name
+	}
+	metadata := map[string]interface{}{
+		"agentId":      agent.ID,
+		"guid":         agent.GUID,
+		"hostName":     hostName,
+		"agentType":    agent.AgentType,
+		"version":      agent.Version,
+		"recordStatus": agent.RecordStatus,
+	}
+	if agent.OS != "" {
+		metadata["os"] = strings.TrimSpace(agent.OS + " " + agent.OSVersion)
+	}
+	if agent.LastHeartbeat != "" {
+		metadata["lastHeartbeat"] = agent.LastHeartbeat
+	}
+
+	return types.DataSource{
+		ID:         "agent:" + strconv.Itoa(agent.ID),
+		Name:       agent.Name,
+		Title:      agent.Name,
+		Type:       "logrhythm-agent",
+		Status:     status,
+		Tags:       []string{"logrhythm", "agent"},
+		Normalized: n,
+		Metadata:   metadata,
+	}
+}
+
+func (l *LogRhythmProvider) convertLogSource(logSource LogRhythmLogSource) types.DataSource {
+	status := logRhythmStatus(logSource.RecordStatus, logSource.Status)
+
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(status == "active")
+	n.Product = logSource.LogSourceType.Name
+	if t, ok := parseTimeLayouts(logSource.MaxLogDate, logRhythmTimeLayouts...); ok {
+		n.LastEventTime = timePtr(t.UTC())
+	}
+
+	metadata := map[string]interface{}{
+		"logSourceId":       logSource.ID,
+		"logSourceType":     logSource.LogSourceType.Name,
+		"logSourceTypeId":   logSource.LogSourceType.ID,
+		"host":              logSource.Host.Name,
+		"entity":            logSource.Entity.Name,
+		"systemMonitorId":   logSource.SystemMonitorID,
+		"systemMonitorName": logSource.SystemMonitorName,
+		"recordStatus":      logSource.RecordStatus,
+		"isVirtual":         logSource.IsVirtual,
+	}
+	if logSource.MPEPolicy.Name != "" {
+		metadata["mpePolicy"] = logSource.MPEPolicy.Name
+	}
+	if logSource.FilePath != "" {
+		metadata["filePath"] = logSource.FilePath
+	}
+	if logSource.MaxLogDate != "" {
+		metadata["maxLogDate"] = logSource.MaxLogDate
+	}
+
+	ds := types.DataSource{
+		ID:          "logsource:" + strconv.Itoa(logSource.ID),
+		Name:        logSource.Name,
+		Title:       logSource.Name,
+		Type:        "logrhythm-log-source",
+		Description: logSource.ShortDescription,
+		Status:      status,
+		Tags:        []string{"logrhythm", "log-source"},
+		Normalized:  n,
+		Metadata:    metadata,
+	}
+	if logSource.SystemMonitorID > 0 {
+		ds.Relations = []types.Relation{{Type: types.RelationRoutesTo, TargetID: "agent:" + strconv.Itoa(logSource.SystemMonitorID)}}
+	}
+	return ds
+}
+
+// logRhythmStatus maps a record status (Active, Retired) and an operational
+// status (Enabled, Disabled, ...) to a DataSource status
+func logRhythmStatus(recordStatus, status string) string {
+	if strings.EqualFold(recordStatus, "retired") {
+		return "disabled"
+	}
+	switch strings.ToLower(status) {
+	case "", "enabled", "active":
+		return "active"
+	case "disabled", "inactive", "unlicensed":
+		return "disabled"
+	}
+	return strings.ToLower(status)
+}
+
+// getJSON performs a GET with a bearer token and returns the X-Total-Count
+// header, or -1 when it is absent
+func (l *LogRhythmProvider) getJSON(ctx context.Context, path string, out interface{}) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", l.baseURL+path, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if auth := l.config.Auth; auth != nil {
+		token := auth.Token
+		if token == "" {
+			token = auth.APIKey
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	total := -1
+	if value, err := strconv.Atoi(resp.Header.Get("X-Total-Count")); err == nil {
+		total = value
+	}
+	return total, readJSONResponse(resp, "logrhythm", out)
+}
+
+func (l *LogRhythmProvider) ValidateConnection(ctx context.Context) error {
+	var page json.RawMessage
+	if _, err := l.getJSON(ctx, "/agents?count=1", &page); err != nil {
+		return fmt.Errorf("failed to connect to LogRhythm: %w", err)
+	}
+	return nil
+}
+
+func (l *LogRhythmProvider) GetCapabilities() types.ProviderCapabilities {
+	return types.ProviderCapabilities{
+		SupportsRealTimeQueries: false,
+		SupportsHistoricalData:  true,
+		SupportedDataTypes:      []string{"logrhythm-log-source", "logrhythm-agent"},
+		RequiresAuthentication:  true,
+	}
+}
@@ -0,0 +1,92 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+func TestLogRhythmFetchDataViews(t *testing.T) {
+	logSourcePages := []string{
+		`[{"id":1,"name":"DC01 Security","host":{"id":10,"name":"DC01"},"logSourceType":{"id":1000030,"name":"MS Windows Event Logging - Security"},"systemMonitorId":5,"systemMonitorName":"DC01","recordStatus":"Active","status":"Enabled","maxLogDate":"2024-05-01T10:00:00"},
+		  {"id":2,"name":"Old firewall","host":{"id":11,"name":"fw-old"},"logSourceType":{"id":1000050,"name":"Syslog - Cisco ASA"},"systemMonitorId":6,"recordStatus":"Retired","status":"Disabled"}]`,
+		`[{"id":3,"name":"Linux auth","host":{"id":12,"name":"web01"},"logSourceType":{"id":1000100,"name":"Syslog - Linux Host"},"systemMonitorId":6,"recordStatus":"Active","status":"Enabled","maxLogDate":"2024-05-01T09:00:00.5Z"}]`,
+	}
+	var offsets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer lr-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/lr-admin-api/agents":
+			if r.URL.Query().Get("offset") != "0" {
+				w.Write([]byte(`[]`))
+				return
+			}
+			w.Write([]byte(`[{"id":5,"name":"DC01","hostName":"DC01","agentType":"Windows","version":"7.13","recordStatus":"Active","status":"Enabled"},
+			                 {"id":6,"name":"collector01","host":{"id":13,"name":"collector01"},"agentType":"Linux","recordStatus":"Active","status":"Disabled"}]`))
+		case "/lr-admin-api/logsources":
+			offset := r.URL.Query().Get("offset")
+			offsets = append(offsets, offset)
+			if r.URL.Query().Get("count") != "2" {
+				t.Errorf("unexpected count %s", r.URL.Query().Get("count"))
+			}
+			w.Header().Set("X-Total-Count", "3")
+			page, _ := strconv.Atoi(offset)
+			w.Write([]byte(logSourcePages[page/2]))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewLogRhythmProvider(types.ProviderConfig{
+		Type:     "logrhythm",
+		Endpoint: server.URL,
+		Options:  map[string]string{"page_size": "2"},
+		Auth:     &types.AuthConfig{Type: "bearer", Token: "lr-token"},
+		Timeout:  5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, err := provider.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+
+	if len(offsets) != 2 || offsets[0] != "0" || offsets[1] != "2" {
+		t.Errorf("unexpected paging offsets %v", offsets)
+	}
+
+	byID := make(map[string]types.DataSource)
+	for _, ds := range sources {
+		byID[ds.ID] = ds
+	}
+	if len(byID) != 5 {
+		t.Fatalf("expected 5 data sources, got %d", len(byID))
+	}
+
+	if ds := byID["agent:6"]; ds.Status != "disabled" || ds.Metadata["hostName"] != "collector01" {
+		t.Errorf("unexpected agent %+v", ds)
+	}
+	dc := byID["logsource:1"]
+	if dc.Status != "active" || dc.Normalized.Product != "MS Windows Event Logging - Security" || dc.Metadata["host"] != "DC01" {
+		t.Errorf("unexpected log source %+v", dc)
+	}
+	if dc.Normalized.LastEventTime == nil || dc.Normalized.LastEventTime.Hour() != 10 {
+		t.Errorf("expected last log message time, got %v", dc.Normalized.LastEventTime)
+	}
+	if len(dc.Relations) != 1 || dc.Relations[0].TargetID != "agent:5" {
+		t.Errorf("log source should route to its System Monitor agent, got %+v", dc.Relations)
+	}
+	if ds := byID["logsource:2"]; ds.Status != "disabled" {
+		t.Errorf("retired log source should be disabled, got %s", ds.Status)
+	}
+}
@@ -0,0 +1,287 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/logfiend/internal/types"
+)
+
+// LogScaleProvider implements the Provider interface for Falcon LogScale
+// (formerly Humio). It inventories repositories, views, parsers and ingest
+// tokens through the LogScale GraphQL API. Ingest token secrets are never
+// requested.
+type LogScaleProvider struct {
+	config  types.ProviderConfig
+	graphql *graphQLClient
+}
+
+// LogScaleSearchDomainsResponse represents the searchDomains query result
+type LogScaleSearchDomainsResponse struct {
+	SearchDomains []LogScaleSearchDomain `json:"searchDomains"`
+}
+
+// LogScaleSearchDomain is a repository or a view
+type LogScaleSearchDomain struct {
+	Typename    string `json:"__typename"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+
+	// Repository fields
+	TimeBasedRetention        *float64 `json:"timeBasedRetention"`
+	IngestSizeBasedRetention  *float64 `json:"ingestSizeBasedRetention"`
+	StorageSizeBasedRetention *float64 `json:"storageSizeBasedRetention"`
+	CompressedByteSize        *int64   `json:"compressedByteSize"`
+	UncompressedByteSize      *int64   `json:"uncompressedByteSize"`
+	Parsers                   []struct {
+		ID        string `json:"id"`
+		Name      string `json:"name"`
+		IsBuiltIn bool   `json:"isBuiltIn"`
+	} `json:"parsers"`
+	IngestTokens []struct {
+		Name   string `json:"name"`
+		Parser *struct {
+			Name string `json:"name"`
+		} `json:"parser"`
+	} `json:"ingestTokens"`
+
+	// View fields
+	Connections []struct {
+		Repository struct {
+			Name string `json:"name"`
+		} `json:"repository"`
+		Filter string `json:"filter"`
+	} `json:"connections"`
+}
+
+// logScaleSearchDomainsQuery lists repositories and views. The ingest token
+// value is deliberately not selected so it can never reach the inventory.
+const logScaleSearchDomainsQuery = `query {
+  searchDomains {
+    __typename
+    name
+    description
+    ... on Repository {
+      timeBasedRetention
+      ingestSizeBasedRetention
+      storageSizeBasedRetention
+      compressedByteSize
+      uncompressedByteSize
+      parsers { id name isBuiltIn }
+      ingestTokens { name parser { name } }
+    }
+    ... on View {
+      connections { repository { name } filter }
+    }
+  }
+}`
+
+// logScaleRedacted replaces ingest token values in the inventory
+const logScaleRedacted = "[REDACTED]"
+
+// NewLogScaleProvider creates a new LogScale provider
+func NewLogScaleProvider(config types.ProviderConfig) (types.Provider, error) {
+	client, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &LogScaleProvider{config: config}
+	provider.graphql = &graphQLClient{
+		client:    client,
+		endpoint:  strings.TrimSuffix(config.Endpoint, "/") + "/graphql",
+		vendor:    "logscale",
+		authorize: provider.addAuth,
+	}
+	return provider, nil
+}
+
+func (l *LogScaleProvider) Name() string {
+	return "logscale"
+}
+
+func (l *LogScaleProvider) FetchDataViews(ctx context.Context) ([]types.DataSource, error) {
+	var resp LogScaleSearchDomainsResponse
+	if err := l.graphql.query(ctx, logScaleSearchDomainsQuery, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to fetch search domains: %w", err)
+	}
+
+	includeBuiltIn := l.config.Options["include_builtin_parsers"] == "true"
+	dataSources := []types.DataSource{}
+	for _, domain := range resp.SearchDomains {
+		switch domain.Typename {
+		case "Repository":
+			dataSources = append(dataSources, l.convertRepository(domain))
+			dataSources = append(dataSources, l.convertParsers(domain, includeBuiltIn)...)
+			dataSources = append(dataSources, l.convertIngestTokens(domain)...)
+		case "View":
+			dataSources = append(dataSources, l.convertView(domain))
+		}
+	}
+	return dataSources, nil
+}
+
+func (l *LogScaleProvider) convertRepository(repo LogScaleSearchDomain) types.DataSource {
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(true)
+	// size_bytes is stored size; the uncompressed ingest volume stays in metadata
+	if repo.CompressedByteSize != nil {
+		n.SizeBytes = int64Ptr(*repo.CompressedByteSize)
+	}
+	if repo.TimeBasedRetention != nil && *repo.TimeBasedRetention > 0 {
+		n.RetentionDays = intPtr(int(*repo.TimeBasedRetention))
+	}
+
+	metadata := map[string]interface{}{}
+	if repo.TimeBasedRetention != nil {
+		metadata["timeBasedRetentionDays"] = *repo.TimeBasedRetention
+	}
+	if repo.IngestSizeBasedRetention != nil {
+		metadata["ingestSizeBasedRetentionGB"] = *repo.IngestSizeBasedRetention
+	}
+	if repo.StorageSizeBasedRetention != nil {
+		metadata["storageSizeBasedRetentionGB"] = *repo.StorageSizeBasedRetention
+	}
+	if repo.UncompressedByteSize != nil {
+		metadata["uncompressedByteSize"] = *repo.UncompressedByteSize
+	}
+	if repo.CompressedByteSize != nil {
+		metadata["compressedByteSize"] = *repo.CompressedByteSize
+	}
+
+	return types.DataSource{
+		ID:          "repository:" + repo.Name,
+		Name:        repo.Name,
+		Title:       repo.Name,
+		Type:        "logscale-repository",
+		Description: repo.Description,
+		Status:      "active",
+		Tags:        []string{"logscale", "repository"},
+		Normalized:  n,
+		Metadata:    metadata,
+	}
+}
+
+func (l *LogScaleProvider) convertView(view LogScaleSearchDomain) types.DataSource {
+	repositories := make([]string, 0, len(view.Connections))
+	filters := make(map[string]string, len(view.Connections))
+	var relations []types.Relation
+	for _, connection := range view.Connections {
+		name := connection.Repository.Name
+		repositories = append(repositories, name)
+		if connection.Filter != "" {
+			filters[name] = connection.Filter
+		}
+		relations = append(relations, types.Relation{Type: types.RelationStoredIn, TargetID: "repository:" + name})
+	}
+
+	return types.DataSource{
+		ID:          "view:" + view.Name,
+		Name:        view.Name,
+		Title:       view.Name,
+		Type:        "logscale-view",
+		Pattern:     strings.Join(repositories, ","),
+		Description: view.Description,
+		Status:      "active",
+		Tags:        []string{"logscale", "view"},
+		Normalized:  types.NewNormalizedFields(),
+		Relations:   relations,
+		Metadata: map[string]interface{}{
+			"repositories": repositories,
+			"filters":      filters,
+		},
+	}
+}
+
+// convertParsers lists a repository's parsers. Built-in parsers exist in
+// every repository and are skipped unless options.include_builtin_parsers is set.
+func (l *LogScaleProvider) convertParsers(repo LogScaleSearchDomain, includeBuiltIn bool) []types.DataSource {
+	dataSources := []types.DataSource{}
+	for _, parser := range repo.Parsers {
+		if parser.IsBuiltIn && !includeBuiltIn {
+			continue
+		}
+		dataSources = append(dataSources, types.DataSource{
+			ID:         "parser:" + repo.Name + "/" + parser.Name,
+			Name:       parser.Name,
+			Title:      parser.Name,
+			Type:       "logscale-parser",
+			Status:     "active",
+			Tags:       []string{"logscale", "parser"},
+			Normalized: types.NewNormalizedFields(),
+			Metadata: map[string]interface{}{
+				"repository": repo.Name,
+				"parserId":   parser.ID,
+				"builtIn":    parser.IsBuiltIn,
+			},
+		})
+	}
+	return dataSources
+}
+
+// convertIngestTokens lists ingest tokens with their parser assignment; the
+// token value itself is always redacted
+func (l *LogScaleProvider) convertIngestTokens(repo LogScaleSearchDomain) []types.DataSource {
+	dataSources := []types.DataSource{}
+	for _, token := range repo.IngestTokens {
+		relations := []types.Relation{{Type: types.RelationStoredIn, TargetID: "repository:" + repo.Name}}
+		metadata := map[string]interface{}{
+			"repository": repo.Name,
+			"token":      logScaleRedacted,
+		}
+		if token.Parser != nil && token.Parser.Name != "" {
+			metadata["parser"] = token.Parser.Name
+			relations = append(relations, types.Relation{Type: types.RelationProcessedBy, TargetID: "parser:" + repo.Name + "/" + token.Parser.Name})
+		}
+
+		dataSources = append(dataSources, types.DataSource{
+			ID:         "ingest-token:" + repo.Name + "/" + token.Name,
+			Name:       token.Name,
+			Title:      token.Name,
+			Type:       "logscale-ingest-token",
+			Status:     "active",
+			Tags:       []string{"logscale", "ingest-token"},
+			Normalized: types.NewNormalizedFields(),
+			Relations:  relations,
+			Metadata:   metadata,
+		})
+	}
+	return dataSources
+}
+
+func (l *LogScaleProvider) addAuth(_ context.Context, req *http.Request) error {
+	auth := l.config.Auth
+	if auth == nil {
+		return nil
+	}
+	switch auth.Type {
+	case "bearer":
+		req.Header.Set("Authorization", "Bearer "+auth.Token)
+	case "api_key":
+		req.Header.Set("Authorization", "Bearer "+auth.APIKey)
+	}
+	return nil
+}
+
+func (l *LogScaleProvider) ValidateConnection(ctx context.Context) error {
+	var resp struct {
+		Viewer struct {
+			Username string `json:"username"`
+		} `json:"viewer"`
+	}
+	if err := l.graphql.query(ctx, `query { viewer { username } }`, nil, &resp); err != nil {
+		return fmt.Errorf("failed to connect to LogScale: %w", err)
+	}
+	return nil
+}
+
+func (l *LogScaleProvider) GetCapabilities() types.ProviderCapabilities {
+	return types.ProviderCapabilities{
+		SupportsRealTimeQueries: true,
+		SupportsHistoricalData:  true,
+		SupportedDataTypes:      []string{"logscale-repository", "logscale-view", "logscale-parser", "logscale-ingest-token"},
+		RequiresAuthentication:  true,
+	}
+}
@@ -0,0 +1,90 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+func TestLogScaleFetchDataViews(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" || r.Method != "POST" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer ls-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var req struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if strings.Contains(req.Query, "token ") || strings.Contains(req.Query, "token}") {
+			t.Errorf("query must not select ingest token values: %s", req.Query)
+		}
+		w.Write([]byte(`{"data":{"searchDomains":[
+			{"__typename":"Repository","name":"security","timeBasedRetention":90,"ingestSizeBasedRetention":500,
+			 "compressedByteSize":1000,"uncompressedByteSize":8000,
+			 "parsers":[{"id":"p1","name":"okta","isBuiltIn":false},{"id":"p2","name":"kv","isBuiltIn":true}],
+			 "ingestTokens":[{"name":"okta-ingest","parser":{"name":"okta"}}]},
+			{"__typename":"View","name":"soc","connections":[{"repository":{"name":"security"},"filter":"#type=okta"}]}]}}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewLogScaleProvider(types.ProviderConfig{
+		Type:     "logscale",
+		Endpoint: server.URL,
+		Auth:     &types.AuthConfig{Type: "bearer", Token: "ls-token"},
+		Timeout:  5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, err := provider.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+
+	byID := make(map[string]types.DataSource)
+	for _, ds := range sources {
+		byID[ds.ID] = ds
+	}
+	if len(byID) != 4 {
+		t.Fatalf("expected 4 data sources (built-in parser skipped), got %d", len(byID))
+	}
+
+	repo := byID["repository:security"]
+	if repo.Normalized.RetentionDays == nil || *repo.Normalized.RetentionDays != 90 || *repo.Normalized.SizeBytes != 1000 {
+		t.Errorf("unexpected repository %+v", repo.Normalized)
+	}
+	token := byID["ingest-token:security/okta-ingest"]
+	if token.Metadata["token"] != logScaleRedacted || token.Metadata["parser"] != "okta" {
+		t.Errorf("unexpected ingest token metadata %v", token.Metadata)
+	}
+	if len(token.Relations) != 2 || token.Relations[1].TargetID != "parser:security/okta" {
+		t.Errorf("unexpected ingest token relations %+v", token.Relations)
+	}
+	if view := byID["view:soc"]; len(view.Relations) != 1 || view.Relations[0].TargetID != "repository:security" {
+		t.Errorf("unexpected view relations %+v", view.Relations)
+	}
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data":null,"errors":[{"message":"Not authorized"},{"message":"Unknown field"}]}`))
+	}))
+	defer server.Close()
+
+	client := &graphQLClient{client: server.Client(), endpoint: server.URL, vendor: "test"}
+	err := client.query(context.Background(), "query { x }", nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "Not authorized; Unknown field") {
+		t.Fatalf("expected GraphQL errors to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,335 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+// LokiProvider implements the Provider interface for Grafana Loki. Loki has
+// no notion of indexes, so streams seen within a lookback window are grouped
+// into data sources by configurable label keys.
+type LokiProvider struct {
+	config   types.ProviderConfig
+	client   *http.Client
+	groupBy  []string
+	lookback time.Duration
+	now      func() time.Time
+}
+
+// LokiStringsResponse represents the labels and label values responses
+type LokiStringsResponse struct {
+	Status string   `json:"status"`
+	Data   []string `json:"data"`
+}
+
+// LokiSeriesResponse represents the series response
+type LokiSeriesResponse struct {
+	Status string              `json:"status"`
+	Data   []map[string]string `json:"data"`
+}
+
+// LokiVolumeResponse represents the index/volume response, a Prometheus-style vector
+type LokiVolumeResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Value  []interface{}     `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// lokiGroup accumulates the streams sharing the same group-by label values
+type lokiGroup struct {
+	labels      map[string]string
+	streamCount int
+	labelNames  map[string]bool
+}
+
+const lokiDefaultLookback = 24 * time.Hour
+
+// NewLokiProvider creates a new Loki provider. options.group_by lists the
+// label keys to group streams by (default "job"); the first key is required
+// on a stream for it to be inventoried.
+func NewLokiProvider(config types.ProviderConfig) (types.Provider, error) {
+	client, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &LokiProvider{
+		config:   config,
+		client:   client,
+		groupBy:  []string{"job"},
+		lookback: lokiDefaultLookback,
+		now:      time.Now,
+	}
+
+	if groupBy := config.Options["group_by"]; groupBy != "" {
+		provider.groupBy = nil
+		for _, key := range strings.Split(groupBy, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				provider.groupBy = append(provider.groupBy, key)
+			}
+		}
+		if len(provider.groupBy) == 0 {
+			return nil, fmt.Errorf("group_by must name at least one label")
+		}
+	}
+	if lookback := config.Options["lookback"]; lookback != "" {
+		provider.lookback, err = time.ParseDuration(lookback)
+		if err != nil || provider.lookback <= 0 {
+			return nil, fmt.Errorf("invalid lookback: %s", lookback)
+		}
+	}
+
+	return provider, nil
+}
+
+func (l *LokiProvider) Name() string {
+	return "loki"
+}
+
+func (l *LokiProvider) FetchDataViews(ctx context.Context) ([]types.DataSource, error) {
+	end := l.now()
+	start := end.Add(-l.lookback)
+
+	var labels LokiStringsResponse
+	if err := l.getJSON(ctx, "/loki/api/v1/labels", l.timeRange(start, end), &labels); err != nil {
+		return nil, fmt.Errorf("failed to fetch labels: %w", err)
+	}
+	present := make(map[string]bool, len(labels.Data))
+	for _, label := range labels.Data {
+		present[label] = true
+	}
+
+	primary := l.groupBy[0]
+	if !present[primary] {
+		// No stream carries the primary label within the window
+		return []types.DataSource{}, nil
+	}
+
+	var values LokiStringsResponse
+	if err := l.getJSON(ctx, "/loki/api/v1/label/"+url.PathEscape(primary)+"/values", l.timeRange(start, end), &values); err != nil {
+		return nil, fmt.Errorf("failed to fetch values for label %s: %w", primary, err)
+	}
+
+	groups := make(map[string]*lokiGroup)
+	for _, value := range values.Data {
+		params := l.timeRange(start, end)
+		params.Set("match[]", lokiSelector(map[string]string{primary: value}))
+
+		var series LokiSeriesResponse
+		if err := l.getJSON(ctx, "/loki/api/v1/series", params, &series); err != nil {
+			return nil, fmt.Errorf("failed to fetch series for %s=%q: %w", primary, value, err)
+		}
+		for _, stream := range series.Data {
+			l.addStream(groups, stream)
+		}
+	}
+
+	volumes, err := l.fetchVolumes(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch volume: %w", err)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	dataSources := make([]types.DataSource, 0, len(keys))
+	for _, key := range keys {
+		volume, hasVolume := volumes[key]
+		dataSources = append(dataSources, l.convertGroup(groups[key], volume, hasVolume))
+	}
+	return dataSources, nil
+}
+
+func (l *LokiProvider) addStream(groups map[string]*lokiGroup, stream map[string]string) {
+	groupLabels := make(map[string]string, len(l.groupBy))
+	for _, key := range l.groupBy {
+		if value, ok := stream[key]; ok {
+			groupLabels[key] = value
+		}
+	}
+	key := lokiSelector(groupLabels)
+
+	group, ok := groups[key]
+	if !ok {
+		group = &lokiGroup{labels: groupLabels, labelNames: make(map[string]bool)}
+		groups[key] = group
+	}
+	group.streamCount++
+	for name := range stream {
+		group.labelNames[name] = true
+	}
+}
+
+// fetchVolumes returns ingested bytes per group over the window. The default
+// series aggregation with targetLabels sums per combination of the group_by
+// values (aggregateBy=labels would sum per label name instead). The volume
+// endpoint needs Loki 2.9+ with volume_enabled, so it is best effort.
+func (l *LokiProvider) fetchVolumes(ctx context.Context, start, end time.Time) (map[string]int64, error) {
+	params := l.timeRange(start, end)
+	params.Set("query", fmt.Sprintf(`{%s=~".+"}`, l.groupBy[0]))
+	params.Set("targetLabels", strings.Join(l.groupBy, ","))
+	params.Set("limit", "10000")
+
+	var resp LokiVolumeResponse
+	err := l.getJSON(ctx, "/loki/api/v1/index/volume", params, &resp)
+	if isStatus(err, http.StatusNotFound) || isStatus(err, http.StatusBadRequest) {
+		return map[string]int64{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	volumes := make(map[string]int64, len(resp.Data.Result))
+	for _, result := range resp.Data.Result {
+		if len(result.Value) != 2 {
+			continue
+		}
+		text, _ := result.Value[1].(string)
+		bytes, ok := parseInt64(text)
+		if !ok {
+			continue
+		}
+		groupLabels := make(map[string]string, len(l.groupBy))
+		for _, key := range l.groupBy {
+			if value, ok := result.Metric[key]; ok {
+				groupLabels[key] = value
+			}
+		}
+		volumes[lokiSelector(groupLabels)] += bytes
+	}
+	return volumes, nil
+}
+
+func (l *LokiProvider) convertGroup(group *lokiGroup, volume int64, hasVolume bool) types.DataSource {
+	selector := lokiSelector(group.labels)
+	labelNames := make([]string, 0, len(group.labelNames))
+	for name := range group.labelNames {
+		labelNames = append(labelNames, name)
+	}
+	sort.Strings(labelNames)
+
+	metadata := map[string]interface{}{
+		"labels":      group.labels,
+		"streamCount": group.streamCount,
+		"labelNames":  labelNames,
+		"lookback":    l.lookback.String(),
+	}
+	if hasVolume {
+		metadata["volumeBytes"] = volume
+	}
+	if tenant := l.config.Options["tenant"]; tenant != "" {
+		metadata["tenant"] = tenant
+	}
+
+	tags := []string{"loki"}
+	for _, key := range l.groupBy {
+		if value, ok := group.labels[key]; ok {
+			tags = append(tags, key+":"+value)
+		}
+	}
+
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(true)
+
+	return types.DataSource{
+		ID:         "streams:" + selector,
+		Name:       group.labels[l.groupBy[0]],
+		Title:      selector,
+		Type:       "loki-stream-group",
+		Pattern:    selector,
+		Status:     "active",
+		Tags:       tags,
+		Normalized: n,
+		Metadata:   metadata,
+	}
+}
+
+// lokiSelector renders labels as a LogQL stream selector with sorted keys
+func lokiSelector(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	matchers := make([]string, 0, len(keys))
+	for _, key := range keys {
+		matchers = append(matchers, key+"="+strconv.Quote(labels[key]))
+	}
+	return "{" + strings.Join(matchers, ", ") + "}"
+}
+
+func (l *LokiProvider) timeRange(start, end time.Time) url.Values {
+	params := url.Values{}
+	params.Set("start", strconv.FormatInt(start.UnixNano(), 10))
+	params.Set("end", strconv.FormatInt(end.UnixNano(), 10))
+	return params
+}
+
+// getJSON performs a GET against the Loki HTTP API, scoped to the tenant in
+// options.tenant when Loki runs multi-tenant
+func (l *LokiProvider) getJSON(ctx context.Context, path string, params url.Values, out interface{}) error {
+	endpoint := strings.TrimSuffix(l.config.Endpoint, "/") + path
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if tenant := l.config.Options["tenant"]; tenant != "" {
+		req.Header.Set("X-Scope-OrgID", tenant)
+	}
+	if auth := l.config.Auth; auth != nil {
+		switch auth.Type {
+		case "basic":
+			req.SetBasicAuth(auth.Username, auth.Password)
+		case "bearer":
+			req.Header.Set("Authorization", "Bearer "+auth.Token)
+		}
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return readJSONResponse(resp, "loki", out)
+}
+
+func (l *LokiProvider) ValidateConnection(ctx context.Context) error {
+	end := l.now()
+	var labels LokiStringsResponse
+	if err := l.getJSON(ctx, "/loki/api/v1/labels", l.timeRange(end.Add(-time.Hour), end), &labels); err != nil {
+		return fmt.Errorf("failed to connect to Loki: %w", err)
+	}
+	return nil
+}
+
+func (l *LokiProvider) GetCapabilities() types.ProviderCapabilities {
+	return types.ProviderCapabilities{
+		SupportsRealTimeQueries: true,
+		SupportsHistoricalData:  true,
+		SupportedDataTypes:      []string{"loki-stream-group"},
+		RequiresAuthentication:  l.config.Auth != nil,
+	}
+}
@@ -0,0 +1,93 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+func TestLokiFetchDataViews(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Scope-OrgID") != "platform" {
+			http.Error(w, "no org id", http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("start") == "" || r.URL.Query().Get("end") == "" {
+			http.Error(w, "missing time range", http.StatusBadRequest)
+			return
+		}
+		switch r.URL.Path {
+		case "/loki/api/v1/labels":
+			w.Write([]byte(`{"status":"success","data":["job","namespace","pod"]}`))
+		case "/loki/api/v1/label/job/values":
+			w.Write([]byte(`{"status":"success","data":["kube-audit","ingress"]}`))
+		case "/loki/api/v1/series":
+			switch r.URL.Query().Get("match[]") {
+			case `{job="kube-audit"}`:
+				w.Write([]byte(`{"status":"success","data":[
+					{"job":"kube-audit","namespace":"kube-system","pod":"apiserver-1"},
+					{"job":"kube-audit","namespace":"kube-system","pod":"apiserver-2"}]}`))
+			case `{job="ingress"}`:
+				w.Write([]byte(`{"status":"success","data":[
+					{"job":"ingress","namespace":"web","pod":"nginx-1"},
+					{"job":"ingress","namespace":"edge","pod":"nginx-2"}]}`))
+			default:
+				t.Errorf("unexpected series selector %q", r.URL.Query().Get("match[]"))
+			}
+		case "/loki/api/v1/index/volume":
+			if r.URL.Query().Get("targetLabels") != "job,namespace" {
+				t.Errorf("unexpected targetLabels %q", r.URL.Query().Get("targetLabels"))
+			}
+			// As Loki answers: aggregateBy=labels sums per label name, the
+			// default per combination of the target labels' values
+			if r.URL.Query().Get("aggregateBy") == "labels" {
+				w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+					{"metric":{"job":""},"value":[1700000000,"4096"]},
+					{"metric":{"namespace":""},"value":[1700000000,"4096"]}]}}`))
+				return
+			}
+			w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+				{"metric":{"job":"kube-audit","namespace":"kube-system"},"value":[1700000000,"4096"]}]}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewLokiProvider(types.ProviderConfig{
+		Type:     "loki",
+		Endpoint: server.URL,
+		Options:  map[string]string{"group_by": "job, namespace", "tenant": "platform", "lookback": "6h"},
+		Timeout:  5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, err := provider.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+
+	byID := make(map[string]types.DataSource)
+	for _, ds := range sources {
+		byID[ds.ID] = ds
+	}
+	if len(byID) != 3 {
+		t.Fatalf("expected 3 stream groups, got %d", len(byID))
+	}
+
+	audit, ok := byID[`streams:{job="kube-audit", namespace="kube-system"}`]
+	if !ok {
+		t.Fatalf("missing kube-audit group in %v", byID)
+	}
+	if audit.Name != "kube-audit" || audit.Metadata["streamCount"] != 2 || audit.Metadata["volumeBytes"] != int64(4096) {
+		t.Errorf("unexpected kube-audit group %+v", audit)
+	}
+	if _, ok := byID[`streams:{job="ingress", namespace="web"}`].Metadata["volumeBytes"]; ok {
+		t.Error("groups without volume data should not report volumeBytes")
+	}
+}
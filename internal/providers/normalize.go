@@ -0,0 +1,85 @@
+package providers
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Helpers for filling types.NormalizedFields from the loosely typed values
+// SIEM APIs return. Parsers report ok=false instead of guessing so converters
+// can leave the corresponding normalized field unset.
+
+const bytesPerMB = 1024 * 1024
+
+func boolPtr(v bool) *bool {
+	return &v
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+// parseInt64 parses integers that some APIs return as strings, including
+// float notation such as "1.2e3"
+func parseInt64(s string) (int64, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false
+	}
+	if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return v, true
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+// parseFloat64 parses a float that an API returned as a string
+func parseFloat64(s string) (float64, bool) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false
+	}
+	return f, true
+}
+
+// megabytesToBytes converts a size in MB (base 1024) to bytes
+func megabytesToBytes(mb float64) int64 {
+	return int64(mb * bytesPerMB)
+}
+
+// secondsToDays converts a retention period in seconds to whole days
+func secondsToDays(seconds int64) int {
+	return int(seconds / int64(24*time.Hour/time.Second))
+}
+
+// unixMillis converts epoch milliseconds to a UTC time
+func unixMillis(ms int64) time.Time {
+	return time.UnixMilli(ms).UTC()
+}
+
+// parseTimeLayouts tries each layout in turn and returns the first match
+func parseTimeLayouts(value string, layouts ...string) (time.Time, bool) {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,77 @@
+package providers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+func TestSplunkNormalize(t *testing.T) {
+	s := &SplunkProvider{}
+	n := s.normalize(false, "1500", "2.5", "7776000", "1700000000", "2024-01-15T10:30:00+00:00")
+
+	if n.Version != types.NormalizedFieldsVersion {
+		t.Fatalf("expected version %d, got %d", types.NormalizedFieldsVersion, n.Version)
+	}
+	if n.Enabled == nil || !*n.Enabled {
+		t.Fatalf("expected enabled=true, got %v", n.Enabled)
+	}
+	if n.EventCount == nil || *n.EventCount != 1500 {
+		t.Fatalf("expected event_count 1500, got %v", n.EventCount)
+	}
+	if n.SizeBytes == nil || *n.SizeBytes != 2621440 {
+		t.Fatalf("expected size_bytes 2621440, got %v", n.SizeBytes)
+	}
+	if n.RetentionDays == nil || *n.RetentionDays != 90 {
+		t.Fatalf("expected retention_days 90, got %v", n.RetentionDays)
+	}
+	if n.FirstEventTime == nil || !n.FirstEventTime.Equal(time.Unix(1700000000, 0)) {
+		t.Fatalf("unexpected first_event_time %v", n.FirstEventTime)
+	}
+	want := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	if n.LastEventTime == nil || !n.LastEventTime.Equal(want) {
+		t.Fatalf("unexpected last_event_time %v", n.LastEventTime)
+	}
+}
+
+func TestSplunkNormalizeUnknownValues(t *testing.T) {
+	s := &SplunkProvider{}
+	n := s.normalize(true, "", "n/a", "0", "0", "")
+
+	if n.Enabled == nil || *n.Enabled {
+		t.Fatalf("expected enabled=false, got %v", n.Enabled)
+	}
+	if n.EventCount != nil || n.SizeBytes != nil || n.RetentionDays != nil {
+		t.Fatalf("expected unknown values to stay nil, got %+v", n)
+	}
+	if n.FirstEventTime != nil || n.LastEventTime != nil {
+		t.Fatalf("expected unknown times to stay nil, got %+v", n)
+	}
+}
+
+func TestQRadarNormalize(t *testing.T) {
+	q := &QRadarProvider{}
+	ds := q.convertToDataSource(QRadarLogSource{
+		ID:            42,
+		Name:          "dc01",
+		TypeID:        12,
+		Enabled:       true,
+		AverageEPS:    35,
+		LastEventTime: 1700000000000,
+	}, map[int]string{12: "Microsoft Windows Security Event Log"})
+
+	n := ds.Normalized
+	if n == nil {
+		t.Fatal("expected normalized fields")
+	}
+	if n.Product != "Microsoft Windows Security Event Log" {
+		t.Fatalf("unexpected product %q", n.Product)
+	}
+	if n.IngestRateEPS == nil || *n.IngestRateEPS != 35 {
+		t.Fatalf("expected ingest_rate_eps 35, got %v", n.IngestRateEPS)
+	}
+	if n.LastEventTime == nil || !n.LastEventTime.Equal(time.Unix(1700000000, 0)) {
+		t.Fatalf("unexpected last_event_time %v", n.LastEventTime)
+	}
+}
@@ -0,0 +1,65 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// oauthTokenResponse is the RFC 6749 access token response
+type oauthTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// cachedToken holds an access token until shortly before it expires
+type cachedToken struct {
+	value  string
+	expiry time.Time
+}
+
+// tokenExpiryMargin renews tokens early so requests never race the expiry
+const tokenExpiryMargin = time.Minute
+
+func (c *cachedToken) valid(now time.Time) bool {
+	return c.value != "" && now.Before(c.expiry.Add(-tokenExpiryMargin))
+}
+
+func (c *cachedToken) set(resp oauthTokenResponse, now time.Time) {
+	c.value = resp.AccessToken
+	c.expiry = now.Add(time.Duration(resp.ExpiresIn) * time.Second)
+	if resp.ExpiresIn <= 0 {
+		c.expiry = now.Add(time.Hour)
+	}
+}
+
+// requestToken posts a form-encoded grant to an OAuth token endpoint. vendor
+// names the identity provider in error messages.
+func requestToken(ctx context.Context, client *http.Client, tokenURL string, form url.Values, vendor string) (oauthTokenResponse, error) {
+	var token oauthTokenResponse
+
+	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return token, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return token, fmt.Errorf("failed to request token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := readJSONResponse(resp, vendor, &token); err != nil {
+		return token, fmt.Errorf("token request failed: %w", err)
+	}
+	if token.AccessToken == "" {
+		return token, fmt.Errorf("token response from %s contained no access_token", vendor)
+	}
+	return token, nil
+}
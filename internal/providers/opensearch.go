@@ -0,0 +1,672 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+// OpenSearchProvider implements the Provider interface for OpenSearch and
+// Amazon OpenSearch Service. It inventories Dashboards index patterns per
+// tenant, indices, data streams and ISM policies.
+type OpenSearchProvider struct {
+	config types.ProviderConfig
+	client *http.Client
+
+	// awsService is set for aws_sigv4 auth; credentials are resolved on first use
+	awsService string
+	mu         sync.Mutex
+	aws        *awsCredentials
+}
+
+// OpenSearchSavedObjectsResponse represents the Dashboards saved objects _find API response
+type OpenSearchSavedObjectsResponse struct {
+	Page         int `json:"page"`
+	PerPage      int `json:"per_page"`
+	Total        int `json:"total"`
+	SavedObjects []struct {
+		ID         string `json:"id"`
+		Type       string `json:"type"`
+		UpdatedAt  string `json:"updated_at"`
+		Attributes struct {
+			Title         string `json:"title"`
+			TimeFieldName string `json:"timeFieldName"`
+		} `json:"attributes"`
+	} `json:"saved_objects"`
+}
+
+// OpenSearchTenant represents a tenant from the security plugin
+type OpenSearchTenant struct {
+	Reserved    bool   `json:"reserved"`
+	Hidden      bool   `json:"hidden"`
+	Description string `json:"description"`
+	Static      bool   `json:"static"`
+}
+
+// OpenSearchCatIndex represents a row of _cat/indices?format=json
+type OpenSearchCatIndex struct {
+	Index        string `json:"index"`
+	Health       string `json:"health"`
+	Status       string `json:"status"`
+	DocsCount    string `json:"docs.count"`
+	StoreSize    string `json:"store.size"`
+	CreationDate string `json:"creation.date"`
+}
+
+// OpenSearchDataStreamsResponse represents the _data_stream API response
+type OpenSearchDataStreamsResponse struct {
+	DataStreams []struct {
+		Name           string `json:"name"`
+		Status         string `json:"status"`
+		Template       string `json:"template"`
+		Generation     int    `json:"generation"`
+		TimestampField struct {
+			Name string `json:"name"`
+		} `json:"timestamp_field"`
+		Indices []struct {
+			IndexName string `json:"index_name"`
+		} `json:"indices"`
+	} `json:"data_streams"`
+}
+
+// OpenSearchISMPoliciesResponse represents the ISM policies API response
+type OpenSearchISMPoliciesResponse struct {
+	Policies []struct {
+		ID     string              `json:"_id"`
+		Policy OpenSearchISMPolicy `json:"policy"`
+	} `json:"policies"`
+	TotalPolicies int `json:"total_policies"`
+}
+
+// OpenSearchISMPolicy represents an Index State Management policy
+type OpenSearchISMPolicy struct {
+	PolicyID        string `json:"policy_id"`
+	Description     string `json:"description"`
+	DefaultState    string `json:"default_state"`
+	LastUpdatedTime int64  `json:"last_updated_time"`
+	States          []struct {
+		Name        string                   `json:"name"`
+		Actions     []map[string]interface{} `json:"actions"`
+		Transitions []struct {
+			StateName  string `json:"state_name"`
+			Conditions struct {
+				MinIndexAge string `json:"min_index_age"`
+			} `json:"conditions"`
+		} `json:"transitions"`
+	} `json:"states"`
+	ISMTemplate []struct {
+		IndexPatterns []string `json:"index_patterns"`
+		Priority      int      `json:"priority"`
+	} `json:"ism_template"`
+}
+
+// NewOpenSearchProvider creates a new OpenSearch provider
+func NewOpenSearchProvider(config types.ProviderConfig) (types.Provider, error) {
+	client, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &OpenSearchProvider{
+		config: config,
+		client: client,
+	}
+
+	// Amazon OpenSearch Service uses "es" for domains and "aoss" for serverless
+	if config.Auth != nil && config.Auth.Type == "aws_sigv4" {
+		service := config.Options["aws_service"]
+		if service == "" {
+			service = "es"
+		}
+		if config.Auth.Region == "" {
+			return nil, fmt.Errorf("aws_sigv4 auth requires region")
+		}
+		provider.awsService = service
+	}
+
+	return provider, nil
+}
+
+func (o *OpenSearchProvider) Name() string {
+	return "opensearch"
+}
+
+func (o *OpenSearchProvider) FetchDataViews(ctx context.Context) ([]types.DataSource, error) {
+	dataSources := []types.DataSource{}
+
+	// Index patterns require a Dashboards URL; skip them if none is configured
+	if o.dashboardsURL() != "" {
+		patterns, err := o.fetchIndexPatterns(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch index patterns: %w", err)
+		}
+		dataSources = append(dataSources, patterns...)
+	}
+
+	// ISM is optional; clusters without the plugin still get an inventory
+	policies, err := o.fetchISMPolicies(ctx)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		return nil, fmt.Errorf("failed to fetch ISM policies: %w", err)
+	}
+
+	indices, err := o.fetchIndices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch indices: %w", err)
+	}
+
+	dataStreams, err := o.fetchDataStreams(ctx, indices, policies)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch data streams: %w", err)
+	}
+
+	includeHidden := o.config.Options["include_hidden"] == "true"
+	for _, index := range indices {
+		if strings.HasPrefix(index.Index, ".") && !includeHidden {
+			continue
+		}
+		dataSources = append(dataSources, o.convertIndex(index, policies))
+	}
+	dataSources = append(dataSources, dataStreams...)
+
+	for _, policy := range policies {
+		dataSources = append(dataSources, o.convertISMPolicy(policy))
+	}
+
+	return dataSources, nil
+}
+
+func (o *OpenSearchProvider) dashboardsURL() string {
+	return strings.TrimSuffix(o.config.Options["dashboards_url"], "/")
+}
+
+// fetchIndexPatterns lists index patterns in the global tenant and every
+// tenant defined in the security plugin
+func (o *OpenSearchProvider) fetchIndexPatterns(ctx context.Context) ([]types.DataSource, error) {
+	tenants, err := o.fetchTenants(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dataSources := []types.DataSource{}
+	for _, tenant := range tenants {
+		patterns, err := o.fetchTenantIndexPatterns(ctx, tenant)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", tenant, err)
+		}
+		dataSources = append(dataSources, patterns...)
+	}
+	return dataSources, nil
+}
+
+// fetchTenants returns tenant names, always starting with "global". Clusters
+// without the security plugin only have the global tenant.
+func (o *OpenSearchProvider) fetchTenants(ctx context.Context) ([]string, error) {
+	tenants := []string{"global"}
+	if o.config.Options["tenants"] == "global" {
+		return tenants, nil
+	}
+
+	var tenantMap map[string]OpenSearchTenant
+	err := o.getJSON(ctx, o.clusterURL("/_plugins/_security/api/tenants"), nil, &tenantMap)
+	// Without the security plugin (404) or its admin API permissions (403),
+	// only the global tenant is inventoried
+	if isStatus(err, http.StatusNotFound) || isStatus(err, http.StatusForbidden) {
+		return tenants, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+
+	names := make([]string, 0, len(tenantMap))
+	for name, tenant := range tenantMap {
+		if tenant.Hidden || strings.EqualFold(name, "global_tenant") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append(tenants, names...), nil
+}
+
+func (o *OpenSearchProvider) fetchTenantIndexPatterns(ctx context.Context, tenant string) ([]types.DataSource, error) {
+	headers := map[string]string{"osd-xsrf": "true", "securitytenant": tenant}
+
+	dataSources := []types.DataSource{}
+	const perPage = 1000
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Add("type", "index-pattern")
+		params.Add("per_page", strconv.Itoa(perPage))
+		params.Add("page", strconv.Itoa(page))
+		endpoint := fmt.Sprintf("%s/api/saved_objects/_find?%s", o.dashboardsURL(), params.Encode())
+
+		var resp OpenSearchSavedObjectsResponse
+		if err := o.getJSON(ctx, endpoint, headers, &resp); err != nil {
+			return nil, err
+		}
+
+		for _, object := range resp.SavedObjects {
+			ds := types.DataSource{
+				ID:         tenant + ":" + object.ID,
+				Name:       object.Attributes.Title,
+				Title:      object.Attributes.Title,
+				Type:       "opensearch-index-pattern",
+				Pattern:    object.Attributes.Title,
+				Status:     "active",
+				Tags:       []string{"opensearch", "tenant:" + tenant},
+				Normalized: types.NewNormalizedFields(),
+				Metadata: map[string]interface{}{
+					"tenant":        tenant,
+					"savedObjectId": object.ID,
+				},
+			}
+			if object.Attributes.TimeFieldName != "" {
+				ds.Metadata["timeField"] = object.Attributes.TimeFieldName
+			}
+			if updatedAt, err := time.Parse(time.RFC3339, object.UpdatedAt); err == nil {
+				ds.UpdatedAt = &updatedAt
+			}
+			dataSources = append(dataSources, ds)
+		}
+
+		if len(resp.SavedObjects) == 0 || page*perPage >= resp.Total {
+			break
+		}
+	}
+	return dataSources, nil
+}
+
+// fetchIndices lists all indices including hidden data stream backing indices
+func (o *OpenSearchProvider) fetchIndices(ctx context.Context) ([]OpenSearchCatIndex, error) {
+	params := url.Values{}
+	params.Add("format", "json")
+	params.Add("bytes", "b")
+	params.Add("expand_wildcards", "all")
+	params.Add("h", "index,health,status,docs.count,store.size,creation.date")
+
+	var indices []OpenSearchCatIndex
+	if err := o.getJSON(ctx, o.clusterURL("/_cat/indices?"+params.Encode()), nil, &indices); err != nil {
+		return nil, err
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i].Index < indices[j].Index })
+	return indices, nil
+}
+
+func (o *OpenSearchProvider) fetchDataStreams(ctx context.Context, indices []OpenSearchCatIndex, policies []OpenSearchISMPolicy) ([]types.DataSource, error) {
+	var resp OpenSearchDataStreamsResponse
+	err := o.getJSON(ctx, o.clusterURL("/_data_stream"), nil, &resp)
+	if isStatus(err, http.StatusNotFound) {
+		// Data streams arrived in OpenSearch 1.0; older clusters have none
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]OpenSearchCatIndex, len(indices))
+	for _, index := range indices {
+		byName[index.Index] = index
+	}
+
+	dataSources := make([]types.DataSource, 0, len(resp.DataStreams))
+	for _, stream := range resp.DataStreams {
+		backing := make([]string, 0, len(stream.Indices))
+		var docs, bytes int64
+		var first time.Time
+		for _, ref := range stream.Indices {
+			backing = append(backing, ref.IndexName)
+			index, ok := byName[ref.IndexName]
+			if !ok {
+				continue
+			}
+			if count, ok := parseInt64(index.DocsCount); ok {
+				docs += count
+			}
+			if size, ok := parseInt64(index.StoreSize); ok {
+				bytes += size
+			}
+			if created, ok := parseInt64(index.CreationDate); ok {
+				if t := unixMillis(created); first.IsZero() || t.Before(first) {
+					first = t
+				}
+			}
+		}
+
+		n := types.NewNormalizedFields()
+		n.Enabled = boolPtr(true)
+		n.EventCount = int64Ptr(docs)
+		n.SizeBytes = int64Ptr(bytes)
+		if days, ok := retentionForIndex(stream.Name, policies); ok {
+			n.RetentionDays = intPtr(days)
+		}
+
+		ds := types.DataSource{
+			ID:         "data-stream:" + stream.Name,
+			Name:       stream.Name,
+			Title:      stream.Name,
+			Type:       "opensearch-data-stream",
+			Pattern:    stream.Name,
+			Status:     healthStatus(stream.Status, "open"),
+			Tags:       []string{"opensearch", "data-stream"},
+			Normalized: n,
+			Metadata: map[string]interface{}{
+				"health":         strings.ToLower(stream.Status),
+				"template":       stream.Template,
+				"generation":     stream.Generation,
+				"timestampField": stream.TimestampField.Name,
+				"backingIndices": backing,
+			},
+		}
+		if !first.IsZero() {
+			ds.CreatedAt = timePtr(first)
+		}
+		dataSources = append(dataSources, ds)
+	}
+	return dataSources, nil
+}
+
+func (o *OpenSearchProvider) fetchISMPolicies(ctx context.Context) ([]OpenSearchISMPolicy, error) {
+	const size = 1000
+	policies := []OpenSearchISMPolicy{}
+	for from := 0; ; from += size {
+		var resp OpenSearchISMPoliciesResponse
+		path := fmt.Sprintf("/_plugins/_ism/policies?size=%d&from=%d", size, from)
+		if err := o.getJSON(ctx, o.clusterURL(path), nil, &resp); err != nil {
+			return nil, err
+		}
+
+		for _, entry := range resp.Policies {
+			policy := entry.Policy
+			if policy.PolicyID == "" {
+				policy.PolicyID = entry.ID
+			}
+			policies = append(policies, policy)
+		}
+		if len(resp.Policies) < size || len(policies) >= resp.TotalPolicies {
+			return policies, nil
+		}
+	}
+}
+
+func (o *OpenSearchProvider) convertIndex(index OpenSearchCatIndex, policies []OpenSearchISMPolicy) types.DataSource {
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(index.Status == "open")
+	if count, ok := parseInt64(index.DocsCount); ok {
+		n.EventCount = int64Ptr(count)
+	}
+	if size, ok := parseInt64(index.StoreSize); ok {
+		n.SizeBytes = int64Ptr(size)
+	}
+
+	metadata := map[string]interface{}{
+		"health":    index.Health,
+		"status":    index.Status,
+		"docsCount": index.DocsCount,
+		"storeSize": index.StoreSize,
+	}
+	if policyID, days, ok := policyForIndex(index.Index, policies); ok {
+		metadata["ismPolicy"] = policyID
+		if days > 0 {
+			n.RetentionDays = intPtr(days)
+		}
+	}
+
+	ds := types.DataSource{
+		ID:         "index:" + index.Index,
+		Name:       index.Index,
+		Title:      index.Index,
+		Type:       "opensearch-index",
+		Pattern:    index.Index,
+		Status:     healthStatus(index.Health, index.Status),
+		Tags:       []string{"opensearch", "index"},
+		Normalized: n,
+		Metadata:   metadata,
+	}
+	if created, ok := parseInt64(index.CreationDate); ok {
+		ds.CreatedAt = timePtr(unixMillis(created))
+	}
+	return ds
+}
+
+func (o *OpenSearchProvider) convertISMPolicy(policy OpenSearchISMPolicy) types.DataSource {
+	patterns := []string{}
+	for _, template := range policy.ISMTemplate {
+		patterns = append(patterns, template.IndexPatterns...)
+	}
+	states := make([]string, 0, len(policy.States))
+	for _, state := range policy.States {
+		states = append(states, state.Name)
+	}
+
+	n := types.NewNormalizedFields()
+	if days, ok := policyRetentionDays(policy); ok {
+		n.RetentionDays = intPtr(days)
+	}
+
+	ds := types.DataSource{
+		ID:          "ism-policy:" + policy.PolicyID,
+		Name:        policy.PolicyID,
+		Title:       policy.PolicyID,
+		Type:        "opensearch-ism-policy",
+		Pattern:     strings.Join(patterns, ","),
+		Description: policy.Description,
+		Status:      "active",
+		Tags:        []string{"opensearch", "ism"},
+		Normalized:  n,
+		Metadata: map[string]interface{}{
+			"defaultState":  policy.DefaultState,
+			"states":        states,
+			"indexPatterns": patterns,
+		},
+	}
+	if policy.LastUpdatedTime > 0 {
+		ds.UpdatedAt = timePtr(unixMillis(policy.LastUpdatedTime))
+	}
+	return ds
+}
+
+// healthStatus maps index health and open/close state to a DataSource status
+func healthStatus(health, status string) string {
+	switch {
+	case status == "close":
+		return "disabled"
+	case strings.EqualFold(health, "red"):
+		return "degraded"
+	}
+	return "active"
+}
+
+// policyForIndex finds the ISM policy whose template applies to the index,
+// preferring the highest priority, and returns its retention
+func policyForIndex(index string, policies []OpenSearchISMPolicy) (string, int, bool) {
+	bestPriority := -1
+	var best *OpenSearchISMPolicy
+	for i, policy := range policies {
+		for _, template := range policy.ISMTemplate {
+			if template.Priority <= bestPriority {
+				continue
+			}
+			for _, pattern := range template.IndexPatterns {
+				if indexPatternMatches(pattern, index) {
+					best = &policies[i]
+					bestPriority = template.Priority
+					break
+				}
+			}
+		}
+	}
+	if best == nil {
+		return "", 0, false
+	}
+	days, _ := policyRetentionDays(*best)
+	return best.PolicyID, days, true
+}
+
+func retentionForIndex(index string, policies []OpenSearchISMPolicy) (int, bool) {
+	_, days, ok := policyForIndex(index, policies)
+	return days, ok && days > 0
+}
+
+// policyRetentionDays returns the min_index_age of the transition into the
+// first state that deletes the index
+func policyRetentionDays(policy OpenSearchISMPolicy) (int, bool) {
+	deleteStates := make(map[string]bool)
+	for _, state := range policy.States {
+		for _, action := range state.Actions {
+			if _, ok := action["delete"]; ok {
+				deleteStates[state.Name] = true
+			}
+		}
+	}
+
+	for _, state := range policy.States {
+		for _, transition := range state.Transitions {
+			if !deleteStates[transition.StateName] {
+				continue
+			}
+			if age, ok := parseIndexAge(transition.Conditions.MinIndexAge); ok {
+				return int(age / (24 * time.Hour)), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// parseIndexAge parses OpenSearch time units such as "30d", "12h" or "90m"
+func parseIndexAge(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if len(value) < 2 {
+		return 0, false
+	}
+	units := map[string]time.Duration{
+		"d": 24 * time.Hour, "h": time.Hour, "m": time.Minute, "s": time.Second,
+	}
+	unit, ok := units[value[len(value)-1:]]
+	if !ok {
+		return 0, false
+	}
+	amount, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(amount) * unit, true
+}
+
+// indexPatternMatches matches OpenSearch index patterns, where only '*' is special
+func indexPatternMatches(pattern, index string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == index
+	}
+	if !strings.HasPrefix(index, parts[0]) {
+		return false
+	}
+	rest := index[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(rest, part)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(part):]
+	}
+	return strings.HasSuffix(rest, parts[len(parts)-1])
+}
+
+func (o *OpenSearchProvider) clusterURL(path string) string {
+	return strings.TrimSuffix(o.config.Endpoint, "/") + path
+}
+
+// getJSON performs an authenticated GET and decodes the JSON response
+func (o *OpenSearchProvider) getJSON(ctx context.Context, url string, headers map[string]string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	if err := o.addAuth(ctx, req); err != nil {
+		return err
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return readJSONResponse(resp, "opensearch", out)
+}
+
+func (o *OpenSearchProvider) addAuth(ctx context.Context, req *http.Request) error {
+	if o.awsService != "" {
+		credentials, err := o.awsCredentials(ctx)
+		if err != nil {
+			return err
+		}
+		signer := &sigV4Signer{credentials: credentials, region: o.config.Auth.Region, service: o.awsService, now: time.Now}
+		signer.sign(req, nil)
+		return nil
+	}
+	auth := o.config.Auth
+	if auth == nil {
+		return nil
+	}
+	switch auth.Type {
+	case "basic":
+		req.SetBasicAuth(auth.Username, auth.Password)
+	case "bearer":
+		req.Header.Set("Authorization", "Bearer "+auth.Token)
+	case "api_key":
+		req.Header.Set("Authorization", "ApiKey "+auth.APIKey)
+	}
+	return nil
+}
+
+// awsCredentials resolves the AWS credential chain once per provider, and
+// again when assumed-role credentials are about to expire
+func (o *OpenSearchProvider) awsCredentials(ctx context.Context) (awsCredentials, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.aws == nil || !o.aws.valid(time.Now()) {
+		credentials, err := resolveAWSCredentials(ctx, o.client, o.config.Auth, o.config.Options)
+		if err != nil {
+			return awsCredentials{}, fmt.Errorf("failed to resolve AWS credentials: %w", err)
+		}
+		o.aws = &credentials
+	}
+	return *o.aws, nil
+}
+
+func (o *OpenSearchProvider) ValidateConnection(ctx context.Context) error {
+	var info struct {
+		Version struct {
+			Distribution string `json:"distribution"`
+			Number       string `json:"number"`
+		} `json:"version"`
+	}
+	if err := o.getJSON(ctx, o.clusterURL("/"), nil, &info); err != nil {
+		return fmt.Errorf("failed to connect to OpenSearch: %w", err)
+	}
+	return nil
+}
+
+func (o *OpenSearchProvider) GetCapabilities() types.ProviderCapabilities {
+	return types.ProviderCapabilities{
+		SupportsRealTimeQueries: true,
+		SupportsHistoricalData:  true,
+		SupportedDataTypes:      []string{"opensearch-index-pattern", "opensearch-index", "opensearch-data-stream", "opensearch-ism-policy"},
+		RequiresAuthentication:  o.config.Auth != nil,
+	}
+}
@@ -0,0 +1,295 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+// newOpenSearchStandIn serves canned cluster and Dashboards responses
+func newOpenSearchStandIn(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	reply := func(w http.ResponseWriter, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(v)
+	}
+
+	mux.HandleFunc("/_plugins/_security/api/tenants", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, map[string]interface{}{
+			"global_tenant": map[string]interface{}{"reserved": true},
+			"soc":           map[string]interface{}{"description": "SOC analysts"},
+			"internal":      map[string]interface{}{"hidden": true},
+		})
+	})
+	mux.HandleFunc("/api/saved_objects/_find", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("osd-xsrf") != "true" {
+			http.Error(w, "missing osd-xsrf", http.StatusBadRequest)
+			return
+		}
+		title := "logs-*"
+		if r.Header.Get("securitytenant") == "soc" {
+			title = "firewall-*"
+		}
+		reply(w, map[string]interface{}{
+			"total": 1,
+			"saved_objects": []interface{}{map[string]interface{}{
+				"id":         "p1",
+				"type":       "index-pattern",
+				"attributes": map[string]interface{}{"title": title, "timeFieldName": "@timestamp"},
+			}},
+		})
+	})
+	mux.HandleFunc("/_plugins/_ism/policies", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, map[string]interface{}{
+			"total_policies": 1,
+			"policies": []interface{}{map[string]interface{}{
+				"_id": "logs-retention",
+				"policy": map[string]interface{}{
+					"policy_id":     "logs-retention",
+					"default_state": "hot",
+					"states": []interface{}{
+						map[string]interface{}{
+							"name":        "hot",
+							"actions":     []interface{}{},
+							"transitions": []interface{}{map[string]interface{}{"state_name": "delete", "conditions": map[string]interface{}{"min_index_age": "30d"}}},
+						},
+						map[string]interface{}{
+							"name":    "delete",
+							"actions": []interface{}{map[string]interface{}{"delete": map[string]interface{}{}}},
+						},
+					},
+					"ism_template": []interface{}{map[string]interface{}{"index_patterns": []string{"logs-*", ".ds-logs-*"}, "priority": 10}},
+				},
+			}},
+		})
+	})
+	mux.HandleFunc("/_cat/indices", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, []map[string]string{
+			{"index": "logs-app", "health": "green", "status": "open", "docs.count": "100", "store.size": "2048", "creation.date": "1700000000000"},
+			{"index": "firewall-old", "health": "yellow", "status": "close"},
+			{"index": "broken", "health": "red", "status": "open", "docs.count": "5", "store.size": "10"},
+			{"index": ".ds-logs-web-000001", "health": "green", "status": "open", "docs.count": "40", "store.size": "400", "creation.date": "1700000000000"},
+			{"index": ".ds-logs-web-000002", "health": "green", "status": "open", "docs.count": "60", "store.size": "600", "creation.date": "1700086400000"},
+		})
+	})
+	mux.HandleFunc("/_data_stream", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, map[string]interface{}{
+			"data_streams": []interface{}{map[string]interface{}{
+				"name":            "logs-web",
+				"status":          "GREEN",
+				"template":        "logs-template",
+				"generation":      2,
+				"timestamp_field": map[string]string{"name": "@timestamp"},
+				"indices": []interface{}{
+					map[string]string{"index_name": ".ds-logs-web-000001"},
+					map[string]string{"index_name": ".ds-logs-web-000002"},
+				},
+			}},
+		})
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		reply(w, map[string]interface{}{"version": map[string]string{"distribution": "opensearch", "number": "2.11.0"}})
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestOpenSearchFetchDataViews(t *testing.T) {
+	server := newOpenSearchStandIn(t)
+	defer server.Close()
+
+	provider, err := NewOpenSearchProvider(types.ProviderConfig{
+		Type:     "opensearch",
+		Endpoint: server.URL,
+		Options:  map[string]string{"dashboards_url": server.URL},
+		Auth:     &types.AuthConfig{Type: "basic", Username: "admin", Password: "admin"},
+		Timeout:  5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := provider.ValidateConnection(context.Background()); err != nil {
+		t.Fatalf("ValidateConnection: %v", err)
+	}
+
+	sources, err := provider.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+
+	byID := make(map[string]types.DataSource)
+	for _, ds := range sources {
+		byID[ds.ID] = ds
+	}
+	if len(byID) != 7 {
+		ids := make([]string, 0, len(byID))
+		for id := range byID {
+			ids = append(ids, id)
+		}
+		t.Fatalf("expected 7 data sources, got %d: %s", len(byID), strings.Join(ids, ", "))
+	}
+
+	if ds := byID["soc:p1"]; ds.Pattern != "firewall-*" || ds.Metadata["tenant"] != "soc" {
+		t.Errorf("unexpected soc tenant index pattern %+v", ds)
+	}
+	if _, ok := byID["internal:p1"]; ok {
+		t.Error("hidden tenant should be skipped")
+	}
+
+	app := byID["index:logs-app"]
+	if app.Normalized.RetentionDays == nil || *app.Normalized.RetentionDays != 30 {
+		t.Errorf("expected 30 day retention from ISM, got %v", app.Normalized.RetentionDays)
+	}
+	if app.Normalized.EventCount == nil || *app.Normalized.EventCount != 100 {
+		t.Errorf("expected 100 docs, got %v", app.Normalized.EventCount)
+	}
+	if ds := byID["index:firewall-old"]; ds.Status != "disabled" || *ds.Normalized.Enabled {
+		t.Errorf("closed index should be disabled, got %s", ds.Status)
+	}
+	if ds := byID["index:broken"]; ds.Status != "degraded" {
+		t.Errorf("red index should be degraded, got %s", ds.Status)
+	}
+	if _, ok := byID["index:.ds-logs-web-000001"]; ok {
+		t.Error("hidden backing indices should be skipped")
+	}
+
+	stream := byID["data-stream:logs-web"]
+	if stream.Normalized.EventCount == nil || *stream.Normalized.EventCount != 100 {
+		t.Errorf("expected data stream docs summed to 100, got %v", stream.Normalized.EventCount)
+	}
+	if stream.Normalized.SizeBytes == nil || *stream.Normalized.SizeBytes != 1000 {
+		t.Errorf("expected data stream size 1000, got %v", stream.Normalized.SizeBytes)
+	}
+	if stream.Normalized.RetentionDays == nil || *stream.Normalized.RetentionDays != 30 {
+		t.Errorf("expected data stream retention 30, got %v", stream.Normalized.RetentionDays)
+	}
+
+	if ds := byID["ism-policy:logs-retention"]; ds.Pattern != "logs-*,.ds-logs-*" {
+		t.Errorf("unexpected ISM policy pattern %q", ds.Pattern)
+	}
+}
+
+func TestOpenSearchSigV4Auth(t *testing.T) {
+	var authorization, contentHash string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		contentHash = r.Header.Get("X-Amz-Content-Sha256")
+		w.Write([]byte(`{"version":{"number":"2.11.0"}}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewOpenSearchProvider(types.ProviderConfig{
+		Type:     "opensearch",
+		Endpoint: server.URL,
+		Options:  map[string]string{"aws_service": "aoss"},
+		Auth: &types.AuthConfig{
+			Type:            "aws_sigv4",
+			AccessKeyID:     "AKIDEXAMPLE",
+			SecretAccessKey: "secret",
+			Region:          "eu-west-1",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := provider.ValidateConnection(context.Background()); err != nil {
+		t.Fatalf("ValidateConnection: %v", err)
+	}
+
+	if !strings.Contains(authorization, "/eu-west-1/aoss/aws4_request") {
+		t.Errorf("unexpected Authorization header %q", authorization)
+	}
+	if contentHash == "" {
+		t.Error("expected x-amz-content-sha256 for aoss")
+	}
+}
+
+func TestOpenSearchSigV4EnvironmentCredentials(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDENV")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "env-secret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Write([]byte(`{"version":{"number":"2.11.0"}}`))
+	}))
+	defer server.Close()
+
+	// Passes config validation without keys, so construction must not need them
+	provider, err := NewOpenSearchProvider(types.ProviderConfig{
+		Type:     "opensearch",
+		Endpoint: server.URL,
+		Auth:     &types.AuthConfig{Type: "aws_sigv4", Region: "us-east-1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := provider.ValidateConnection(context.Background()); err != nil {
+		t.Fatalf("ValidateConnection: %v", err)
+	}
+	if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKIDENV/") || !strings.Contains(authorization, "/us-east-1/es/aws4_request") {
+		t.Errorf("unexpected Authorization header %q", authorization)
+	}
+
+	if _, err := NewWazuhProvider(types.ProviderConfig{
+		Type:     "wazuh",
+		Endpoint: server.URL,
+		Auth:     &types.AuthConfig{Type: "aws_sigv4", Region: "us-east-1"},
+		Options:  map[string]string{"indexer_url": server.URL},
+	}); err != nil {
+		t.Errorf("keyless aws_sigv4 should reach the Wazuh indexer: %v", err)
+	}
+}
+
+func TestOpenSearchNonAdminTenantsAndPolicyPaging(t *testing.T) {
+	const totalPolicies = 1001
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/_plugins/_security/api/tenants":
+			http.Error(w, `{"status":"FORBIDDEN"}`, http.StatusForbidden)
+		case "/_plugins/_ism/policies":
+			from, _ := strconv.Atoi(r.URL.Query().Get("from"))
+			size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+			var page []interface{}
+			for i := from; i < from+size && i < totalPolicies; i++ {
+				page = append(page, map[string]interface{}{"_id": fmt.Sprintf("policy-%d", i), "policy": map[string]interface{}{}})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"total_policies": totalPolicies, "policies": page})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewOpenSearchProvider(types.ProviderConfig{Type: "opensearch", Endpoint: server.URL, Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := provider.(*OpenSearchProvider)
+
+	tenants, err := o.fetchTenants(context.Background())
+	if err != nil || len(tenants) != 1 || tenants[0] != "global" {
+		t.Errorf("expected a 403 to fall back to the global tenant, got %v, %v", tenants, err)
+	}
+
+	policies, err := o.fetchISMPolicies(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(policies) != totalPolicies || policies[totalPolicies-1].PolicyID != "policy-1000" {
+		t.Errorf("expected %d policies across pages, got %d", totalPolicies, len(policies))
+	}
+}
@@ -0,0 +1,280 @@
+package providers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/logfiend/internal/types"
+	"github.com/logfiend/pluginsdk"
+)
+
+// PluginProvider runs an external executable (options.path) speaking the
+// pluginsdk stdio protocol. Each call starts a fresh session: the plugin is
+// launched, receives the ProviderConfig in its Initialize request over stdin,
+// answers, and is stopped, so no process outlives a call. Sessions are bounded
+// by the provider timeout.
+type PluginProvider struct {
+	config       types.ProviderConfig
+	command      string
+	args         []string
+	env          []string
+	timeout      time.Duration
+	name         string
+	capabilities types.ProviderCapabilities
+}
+
+// pluginEnvironment is passed through to plugins; anything else, credentials
+// in particular, has to be listed in options.env
+var pluginEnvironment = []string{"PATH", "HOME", "TMPDIR", "TEMP", "TMP", "LANG", "SystemRoot", "USERPROFILE"}
+
+const (
+	defaultPluginTimeout = 30 * time.Second
+	// maxPluginOutput bounds everything read from a plugin's stdout in one session
+	maxPluginOutput = 256 << 20
+	// maxPluginStderr is how much plugin stderr is kept for error messages
+	maxPluginStderr = 4096
+)
+
+func NewPluginProvider(config types.ProviderConfig) (types.Provider, error) {
+	path := strings.TrimSpace(config.Options["path"])
+	if path == "" {
+		return nil, fmt.Errorf("plugin provider requires options.path")
+	}
+	command, err := pluginExecutable(path)
+	if err != nil {
+		return nil, err
+	}
+
+	env := []string{"LOGFIEND_PLUGIN_PROTOCOL=" + strconv.Itoa(pluginsdk.ProtocolVersion)}
+	names := append([]string{}, pluginEnvironment...)
+	for _, name := range strings.Split(config.Options["env"], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	for _, name := range names {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+
+	return newPluginProvider(config, command, nil, env)
+}
+
+// newPluginProvider asks the plugin for its name and capabilities, which the
+// Provider interface returns without a context or error
+func newPluginProvider(config types.ProviderConfig, command string, args, env []string) (*PluginProvider, error) {
+	p := &PluginProvider{
+		config:  config,
+		command: command,
+		args:    args,
+		env:     env,
+		timeout: config.Timeout,
+	}
+	if p.timeout <= 0 {
+		p.timeout = defaultPluginTimeout
+	}
+
+	err := p.session(context.Background(), func(s *pluginSession) error {
+		if err := s.call(pluginsdk.MethodName, nil, &p.name); err != nil {
+			return err
+		}
+		return s.call(pluginsdk.MethodGetCapabilities, nil, &p.capabilities)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start plugin %s: %w", command, err)
+	}
+	if p.name == "" {
+		return nil, fmt.Errorf("plugin %s returned an empty name", command)
+	}
+	return p, nil
+}
+
+// pluginExecutable checks that path is a relative, executable regular file
+// that only its owner can modify, and returns it in a form exec will not
+// look up in PATH
+func pluginExecutable(path string) (string, error) {
+	cleanPath := filepath.Clean(path)
+	if filepath.IsAbs(cleanPath) {
+		return "", fmt.Errorf("absolute paths not allowed for security: %s", cleanPath)
+	}
+	info, err := os.Stat(cleanPath)
+	if err != nil {
+		return "", fmt.Errorf("plugin not found: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("plugin %s is not a regular file", cleanPath)
+	}
+	if runtime.GOOS != "windows" {
+		if info.Mode().Perm()&0111 == 0 {
+			return "", fmt.Errorf("plugin %s is not executable", cleanPath)
+		}
+		if info.Mode().Perm()&0022 != 0 {
+			return "", fmt.Errorf("plugin %s is writable by group or others", cleanPath)
+		}
+	}
+	if !strings.ContainsRune(cleanPath, filepath.Separator) {
+		cleanPath = "." + string(filepath.Separator) + cleanPath
+	}
+	return cleanPath, nil
+}
+
+func (p *PluginProvider) Name() string {
+	return p.name
+}
+
+func (p *PluginProvider) FetchDataViews(ctx context.Context) ([]types.DataSource, error) {
+	var result pluginsdk.FetchDataViewsResult
+	err := p.session(ctx, func(s *pluginSession) error {
+		return s.call(pluginsdk.MethodFetchDataViews, nil, &result)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch data sources from plugin %s: %w", p.name, err)
+	}
+	if result.DataSources == nil {
+		result.DataSources = []types.DataSource{}
+	}
+	return result.DataSources, nil
+}
+
+func (p *PluginProvider) ValidateConnection(ctx context.Context) error {
+	err := p.session(ctx, func(s *pluginSession) error {
+		return s.call(pluginsdk.MethodValidateConnection, nil, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to plugin %s: %w", p.name, err)
+	}
+	return nil
+}
+
+func (p *PluginProvider) GetCapabilities() types.ProviderCapabilities {
+	return p.capabilities
+}
+
+// pluginSession is one running plugin process
+type pluginSession struct {
+	stdin  io.Writer
+	stdout *bufio.Reader
+	nextID int64
+}
+
+// session launches the plugin, initializes it with the provider config and
+// runs fn, killing the plugin if the timeout passes first
+func (p *PluginProvider) session(ctx context.Context, fn func(*pluginSession) error) error {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.command, p.args...)
+	cmd.Env = p.env
+	stderr := &limitedBuffer{limit: maxPluginStderr}
+	cmd.Stderr = stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	s := &pluginSession{stdin: stdin, stdout: bufio.NewReader(io.LimitReader(stdout, maxPluginOutput))}
+	err = s.call(pluginsdk.MethodInitialize, pluginsdk.InitializeParams{
+		ProtocolVersion: pluginsdk.ProtocolVersion,
+		Config:          p.config,
+	}, nil)
+	if err == nil {
+		err = fn(s)
+	}
+
+	// Closing stdin asks the plugin to exit; the context kills it otherwise
+	stdin.Close()
+	waitErr := cmd.Wait()
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("plugin timed out after %s", p.timeout)
+	}
+	if err == nil && waitErr != nil {
+		err = fmt.Errorf("plugin exited: %w", waitErr)
+	}
+	if err != nil && stderr.Len() > 0 {
+		return fmt.Errorf("%w (stderr: %s)", err, strings.TrimSpace(stderr.String()))
+	}
+	return err
+}
+
+// call sends a request and decodes the result into result, if not nil
+func (s *pluginSession) call(method string, params, result interface{}) error {
+	s.nextID++
+	request := pluginsdk.Request{JSONRPC: "2.0", ID: s.nextID, Method: method}
+	if params != nil {
+		encoded, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		request.Params = encoded
+	}
+	line, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	if _, err := s.stdin.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("%s: failed to send request: %w", method, err)
+	}
+
+	reply, err := s.stdout.ReadBytes('\n')
+	if err != nil && (err != io.EOF || len(reply) == 0) {
+		return fmt.Errorf("%s: no response: %w", method, err)
+	}
+	var response pluginsdk.Response
+	if err := json.Unmarshal(reply, &response); err != nil {
+		return fmt.Errorf("%s: invalid response: %w", method, err)
+	}
+	if response.JSONRPC != "2.0" || response.ID != request.ID {
+		return fmt.Errorf("%s: response does not match request %d", method, request.ID)
+	}
+	if response.Error != nil {
+		if response.Error.Code == pluginsdk.CodeProviderError {
+			return errors.New(response.Error.Message)
+		}
+		return fmt.Errorf("%s: %s (code %d)", method, response.Error.Message, response.Error.Code)
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("%s: invalid result: %w", method, err)
+	}
+	return nil
+}
+
+// limitedBuffer keeps the first limit bytes written to it
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(data []byte) (int, error) {
+	if room := b.limit - b.Len(); room > 0 {
+		if len(data) > room {
+			b.Buffer.Write(data[:room])
+		} else {
+			b.Buffer.Write(data)
+		}
+	}
+	return len(data), nil
+}
@@ -0,0 +1,185 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+	"github.com/logfiend/pluginsdk"
+)
+
+// TestMain lets the test binary double as a plugin: NewPluginProvider runs it
+// with LOGFIEND_TEST_PLUGIN passed through options.env
+func TestMain(m *testing.M) {
+	if os.Getenv("LOGFIEND_TEST_PLUGIN") != "" {
+		pluginsdk.Serve(newHelperPlugin)
+	}
+	os.Exit(m.Run())
+}
+
+type helperPlugin struct {
+	mode string
+}
+
+func newHelperPlugin(config types.ProviderConfig) (types.Provider, error) {
+	if config.Auth == nil || config.Auth.Token != "plugin-secret" {
+		return nil, fmt.Errorf("missing credentials")
+	}
+	return &helperPlugin{mode: config.Options["mode"]}, nil
+}
+
+func (h *helperPlugin) Name() string {
+	return "helper"
+}
+
+func (h *helperPlugin) FetchDataViews(ctx context.Context) ([]types.DataSource, error) {
+	switch h.mode {
+	case "hang":
+		time.Sleep(time.Minute)
+	case "fail":
+		return nil, fmt.Errorf("upstream returned 503")
+	}
+	// Serve redirects stray prints away from the protocol stream
+	fmt.Println("not a response")
+	_, leaked := os.LookupEnv("LOGFIEND_TEST_SECRET")
+	return []types.DataSource{{
+		ID:         "feed:1",
+		Name:       "Feed",
+		Type:       "helper-feed",
+		Status:     "active",
+		Normalized: types.NewNormalizedFields(),
+		Metadata:   map[string]interface{}{"leaked": leaked},
+	}}, nil
+}
+
+func (h *helperPlugin) ValidateConnection(ctx context.Context) error {
+	return nil
+}
+
+func (h *helperPlugin) GetCapabilities() types.ProviderCapabilities {
+	return types.ProviderCapabilities{SupportedDataTypes: []string{"helper-feed"}, RequiresAuthentication: true}
+}
+
+// helperPluginPath links the test binary into a temporary directory and
+// returns it as a path relative to the working directory
+func helperPluginPath(t *testing.T) string {
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(t.TempDir(), "helper-plugin")
+	if err := os.Symlink(executable, link); err != nil {
+		t.Skipf("cannot link test binary: %v", err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rel, err := filepath.Rel(wd, link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rel
+}
+
+func TestPluginProvider(t *testing.T) {
+	path := helperPluginPath(t)
+	t.Setenv("LOGFIEND_TEST_PLUGIN", "1")
+	t.Setenv("LOGFIEND_TEST_SECRET", "must-not-leak")
+
+	config := types.ProviderConfig{
+		Type:    "plugin",
+		Auth:    &types.AuthConfig{Type: "bearer", Token: "plugin-secret"},
+		Options: map[string]string{"path": path, "env": "LOGFIEND_TEST_PLUGIN"},
+		Timeout: 10 * time.Second,
+	}
+	provider, err := NewPluginProvider(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if provider.Name() != "helper" || !provider.GetCapabilities().RequiresAuthentication {
+		t.Errorf("unexpected handshake: %s %+v", provider.Name(), provider.GetCapabilities())
+	}
+	if err := provider.ValidateConnection(context.Background()); err != nil {
+		t.Fatalf("ValidateConnection: %v", err)
+	}
+	sources, err := provider.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+	if len(sources) != 1 || sources[0].ID != "feed:1" || sources[0].Normalized == nil {
+		t.Fatalf("unexpected sources %+v", sources)
+	}
+	if sources[0].Metadata["leaked"] != false {
+		t.Errorf("environment not listed in options.env reached the plugin")
+	}
+
+	config.Options["mode"] = "fail"
+	failing, err := NewPluginProvider(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := failing.FetchDataViews(context.Background()); err == nil || !strings.Contains(err.Error(), "upstream returned 503") {
+		t.Errorf("expected the plugin's error, got %v", err)
+	}
+
+	config.Options["mode"] = "hang"
+	config.Timeout = 2 * time.Second
+	hanging, err := NewPluginProvider(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if _, err := hanging.FetchDataViews(context.Background()); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("timeout took %s", elapsed)
+	}
+
+	config.Auth = nil
+	if _, err := NewPluginProvider(config); err == nil || !strings.Contains(err.Error(), "missing credentials") {
+		t.Errorf("expected the plugin to reject the config, got %v", err)
+	}
+}
+
+func TestPluginExecutable(t *testing.T) {
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	write := func(name string, mode os.FileMode) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"), mode); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(path, mode); err != nil {
+			t.Fatal(err)
+		}
+		rel, err := filepath.Rel(wd, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rel
+	}
+
+	if _, err := pluginExecutable(write("ok", 0755)); err != nil {
+		t.Errorf("expected an owner-writable executable to be accepted: %v", err)
+	}
+	for name, path := range map[string]string{
+		"absolute":       filepath.Join(dir, "ok"),
+		"missing":        "no-such-plugin",
+		"not executable": write("plain", 0644),
+		"world writable": write("shared", 0777),
+	} {
+		if _, err := pluginExecutable(path); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
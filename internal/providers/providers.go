@@ -48,4 +48,24 @@ func init() {
 	Register("splunk", NewSplunkProvider)
 	Register("sentinel", NewSentinelProvider)
 	Register("qradar", NewQRadarProvider)
+	Register("opensearch", NewOpenSearchProvider)
+	Register("graylog", NewGraylogProvider)
+	Register("sumologic", NewSumoLogicProvider)
+	Register("chronicle", NewChronicleProvider)
+	Register("logscale", NewLogScaleProvider)
+	Register("datadog", NewDatadogProvider)
+	Register("loki", NewLokiProvider)
+	Register("wazuh", NewWazuhProvider)
+	Register("insightidr", NewInsightIDRProvider)
+	Register("logrhythm", NewLogRhythmProvider)
+	Register("arcsight", NewArcSightProvider)
+	Register("exabeam", NewExabeamProvider)
+	Register("cribl", NewCriblProvider)
+	Register("cloudwatch", NewCloudWatchProvider)
+	Register("gcplogging", NewGCPLoggingProvider)
+	Register("securitylake", NewSecurityLakeProvider)
+	Register("defenderxdr", NewDefenderXDRProvider)
+	Register("rest", NewRESTProvider)
+	Register("file", NewFileProvider)
+	Register("plugin", NewPluginProvider)
 }
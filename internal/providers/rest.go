@@ -0,0 +1,1060 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/logfiend/internal/types"
+	"gopkg.in/yaml.v3"
+)
+
+// RESTProvider implements the Provider interface for any JSON API described
+// declaratively instead of in Go. The description comes from the YAML file
+// named by options.definition, or from flat options for a single resource.
+// examples/rest holds definitions reproducing the Splunk and QRadar providers.
+type RESTProvider struct {
+	config     types.ProviderConfig
+	client     *http.Client
+	baseURL    string
+	definition RESTDefinition
+	paths      map[string]jsonPath
+
+	mu    sync.Mutex
+	token cachedToken
+	aws   *awsCredentials
+}
+
+// RESTDefinition describes how to inventory a REST API
+type RESTDefinition struct {
+	Vendor    string            `yaml:"vendor"`   // first tag on every data source, default "rest"
+	Auth      RESTAuth          `yaml:"auth"`     // where the configured credential goes
+	Headers   map[string]string `yaml:"headers"`  // sent with every request
+	Validate  string            `yaml:"validate"` // path ValidateConnection requests; default the first resource
+	Resources []RESTResource    `yaml:"resources"`
+}
+
+// RESTAuth places the provider's auth credential on each request. Bearer
+// tokens, client-credentials tokens and API keys go in Header (default
+// Authorization) after Prefix (default "Bearer " for tokens), or in the
+// QueryParam query parameter when set. Basic auth needs no placement and
+// aws_sigv4 signs for Service.
+type RESTAuth struct {
+	Header     string `yaml:"header"`
+	Prefix     string `yaml:"prefix"`
+	QueryParam string `yaml:"query_param"`
+	Service    string `yaml:"service"`
+}
+
+// RESTResource is one listing endpoint whose items become data sources.
+// Path, Body and Headers are templates over {{offset}}, {{limit}},
+// {{last}} (offset+limit-1), {{page}}, {{cursor}} and {{options.<key>}}.
+// Values are URL-escaped in Path and JSON-escaped in Body, so a string
+// placeholder in the body goes inside quotes: {"after": "{{cursor}}"}.
+type RESTResource struct {
+	Name       string              `yaml:"name"`
+	Method     string              `yaml:"method"`
+	Path       string              `yaml:"path"`
+	Body       string              `yaml:"body"`
+	Headers    map[string]string   `yaml:"headers"`
+	Items      string              `yaml:"items"` // JSONPath to the item array, default the whole response
+	Pagination RESTPagination      `yaml:"pagination"`
+	Type       string              `yaml:"type"` // DataSource type, default <vendor>-<name>
+	Fields     map[string]RESTExpr `yaml:"fields"`
+	Tags       []RESTExpr          `yaml:"tags"`
+	Normalized map[string]RESTExpr `yaml:"normalized"`
+	Metadata   map[string]RESTExpr `yaml:"metadata"`
+	Relations  []RESTRelation      `yaml:"relations"`
+}
+
+// RESTPagination selects how a resource is paged. offset and page advance
+// {{offset}} and {{page}} until a short page or TotalPath is reached; cursor
+// sends the value at CursorPath back as the CursorParam query parameter; link
+// follows the Link header's rel="next" URL.
+type RESTPagination struct {
+	Style       string `yaml:"style"` // none, offset, page, cursor, link
+	Limit       int    `yaml:"limit"`
+	TotalPath   string `yaml:"total_path"`
+	CursorPath  string `yaml:"cursor_path"`
+	CursorParam string `yaml:"cursor_param"`
+	MaxPages    int    `yaml:"max_pages"`
+}
+
+// RESTRelation links each item to another data source
+type RESTRelation struct {
+	Type   string   `yaml:"type"`
+	Target RESTExpr `yaml:"target"`
+}
+
+// RESTExpr maps a response item to a value. Value is a JSONPath ("$.name"),
+// a template ("index={{$.name}}") or a literal, and a leading ! negates a
+// boolean. Map translates the result and values it does not list become
+// Default, or nothing; without Map, Default replaces an empty result. In YAML
+// a plain string sets Value.
+type RESTExpr struct {
+	Value   string            `yaml:"value"`
+	Map     map[string]string `yaml:"map"`
+	Default string            `yaml:"default"`
+}
+
+// UnmarshalYAML accepts either a scalar expression or the mapping form
+func (e *RESTExpr) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		e.Value = node.Value
+		return nil
+	}
+	type plain RESTExpr
+	return node.Decode((*plain)(e))
+}
+
+const (
+	restDefaultLimit    = 100
+	restDefaultMaxPages = 1000
+)
+
+var (
+	restFields     = []string{"id", "name", "title", "description", "pattern", "status", "created_at", "updated_at"}
+	restNormalized = []string{"enabled", "event_count", "size_bytes", "size_mb", "retention_days", "retention_seconds",
+		"first_event_time", "last_event_time", "ingest_rate_eps", "vendor", "product"}
+	restRequestVars = []string{"offset", "limit", "last", "page", "cursor"}
+)
+
+// NewRESTProvider creates a provider from options.definition or, without
+// one, from the flat options described in restDefinitionFromOptions
+func NewRESTProvider(config types.ProviderConfig) (types.Provider, error) {
+	client, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &RESTProvider{
+		config:  config,
+		client:  client,
+		baseURL: strings.TrimSuffix(config.Endpoint, "/"),
+		paths:   make(map[string]jsonPath),
+	}
+
+	if file := config.Options["definition"]; file != "" {
+		data, err := readRelativeFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read REST definition: %w", err)
+		}
+		if provider.definition, err = parseRESTDefinition(data); err != nil {
+			return nil, fmt.Errorf("invalid REST definition %s: %w", file, err)
+		}
+	} else if provider.definition, err = restDefinitionFromOptions(config.Options); err != nil {
+		return nil, fmt.Errorf("invalid REST options: %w", err)
+	}
+
+	if err := provider.compile(); err != nil {
+		return nil, fmt.Errorf("invalid REST definition: %w", err)
+	}
+	return provider, nil
+}
+
+// parseRESTDefinition decodes a definition file, rejecting unknown keys so
+// typos surface with their line numbers
+func parseRESTDefinition(data []byte) (RESTDefinition, error) {
+	var definition RESTDefinition
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&definition); err != nil {
+		return definition, err
+	}
+	return definition, nil
+}
+
+// restDefinitionFromOptions builds a single-resource definition from flat
+// options: vendor, name, method, path, body, items, type, validate,
+// pagination, limit, total_path, cursor_path, cursor_param, max_pages,
+// auth.header, auth.prefix, auth.query_param, auth.service, header.<name>,
+// field.<name>, normalized.<name>, metadata.<key>, tags (comma-separated),
+// status_map ("value=status,...") and status_default.
+func restDefinitionFromOptions(options map[string]string) (RESTDefinition, error) {
+	if options["path"] == "" {
+		return RESTDefinition{}, fmt.Errorf("options.definition or options.path is required")
+	}
+
+	resource := RESTResource{
+		Name:    options["name"],
+		Method:  options["method"],
+		Path:    options["path"],
+		Body:    options["body"],
+		Items:   options["items"],
+		Type:    options["type"],
+		Headers: map[string]string{},
+		Pagination: RESTPagination{
+			Style:       options["pagination"],
+			TotalPath:   options["total_path"],
+			CursorPath:  options["cursor_path"],
+			CursorParam: options["cursor_param"],
+		},
+		Fields:     map[string]RESTExpr{},
+		Normalized: map[string]RESTExpr{},
+		Metadata:   map[string]RESTExpr{},
+	}
+	for _, key := range []string{"limit", "max_pages"} {
+		if options[key] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(options[key])
+		if err != nil || n <= 0 {
+			return RESTDefinition{}, fmt.Errorf("invalid %s: %s", key, options[key])
+		}
+		if key == "limit" {
+			resource.Pagination.Limit = n
+		} else {
+			resource.Pagination.MaxPages = n
+		}
+	}
+
+	for key, value := range options {
+		switch {
+		case strings.HasPrefix(key, "header."):
+			resource.Headers[strings.TrimPrefix(key, "header.")] = value
+		case strings.HasPrefix(key, "field."):
+			resource.Fields[strings.TrimPrefix(key, "field.")] = RESTExpr{Value: value}
+		case strings.HasPrefix(key, "normalized."):
+			resource.Normalized[strings.TrimPrefix(key, "normalized.")] = RESTExpr{Value: value}
+		case strings.HasPrefix(key, "metadata."):
+			resource.Metadata[strings.TrimPrefix(key, "metadata.")] = RESTExpr{Value: value}
+		}
+	}
+	for _, tag := range strings.Split(options["tags"], ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			resource.Tags = append(resource.Tags, RESTExpr{Value: tag})
+		}
+	}
+
+	if options["status_map"] != "" || options["status_default"] != "" {
+		status, ok := resource.Fields["status"]
+		if !ok {
+			return RESTDefinition{}, fmt.Errorf("status_map requires field.status")
+		}
+		status.Map = map[string]string{}
+		for _, pair := range strings.Split(options["status_map"], ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			value, mapped, ok := strings.Cut(pair, "=")
+			if !ok {
+				return RESTDefinition{}, fmt.Errorf("invalid status_map entry %q, expected value=status", pair)
+			}
+			status.Map[strings.TrimSpace(value)] = strings.TrimSpace(mapped)
+		}
+		status.Default = options["status_default"]
+		resource.Fields["status"] = status
+	}
+
+	return RESTDefinition{
+		Vendor:   options["vendor"],
+		Validate: options["validate"],
+		Auth: RESTAuth{
+			Header:     options["auth.header"],
+			Prefix:     options["auth.prefix"],
+			QueryParam: options["auth.query_param"],
+			Service:    options["auth.service"],
+		},
+		Resources: []RESTResource{resource},
+	}, nil
+}
+
+// compile applies defaults and checks every template and path up front, so
+// a bad definition fails at startup rather than halfway through an inventory
+func (r *RESTProvider) compile() error {
+	d := &r.definition
+	if d.Vendor == "" {
+		d.Vendor = "rest"
+	}
+	if len(d.Resources) == 0 {
+		return fmt.Errorf("no resources defined")
+	}
+
+	if auth := r.config.Auth; auth != nil {
+		switch auth.Type {
+		case "aws_sigv4":
+			if d.Auth.Service == "" {
+				return fmt.Errorf("aws_sigv4 auth requires auth.service")
+			}
+		case "client_credentials":
+			if auth.TokenURL == "" {
+				return fmt.Errorf("client_credentials auth requires auth.token_url")
+			}
+		case "basic", "bearer", "api_key":
+		default:
+			return fmt.Errorf("unsupported auth type for rest: %s", auth.Type)
+		}
+	}
+
+	for name, value := range d.Headers {
+		if err := r.compileTemplate(value, false); err != nil {
+			return fmt.Errorf("header %s: %w", name, err)
+		}
+	}
+	if err := r.compileTemplate(d.Validate, false); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	for i := range d.Resources {
+		res := &d.Resources[i]
+		if res.Name == "" {
+			res.Name = fmt.Sprintf("resource%d", i+1)
+		}
+		if err := r.compileResource(res, d.Vendor); err != nil {
+			return fmt.Errorf("resource %s: %w", res.Name, err)
+		}
+	}
+	return nil
+}
+
+func (r *RESTProvider) compileResource(res *RESTResource, vendor string) error {
+	res.Method = strings.ToUpper(res.Method)
+	if res.Method == "" {
+		res.Method = "GET"
+	}
+	if res.Method != "GET" && res.Method != "POST" {
+		return fmt.Errorf("unsupported method %s", res.Method)
+	}
+	if res.Path == "" {
+		return fmt.Errorf("path is required")
+	}
+	if res.Type == "" {
+		res.Type = vendor + "-" + res.Name
+	}
+
+	for _, template := range []string{res.Path, res.Body} {
+		if err := r.compileTemplate(template, false); err != nil {
+			return err
+		}
+	}
+	for name, value := range res.Headers {
+		if err := r.compileTemplate(value, false); err != nil {
+			return fmt.Errorf("header %s: %w", name, err)
+		}
+	}
+
+	p := &res.Pagination
+	switch p.Style {
+	case "", "none":
+		p.Style = "none"
+	case "offset", "page":
+		if p.Limit == 0 {
+			p.Limit = restDefaultLimit
+		}
+	case "cursor":
+		if p.CursorPath == "" {
+			return fmt.Errorf("cursor pagination requires cursor_path")
+		}
+		if p.CursorParam == "" {
+			p.CursorParam = "cursor"
+		}
+	case "link":
+	default:
+		return fmt.Errorf("unknown pagination style %q", p.Style)
+	}
+	if p.MaxPages == 0 {
+		p.MaxPages = restDefaultMaxPages
+	}
+	for _, path := range []string{res.Items, p.TotalPath, p.CursorPath} {
+		if err := r.compilePath(path); err != nil {
+			return err
+		}
+	}
+
+	if _, ok := res.Fields["id"]; !ok {
+		return fmt.Errorf("fields.id is required")
+	}
+	if err := r.compileExprs("fields", res.Fields, restFields); err != nil {
+		return err
+	}
+	if err := r.compileExprs("normalized", res.Normalized, restNormalized); err != nil {
+		return err
+	}
+	if err := r.compileExprs("metadata", res.Metadata, nil); err != nil {
+		return err
+	}
+	for _, tag := range res.Tags {
+		if err := r.compileExpr(tag); err != nil {
+			return fmt.Errorf("tags: %w", err)
+		}
+	}
+	for _, relation := range res.Relations {
+		switch relation.Type {
+		case types.RelationRoutesTo, types.RelationStoredIn, types.RelationProcessedBy:
+		default:
+			return fmt.Errorf("unknown relation type %q", relation.Type)
+		}
+		if err := r.compileExpr(relation.Target); err != nil {
+			return fmt.Errorf("relations: %w", err)
+		}
+	}
+	return nil
+}
+
+// compileExprs checks a set of expressions; allowed limits the keys
+func (r *RESTProvider) compileExprs(section string, exprs map[string]RESTExpr, allowed []string) error {
+	for key, expr := range exprs {
+		if allowed != nil && !containsString(allowed, key) {
+			return fmt.Errorf("unknown %s key %q (allowed: %s)", section, key, strings.Join(allowed, ", "))
+		}
+		if err := r.compileExpr(expr); err != nil {
+			return fmt.Errorf("%s.%s: %w", section, key, err)
+		}
+	}
+	return nil
+}
+
+func (r *RESTProvider) compileExpr(expr RESTExpr) error {
+	value := strings.TrimPrefix(expr.Value, "!")
+	if strings.HasPrefix(value, "$") && !strings.Contains(value, "{{") {
+		return r.compilePath(value)
+	}
+	return r.compileTemplate(value, true)
+}
+
+func (r *RESTProvider) compilePath(expr string) error {
+	if expr == "" || r.paths[expr] != nil {
+		return nil
+	}
+	path, err := compileJSONPath(expr)
+	if err != nil {
+		return err
+	}
+	r.paths[expr] = path
+	return nil
+}
+
+// compileTemplate checks a template's placeholders; item templates may read
+// the response item, request templates the paging variables
+func (r *RESTProvider) compileTemplate(template string, item bool) error {
+	names, err := templatePlaceholders(template)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		switch {
+		case strings.HasPrefix(name, "options."):
+		case item && strings.HasPrefix(name, "$"):
+			if err := r.compilePath(name); err != nil {
+				return err
+			}
+		case !item && containsString(restRequestVars, name):
+		default:
+			return fmt.Errorf("unknown placeholder {{%s}} in %q", name, template)
+		}
+	}
+	return nil
+}
+
+// templatePlaceholders lists the {{name}} placeholders in a template
+func templatePlaceholders(template string) ([]string, error) {
+	var names []string
+	for rest := template; ; {
+		start := strings.Index(rest, "{{")
+		if start < 0 {
+			return names, nil
+		}
+		end := strings.Index(rest[start:], "}}")
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated placeholder in %q", template)
+		}
+		names = append(names, strings.TrimSpace(rest[start+2:start+end]))
+		rest = rest[start+end+2:]
+	}
+}
+
+// renderTemplate substitutes placeholders; lookup learns whether the
+// placeholder sits in a URL's query string so it can escape accordingly
+func renderTemplate(template string, lookup func(name string, inQuery bool) string) string {
+	var out strings.Builder
+	rest := template
+	for {
+		start := strings.Index(rest, "{{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(rest[start:], "}}")
+		if end < 0 {
+			break
+		}
+		out.WriteString(rest[:start])
+		inQuery := strings.Contains(out.String(), "?")
+		out.WriteString(lookup(strings.TrimSpace(rest[start+2:start+end]), inQuery))
+		rest = rest[start+end+2:]
+	}
+	out.WriteString(rest)
+	return out.String()
+}
+
+func (r *RESTProvider) Name() string {
+	return "rest"
+}
+
+func (r *RESTProvider) FetchDataViews(ctx context.Context) ([]types.DataSource, error) {
+	dataSources := []types.DataSource{}
+	for _, res := range r.definition.Resources {
+		resourceSources, err := r.fetchResource(ctx, res)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %s: %w", res.Name, err)
+		}
+		dataSources = append(dataSources, resourceSources...)
+	}
+	return dataSources, nil
+}
+
+// fetchResource requests every page of a resource and converts its items
+func (r *RESTProvider) fetchResource(ctx context.Context, res RESTResource) ([]types.DataSource, error) {
+	p := res.Pagination
+	offset, page, cursor, next := 0, 1, "", ""
+
+	var dataSources []types.DataSource
+	for pages := 0; pages < p.MaxPages; pages++ {
+		vars := map[string]string{
+			"offset": strconv.Itoa(offset),
+			"limit":  strconv.Itoa(p.Limit),
+			"last":   strconv.Itoa(offset + p.Limit - 1),
+			"page":   strconv.Itoa(page),
+			"cursor": cursor,
+		}
+
+		target := next
+		if target == "" {
+			target = r.baseURL + r.renderRequest(res.Path, vars, escapeURL)
+			if p.Style == "cursor" && cursor != "" {
+				target = withQueryParam(target, p.CursorParam, cursor)
+			}
+		}
+
+		body, header, err := r.do(ctx, res.Method, target, r.renderRequest(res.Body, vars, escapeJSON), res.Headers, vars)
+		if err != nil {
+			return nil, err
+		}
+
+		items := r.items(res.Items, body)
+		for i, item := range items {
+			ds, err := r.convertItem(res, item)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", len(dataSources)+i, err)
+			}
+			dataSources = append(dataSources, ds)
+		}
+
+		switch p.Style {
+		case "offset", "page":
+			if len(items) < p.Limit {
+				return dataSources, nil
+			}
+			offset += len(items)
+			page++
+			if p.TotalPath != "" {
+				value, _ := r.path(p.TotalPath).first(body)
+				if total, ok := parseInt64(jsonString(value)); ok && int64(offset) >= total {
+					return dataSources, nil
+				}
+			}
+		case "cursor":
+			value, _ := r.path(p.CursorPath).first(body)
+			if cursor = jsonString(value); cursor == "" || len(items) == 0 {
+				return dataSources, nil
+			}
+		case "link":
+			if next, err = r.nextLink(header.Get("Link"), target); err != nil || next == "" {
+				return dataSources, err
+			}
+		default:
+			return dataSources, nil
+		}
+	}
+	return nil, fmt.Errorf("stopped after %d pages; raise pagination.max_pages", p.MaxPages)
+}
+
+// items selects the item array; a path matching one array yields its elements
+func (r *RESTProvider) items(expr string, body interface{}) []interface{} {
+	if expr == "" {
+		if list, ok := body.([]interface{}); ok {
+			return list
+		}
+		return []interface{}{body}
+	}
+	values := r.path(expr).find(body)
+	if len(values) == 1 {
+		if list, ok := values[0].([]interface{}); ok {
+			return list
+		}
+	}
+	return values
+}
+
+// nextLink returns the rel="next" URL of a Link header. Links to another
+// host or scheme are refused so credentials never leave the configured
+// endpoint or drop from https to http.
+func (r *RESTProvider) nextLink(header, current string) (string, error) {
+	for _, part := range strings.Split(header, ",") {
+		segments := strings.Split(part, ";")
+		if len(segments) < 2 {
+			continue
+		}
+		isNext := false
+		for _, param := range segments[1:] {
+			if strings.ReplaceAll(strings.TrimSpace(param), `"`, "") == "rel=next" {
+				isNext = true
+			}
+		}
+		if !isNext {
+			continue
+		}
+
+		base, err := url.Parse(current)
+		if err != nil {
+			return "", err
+		}
+		link, err := url.Parse(strings.Trim(strings.TrimSpace(segments[0]), "<>"))
+		if err != nil {
+			return "", fmt.Errorf("invalid next link: %w", err)
+		}
+		resolved := base.ResolveReference(link)
+		if resolved.Host != base.Host || resolved.Scheme != base.Scheme {
+			return "", fmt.Errorf("next link %s leaves the endpoint %s://%s", r.redactURL(resolved.String()), base.Scheme, base.Host)
+		}
+		return resolved.String(), nil
+	}
+	return "", nil
+}
+
+func withQueryParam(target, name, value string) string {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return target
+	}
+	query := parsed.Query()
+	query.Set(name, value)
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
+
+// requestEscape is how renderRequest escapes the values it substitutes
+type requestEscape int
+
+const (
+	escapeNone requestEscape = iota // headers
+	escapeURL                       // paths and query strings
+	escapeJSON                      // JSON bodies, inside string literals
+)
+
+// renderRequest fills a request template
+func (r *RESTProvider) renderRequest(template string, vars map[string]string, escape requestEscape) string {
+	return renderTemplate(template, func(name string, inQuery bool) string {
+		value, ok := vars[name]
+		if !ok {
+			value = r.config.Options[strings.TrimPrefix(name, "options.")]
+		}
+		switch escape {
+		case escapeURL:
+			if inQuery {
+				return url.QueryEscape(value)
+			}
+			return url.PathEscape(value)
+		case escapeJSON:
+			quoted, _ := json.Marshal(value)
+			return string(quoted[1 : len(quoted)-1])
+		}
+		return value
+	})
+}
+
+func (r *RESTProvider) path(expr string) jsonPath {
+	return r.paths[expr]
+}
+
+// eval evaluates an expression against an item
+func (r *RESTProvider) eval(expr RESTExpr, item interface{}) interface{} {
+	value := r.evalValue(expr.Value, item)
+	if len(expr.Map) > 0 {
+		if mapped, ok := expr.Map[jsonString(value)]; ok {
+			return mapped
+		}
+		if expr.Default != "" {
+			return expr.Default
+		}
+		return nil
+	}
+	if jsonString(value) == "" && expr.Default != "" {
+		return expr.Default
+	}
+	return value
+}
+
+func (r *RESTProvider) evalValue(expr string, item interface{}) interface{} {
+	negate := strings.HasPrefix(expr, "!")
+	expr = strings.TrimPrefix(expr, "!")
+
+	var value interface{} = expr
+	switch {
+	case strings.HasPrefix(expr, "$") && !strings.Contains(expr, "{{"):
+		value, _ = r.path(expr).first(item)
+	case strings.Contains(expr, "{{"):
+		value = renderTemplate(expr, func(name string, _ bool) string {
+			if strings.HasPrefix(name, "$") {
+				found, _ := r.path(name).first(item)
+				return jsonString(found)
+			}
+			return r.config.Options[strings.TrimPrefix(name, "options.")]
+		})
+	}
+
+	if negate {
+		b, ok := restBool(value)
+		if !ok {
+			return nil
+		}
+		return !b
+	}
+	return value
+}
+
+// convertItem maps one response item to a DataSource
+func (r *RESTProvider) convertItem(res RESTResource, item interface{}) (types.DataSource, error) {
+	field := func(name string) string {
+		expr, ok := res.Fields[name]
+		if !ok {
+			return ""
+		}
+		return jsonString(r.eval(expr, item))
+	}
+
+	ds := types.DataSource{
+		ID:          field("id"),
+		Name:        field("name"),
+		Title:       field("title"),
+		Type:        res.Type,
+		Pattern:     field("pattern"),
+		Description: field("description"),
+		Status:      field("status"),
+		Tags:        []string{r.definition.Vendor},
+	}
+	if ds.ID == "" {
+		return ds, fmt.Errorf("fields.id evaluated to an empty value")
+	}
+	if ds.Name == "" {
+		ds.Name = ds.ID
+	}
+	if ds.Title == "" {
+		ds.Title = ds.Name
+	}
+	if ds.Status == "" {
+		ds.Status = "active"
+	}
+	if created, ok := restTime(field("created_at")); ok {
+		ds.CreatedAt = timePtr(created)
+	}
+	if updated, ok := restTime(field("updated_at")); ok {
+		ds.UpdatedAt = timePtr(updated)
+	}
+
+	for _, expr := range res.Tags {
+		if tag := jsonString(r.eval(expr, item)); tag != "" && !containsString(ds.Tags, tag) {
+			ds.Tags = append(ds.Tags, tag)
+		}
+	}
+
+	if len(res.Metadata) > 0 {
+		ds.Metadata = make(map[string]interface{}, len(res.Metadata))
+		for key, expr := range res.Metadata {
+			if value := r.eval(expr, item); value != nil {
+				ds.Metadata[key] = value
+			}
+		}
+	}
+
+	for _, relation := range res.Relations {
+		if target := jsonString(r.eval(relation.Target, item)); target != "" {
+			ds.Relations = append(ds.Relations, types.Relation{Type: relation.Type, TargetID: target})
+		}
+	}
+
+	ds.Normalized = r.normalize(res.Normalized, item)
+	return ds, nil
+}
+
+// normalize evaluates the normalized expressions, converting units
+func (r *RESTProvider) normalize(exprs map[string]RESTExpr, item interface{}) *types.NormalizedFields {
+	n := types.NewNormalizedFields()
+	for key, expr := range exprs {
+		value := r.eval(expr, item)
+		text := jsonString(value)
+		switch key {
+		case "enabled":
+			if b, ok := restBool(value); ok {
+				n.Enabled = boolPtr(b)
+			}
+		case "event_count":
+			if count, ok := parseInt64(text); ok {
+				n.EventCount = int64Ptr(count)
+			}
+		case "size_bytes":
+			if size, ok := parseInt64(text); ok {
+				n.SizeBytes = int64Ptr(size)
+			}
+		case "size_mb":
+			if mb, ok := parseFloat64(text); ok {
+				n.SizeBytes = int64Ptr(megabytesToBytes(mb))
+			}
+		case "retention_days":
+			if days, ok := parseInt64(text); ok && days > 0 {
+				n.RetentionDays = intPtr(int(days))
+			}
+		case "retention_seconds":
+			if seconds, ok := parseInt64(text); ok && seconds > 0 {
+				n.RetentionDays = intPtr(secondsToDays(seconds))
+			}
+		case "first_event_time":
+			if t, ok := restTime(text); ok {
+				n.FirstEventTime = timePtr(t)
+			}
+		case "last_event_time":
+			if t, ok := restTime(text); ok {
+				n.LastEventTime = timePtr(t)
+			}
+		case "ingest_rate_eps":
+			if eps, ok := parseFloat64(text); ok {
+				n.IngestRateEPS = float64Ptr(eps)
+			}
+		case "vendor":
+			n.Vendor = text
+		case "product":
+			n.Product = text
+		}
+	}
+	return n
+}
+
+// restBool reads JSON booleans and their common string and numeric forms
+func restBool(value interface{}) (bool, bool) {
+	if b, ok := value.(bool); ok {
+		return b, true
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(jsonString(value)))
+	return b, err == nil
+}
+
+// restTime parses RFC 3339 timestamps and epoch seconds or milliseconds;
+// zero means unset
+func restTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0" {
+		return time.Time{}, false
+	}
+	if epoch, ok := parseFloat64(value); ok {
+		if epoch <= 0 {
+			return time.Time{}, false
+		}
+		if epoch > 1e11 {
+			return unixMillis(int64(epoch)), true
+		}
+		return time.Unix(int64(epoch), 0).UTC(), true
+	}
+	if t, ok := parseTimeLayouts(value, time.RFC3339Nano, "2006-01-02T15:04:05.000-07:00", "2006-01-02 15:04:05"); ok {
+		return t.UTC(), true
+	}
+	return time.Time{}, false
+}
+
+// do performs one request and decodes the JSON response
+func (r *RESTProvider) do(ctx context.Context, method, target, body string, headers map[string]string, vars map[string]string) (interface{}, http.Header, error) {
+	var payload []byte
+	var reader io.Reader
+	if body != "" {
+		payload = []byte(body)
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for _, set := range []map[string]string{r.definition.Headers, headers} {
+		for name, value := range set {
+			req.Header.Set(name, r.renderRequest(value, vars, escapeNone))
+		}
+	}
+	if err := r.addAuth(ctx, req, payload); err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		// *url.Error prints the request URL, which may carry the credential
+		if urlErr, ok := err.(*url.Error); ok {
+			urlErr.URL = r.redactURL(urlErr.URL)
+		}
+		return nil, nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var out interface{}
+	if err := readJSONResponse(resp, r.definition.Vendor, &out); err != nil {
+		return nil, nil, err
+	}
+	return out, resp.Header, nil
+}
+
+// redactURL hides userinfo and the auth.query_param credential in a URL
+// shown in errors
+func (r *RESTProvider) redactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return "(invalid URL)"
+	}
+	if name := r.definition.Auth.QueryParam; name != "" {
+		query := parsed.Query()
+		if query.Has(name) {
+			query.Set(name, "REDACTED")
+			parsed.RawQuery = query.Encode()
+		}
+	}
+	return parsed.Redacted()
+}
+
+// addAuth applies the shared auth config according to the definition's
+// placement
+func (r *RESTProvider) addAuth(ctx context.Context, req *http.Request, payload []byte) error {
+	auth := r.config.Auth
+	if auth == nil {
+		return nil
+	}
+	placement := r.definition.Auth
+
+	var credential, prefix string
+	switch auth.Type {
+	case "basic":
+		req.SetBasicAuth(auth.Username, auth.Password)
+		return nil
+	case "aws_sigv4":
+		credentials, err := r.awsCredentials(ctx)
+		if err != nil {
+			return err
+		}
+		signer := &sigV4Signer{credentials: credentials, region: auth.Region, service: placement.Service, now: time.Now}
+		signer.sign(req, payload)
+		return nil
+	case "bearer":
+		credential, prefix = auth.Token, "Bearer "
+	case "client_credentials":
+		token, err := r.accessToken(ctx)
+		if err != nil {
+			return err
+		}
+		credential, prefix = token, "Bearer "
+	case "api_key":
+		credential = auth.APIKey
+	}
+	if placement.Prefix != "" {
+		prefix = placement.Prefix
+	}
+
+	if placement.QueryParam != "" {
+		query := req.URL.Query()
+		query.Set(placement.QueryParam, credential)
+		req.URL.RawQuery = query.Encode()
+		return nil
+	}
+	header := placement.Header
+	if header == "" {
+		header = "Authorization"
+	}
+	req.Header.Set(header, prefix+credential)
+	return nil
+}
+
+// accessToken returns a cached client-credentials token
+func (r *RESTProvider) accessToken(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if r.token.valid(now) {
+		return r.token.value, nil
+	}
+
+	auth := r.config.Auth
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", auth.ClientID)
+	form.Set("client_secret", auth.ClientSecret)
+	if len(auth.Scopes) > 0 {
+		form.Set("scope", strings.Join(auth.Scopes, " "))
+	}
+
+	resp, err := requestToken(ctx, r.client, auth.TokenURL, form, r.definition.Vendor)
+	if err != nil {
+		return "", err
+	}
+	r.token.set(resp, now)
+	return r.token.value, nil
+}
+
+// awsCredentials resolves the AWS credential chain once per provider, and
+// again when assumed-role credentials are about to expire
+func (r *RESTProvider) awsCredentials(ctx context.Context) (awsCredentials, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.aws == nil || !r.aws.valid(time.Now()) {
+		credentials, err := resolveAWSCredentials(ctx, r.client, r.config.Auth, r.config.Options)
+		if err != nil {
+			return awsCredentials{}, fmt.Errorf("failed to resolve AWS credentials: %w", err)
+		}
+		r.aws = &credentials
+	}
+	return *r.aws, nil
+}
+
+func (r *RESTProvider) ValidateConnection(ctx context.Context) error {
+	vars := map[string]string{"offset": "0", "limit": "1", "last": "0", "page": "1", "cursor": ""}
+	method, path, body, headers := "GET", r.definition.Validate, "", map[string]string(nil)
+	if path == "" {
+		res := r.definition.Resources[0]
+		method, path, body, headers = res.Method, res.Path, res.Body, res.Headers
+	}
+	target := r.baseURL + r.renderRequest(path, vars, escapeURL)
+	if _, _, err := r.do(ctx, method, target, r.renderRequest(body, vars, escapeJSON), headers, vars); err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", r.definition.Vendor, err)
+	}
+	return nil
+}
+
+func (r *RESTProvider) GetCapabilities() types.ProviderCapabilities {
+	dataTypes := make([]string, 0, len(r.definition.Resources))
+	for _, res := range r.definition.Resources {
+		if !containsString(dataTypes, res.Type) {
+			dataTypes = append(dataTypes, res.Type)
+		}
+	}
+	return types.ProviderCapabilities{
+		SupportsRealTimeQueries: false,
+		SupportsHistoricalData:  false,
+		SupportedDataTypes:      dataTypes,
+		RequiresAuthentication:  r.config.Auth != nil,
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
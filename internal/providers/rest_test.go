@@ -0,0 +1,369 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+// TestRESTExamplesMatchBuiltins runs the shipped Splunk and QRadar
+// definitions and the built-in providers against the same responses
+func TestRESTExamplesMatchBuiltins(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/services/data/indexes":
+			if auth := r.Header.Get("Authorization"); auth != "Splunk splunk-key" {
+				http.Error(w, "unauthorized "+auth, http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"paging":{"total":2},"entry":[
+				{"name":"main","content":{"currentDBSizeMB":"512","totalEventCount":"1000","isInternal":"0","frozenTimePeriodInSecs":"7776000",
+				 "minTime":"2024-04-01T00:00:00.000+00:00","maxTime":"2024-05-01T10:00:00.000+00:00","disabled":false,"homePath":"$SPLUNK_DB/main/db"}},
+				{"name":"_internal","content":{"currentDBSizeMB":"64","totalEventCount":"50","isInternal":"1","frozenTimePeriodInSecs":"2592000","minTime":"0","maxTime":"1714557600","disabled":true}}]}`))
+		case "/api/config/event_sources/log_source_management/log_sources":
+			if r.Header.Get("SEC") != "qradar-key" || r.Header.Get("Version") != "15.0" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			if r.Header.Get("Range") != "" && r.Header.Get("Range") != "items=0-499" {
+				w.Write([]byte(`[]`))
+				return
+			}
+			w.Write([]byte(`[
+				{"id":42,"name":"Firewall","description":"edge","type_id":4,"enabled":true,"gateway":false,"internal":false,"auto_discovered":true,
+				 "average_eps":12,"creation_date":1700000000000,"modified_date":1710000000000,"last_event_time":1714557600000,"status":{"last_seen":1714557600000}},
+				{"id":7,"name":"Health","type_id":147,"enabled":false,"gateway":true,"internal":true,"average_eps":0}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cases := []struct {
+		name       string
+		builtin    func(types.ProviderConfig) (types.Provider, error)
+		auth       *types.AuthConfig
+		definition string
+	}{
+		{"splunk", NewSplunkProvider, &types.AuthConfig{Type: "api_key", APIKey: "splunk-key"}, "../../examples/rest/splunk.yml"},
+		{"qradar", NewQRadarProvider, &types.AuthConfig{Type: "api_key", APIKey: "qradar-key"}, "../../examples/rest/qradar.yml"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := types.ProviderConfig{Type: tc.name, Endpoint: server.URL, Auth: tc.auth, Timeout: 5 * time.Second}
+			builtin, err := tc.builtin(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := builtin.FetchDataViews(context.Background())
+			if err != nil {
+				t.Fatalf("built-in FetchDataViews: %v", err)
+			}
+
+			config.Type = "rest"
+			config.Options = map[string]string{"definition": tc.definition}
+			declarative, err := NewRESTProvider(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := declarative.FetchDataViews(context.Background())
+			if err != nil {
+				t.Fatalf("rest FetchDataViews: %v", err)
+			}
+
+			if len(got) != len(want) {
+				t.Fatalf("expected %d data sources, got %d", len(want), len(got))
+			}
+			for i := range want {
+				g, w := got[i], want[i]
+				if g.ID != w.ID || g.Name != w.Name || g.Type != w.Type || g.Pattern != w.Pattern || g.Status != w.Status || g.Description != w.Description {
+					t.Errorf("data source %d differs:\n got  %+v\n want %+v", i, g, w)
+				}
+				if !reflect.DeepEqual(g.Tags[1:], w.Tags[1:]) && !reflect.DeepEqual(g.Tags[1:], w.Tags) {
+					t.Errorf("%s tags differ: got %v, want %v", w.ID, g.Tags, w.Tags)
+				}
+				if !reflect.DeepEqual(g.Normalized, w.Normalized) {
+					t.Errorf("%s normalized fields differ:\n got  %+v\n want %+v", w.ID, *g.Normalized, *w.Normalized)
+				}
+				for key := range g.Metadata {
+					if _, ok := w.Metadata[key]; !ok {
+						t.Errorf("%s has metadata key %s the built-in lacks", w.ID, key)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRESTFlatOptionsPagination(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("token") != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/feeds":
+			// cursor paging
+			switch r.URL.Query().Get("after") {
+			case "":
+				w.Write([]byte(`{"data":{"feeds":[{"key":"a","label":"Feed A","on":"yes","bytes":10}]},"next":"c1"}`))
+			case "c1":
+				w.Write([]byte(`{"data":{"feeds":[{"key":"b","on":"no","bytes":20}]},"next":null}`))
+			default:
+				http.Error(w, "bad cursor", http.StatusBadRequest)
+			}
+		case "/v1/streams":
+			// link-header paging
+			if r.URL.Query().Get("page") == "2" {
+				w.Write([]byte(`[{"id":"s2"}]`))
+				return
+			}
+			w.Header().Set("Link", `</v1/streams?page=2&token=secret>; rel="next", </v1/streams?page=9>; rel="last"`)
+			w.Write([]byte(`[{"id":"s1"}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewRESTProvider(types.ProviderConfig{
+		Type:     "rest",
+		Endpoint: server.URL,
+		Auth:     &types.AuthConfig{Type: "api_key", APIKey: "secret"},
+		Options: map[string]string{
+			"vendor":                "acme",
+			"name":                  "feed",
+			"path":                  "/v1/{{options.collection}}",
+			"collection":            "feeds",
+			"items":                 "$.data.feeds[*]",
+			"pagination":            "cursor",
+			"cursor_path":           "$.next",
+			"cursor_param":          "after",
+			"auth.query_param":      "token",
+			"field.id":              "feed:{{$.key}}",
+			"field.name":            "$.label",
+			"field.status":          "$.on",
+			"status_map":            "yes=active,no=disabled",
+			"normalized.size_bytes": "$.bytes",
+			"metadata.raw":          "$.on",
+			"tags":                  "feed",
+		},
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, err := provider.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+	if len(sources) != 2 {
+		t.Fatalf("expected 2 feeds across two cursor pages, got %d", len(sources))
+	}
+	a, b := sources[0], sources[1]
+	if a.ID != "feed:a" || a.Name != "Feed A" || a.Type != "acme-feed" || a.Status != "active" || *a.Normalized.SizeBytes != 10 {
+		t.Errorf("unexpected feed a %+v", a)
+	}
+	if b.Name != "feed:b" || b.Status != "disabled" || !reflect.DeepEqual(b.Tags, []string{"acme", "feed"}) || b.Metadata["raw"] != "no" {
+		t.Errorf("unexpected feed b %+v", b)
+	}
+
+	linked, err := NewRESTProvider(types.ProviderConfig{
+		Type:     "rest",
+		Endpoint: server.URL,
+		Auth:     &types.AuthConfig{Type: "api_key", APIKey: "secret"},
+		Options: map[string]string{
+			"path":             "/v1/streams",
+			"pagination":       "link",
+			"auth.query_param": "token",
+			"field.id":         "$.id",
+		},
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, err = linked.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+	if len(sources) != 2 || sources[1].ID != "s2" {
+		t.Errorf("expected the Link header to be followed, got %+v", sources)
+	}
+}
+
+func TestRESTBodyEscaping(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			http.Error(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`[{"id":"a"}]`))
+	}))
+	defer server.Close()
+
+	provider, err := NewRESTProvider(types.ProviderConfig{
+		Type:     "rest",
+		Endpoint: server.URL,
+		Options: map[string]string{
+			"method":   "POST",
+			"path":     "/search",
+			"body":     `{"query":"{{options.query}}","limit":{{limit}}}`,
+			"query":    `host="dc\01"`,
+			"field.id": "$.id",
+		},
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.FetchDataViews(context.Background()); err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+	if received["query"] != `host="dc\01"` {
+		t.Errorf("option value altered in the body: %q", received["query"])
+	}
+}
+
+func TestRESTAssumedRoleRefresh(t *testing.T) {
+	var expiration string
+	assumed := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sts/" {
+			assumed++
+			w.Write([]byte(`<AssumeRoleResponse><AssumeRoleResult><Credentials>
+				<AccessKeyId>ASIAROLE</AccessKeyId><SecretAccessKey>role-secret</SecretAccessKey><SessionToken>role-session</SessionToken>
+				<Expiration>` + expiration + `</Expiration></Credentials></AssumeRoleResult></AssumeRoleResponse>`))
+			return
+		}
+		if !strings.Contains(r.Header.Get("Authorization"), "Credential=ASIAROLE/") {
+			http.Error(w, "unsigned", http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`[{"id":"a"}]`))
+	}))
+	defer server.Close()
+
+	for lifetime, want := range map[time.Duration]int{time.Hour: 1, 30 * time.Second: 2} {
+		expiration, assumed = time.Now().Add(lifetime).UTC().Format(time.RFC3339), 0
+		provider, err := NewRESTProvider(types.ProviderConfig{
+			Type:     "rest",
+			Endpoint: server.URL,
+			Auth:     &types.AuthConfig{Type: "aws_sigv4", AccessKeyID: "AKIDBASE", SecretAccessKey: "base-secret", Region: "us-east-1"},
+			Options: map[string]string{
+				"path":         "/items",
+				"auth.service": "execute-api",
+				"role_arn":     "arn:aws:iam::123456789012:role/inventory",
+				"sts_endpoint": server.URL + "/sts",
+				"field.id":     "$.id",
+			},
+			Timeout: 5 * time.Second,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2; i++ {
+			if _, err := provider.FetchDataViews(context.Background()); err != nil {
+				t.Fatalf("FetchDataViews: %v", err)
+			}
+		}
+		if assumed != want {
+			t.Errorf("credentials lasting %s: expected %d AssumeRole calls, got %d", lifetime, want, assumed)
+		}
+	}
+}
+
+func TestRESTQueryCredentialProtection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Same host, different scheme
+		w.Header().Set("Link", "<https://"+r.Host+"/v1/streams?page=2>; rel=\"next\"")
+		w.Write([]byte(`[{"id":"s1"}]`))
+	}))
+	defer server.Close()
+
+	config := types.ProviderConfig{
+		Type:     "rest",
+		Endpoint: server.URL,
+		Auth:     &types.AuthConfig{Type: "api_key", APIKey: "secret-key"},
+		Options: map[string]string{
+			"path":             "/v1/streams",
+			"pagination":       "link",
+			"auth.query_param": "token",
+			"field.id":         "$.id",
+		},
+		Timeout: 5 * time.Second,
+	}
+	provider, err := NewRESTProvider(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.FetchDataViews(context.Background()); err == nil || !strings.Contains(err.Error(), "leaves the endpoint") {
+		t.Errorf("expected a scheme change in the next link to be refused, got %v", err)
+	}
+
+	// Transport errors print the URL; the credential must not appear in it
+	server.Close()
+	provider, err = NewRESTProvider(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = provider.FetchDataViews(context.Background())
+	if err == nil {
+		t.Fatal("expected a connection error")
+	}
+	if strings.Contains(err.Error(), "secret-key") || !strings.Contains(err.Error(), "token=REDACTED") {
+		t.Errorf("credential not redacted: %v", err)
+	}
+}
+
+func TestRESTDefinitionErrors(t *testing.T) {
+	if _, err := parseRESTDefinition([]byte("vendor: acme\nresources:\n  - path: /x\n    itmes: $.data\n")); err == nil || !strings.Contains(err.Error(), "line 4") {
+		t.Errorf("expected a line-numbered error for an unknown key, got %v", err)
+	}
+
+	cases := map[string]map[string]string{
+		"missing id":          {"path": "/x"},
+		"unknown placeholder": {"path": "/x?from={{start}}", "field.id": "$.id"},
+		"unknown normalized":  {"path": "/x", "field.id": "$.id", "normalized.bytes": "$.b"},
+		"bad pagination":      {"path": "/x", "field.id": "$.id", "pagination": "scroll"},
+		"bad path":            {"path": "/x", "field.id": "$.items[x]"},
+	}
+	for name, options := range cases {
+		if _, err := NewRESTProvider(types.ProviderConfig{Type: "rest", Endpoint: "https://api.example.com", Options: options}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	doc := map[string]interface{}{
+		"entry": []interface{}{
+			map[string]interface{}{"name": "a", "content": map[string]interface{}{"dotted.key": 1.0}},
+			map[string]interface{}{"name": "b"},
+		},
+	}
+	cases := map[string][]interface{}{
+		"$.entry[0].name":                  {"a"},
+		"entry[-1].name":                   {"b"},
+		"$.entry[*].name":                  {"a", "b"},
+		"$.entry[0].content['dotted.key']": {1.0},
+		"$.missing.name":                   nil,
+	}
+	for expr, want := range cases {
+		path, err := compileJSONPath(expr)
+		if err != nil {
+			t.Fatalf("%s: %v", expr, err)
+		}
+		if got := path.find(doc); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", expr, got, want)
+		}
+	}
+}
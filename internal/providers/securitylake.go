@@ -0,0 +1,279 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+// SecurityLakeProvider implements the Provider interface for Amazon Security
+// Lake. It discovers custom OCSF sources by listing the lake bucket's
+// ext/<source>/region=/accountId=/eventDay= layout with ListObjectsV2, which
+// works against any S3-compatible store such as MinIO.
+type SecurityLakeProvider struct {
+	config     types.ProviderConfig
+	client     *http.Client
+	bucket     string
+	prefix     string
+	region     string
+	staleAfter time.Duration
+	now        func() time.Time
+}
+
+// S3ListObjectsResponse represents the ListObjectsV2 XML response
+type S3ListObjectsResponse struct {
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key          string `xml:"Key"`
+		LastModified string `xml:"LastModified"`
+		Size         int64  `xml:"Size"`
+	} `xml:"Contents"`
+}
+
+// securityLakeSource accumulates the partitions and objects of one source
+type securityLakeSource struct {
+	name          string
+	regions       map[string]bool
+	accounts      map[string]bool
+	latestDay     string
+	objects       int64
+	bytes         int64
+	lastWriteTime time.Time
+}
+
+const (
+	securityLakeDefaultPrefix = "ext/"
+	securityLakeDefaultStale  = 48 * time.Hour
+	securityLakeDayLayout     = "20060102"
+)
+
+// NewSecurityLakeProvider creates a new Security Lake provider. options.bucket
+// names the lake bucket. Without an endpoint the regional S3 endpoint is used;
+// a configured AWS endpoint, such as s3-fips, is addressed virtual-hosted style
+// and any other path-style, as MinIO expects.
+func NewSecurityLakeProvider(config types.ProviderConfig) (types.Provider, error) {
+	client, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &SecurityLakeProvider{
+		config:     config,
+		client:     client,
+		bucket:     config.Options["bucket"],
+		prefix:     config.Options["prefix"],
+		staleAfter: securityLakeDefaultStale,
+		now:        time.Now,
+	}
+	if provider.bucket == "" {
+		return nil, fmt.Errorf("securitylake requires options.bucket")
+	}
+	if provider.prefix == "" {
+		provider.prefix = securityLakeDefaultPrefix
+	}
+	if !strings.HasSuffix(provider.prefix, "/") {
+		provider.prefix += "/"
+	}
+	if config.Auth != nil {
+		provider.region = config.Auth.Region
+	}
+	if provider.region == "" {
+		return nil, fmt.Errorf("securitylake requires auth.region")
+	}
+
+	if stale := config.Options["stale_after"]; stale != "" {
+		provider.staleAfter, err = time.ParseDuration(stale)
+		if err != nil || provider.staleAfter <= 0 {
+			return nil, fmt.Errorf("invalid stale_after: %s", stale)
+		}
+	}
+
+	return provider, nil
+}
+
+func (s *SecurityLakeProvider) Name() string {
+	return "securitylake"
+}
+
+func (s *SecurityLakeProvider) FetchDataViews(ctx context.Context) ([]types.DataSource, error) {
+	credentials, err := resolveAWSCredentials(ctx, s.client, s.config.Auth, s.config.Options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve AWS credentials: %w", err)
+	}
+	signer := s.signer(credentials)
+
+	sources := make(map[string]*securityLakeSource)
+	for token := ""; ; {
+		var page S3ListObjectsResponse
+		if err := s.listObjects(ctx, signer, token, 1000, &page); err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+		for _, object := range page.Contents {
+			s.addObject(sources, object.Key, object.Size, object.LastModified)
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			break
+		}
+		token = page.NextContinuationToken
+	}
+
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	dataSources := make([]types.DataSource, 0, len(names))
+	for _, name := range names {
+		dataSources = append(dataSources, s.convertSource(sources[name]))
+	}
+	return dataSources, nil
+}
+
+// addObject attributes an object to its source using the Hive-style
+// partition segments in its key; objects outside the layout are ignored
+func (s *SecurityLakeProvider) addObject(sources map[string]*securityLakeSource, key string, size int64, lastModified string) {
+	segments := strings.Split(strings.TrimPrefix(key, s.prefix), "/")
+	if len(segments) < 2 || segments[0] == "" {
+		return
+	}
+
+	partitions := make(map[string]string)
+	for _, segment := range segments[1:] {
+		if name, value, ok := strings.Cut(segment, "="); ok {
+			partitions[name] = value
+		}
+	}
+	if partitions["eventDay"] == "" {
+		return
+	}
+
+	source, ok := sources[segments[0]]
+	if !ok {
+		source = &securityLakeSource{name: segments[0], regions: map[string]bool{}, accounts: map[string]bool{}}
+		sources[segments[0]] = source
+	}
+	if region := partitions["region"]; region != "" {
+		source.regions[region] = true
+	}
+	if account := partitions["accountId"]; account != "" {
+		source.accounts[account] = true
+	}
+	if day := partitions["eventDay"]; day > source.latestDay {
+		source.latestDay = day
+	}
+	source.objects++
+	source.bytes += size
+	if written, err := time.Parse(time.RFC3339, lastModified); err == nil && written.After(source.lastWriteTime) {
+		source.lastWriteTime = written
+	}
+}
+
+func (s *SecurityLakeProvider) convertSource(source *securityLakeSource) types.DataSource {
+	status := "active"
+	metadata := map[string]interface{}{
+		"bucket":      s.bucket,
+		"prefix":      s.prefix + source.name + "/",
+		"regions":     sortedKeys(source.regions),
+		"accounts":    sortedKeys(source.accounts),
+		"objectCount": source.objects,
+		"sizeBytes":   source.bytes,
+	}
+	if day, err := time.Parse(securityLakeDayLayout, source.latestDay); err == nil {
+		metadata["latestEventDay"] = day.Format("2006-01-02")
+		// A partition covers a whole day, so staleness counts from its end
+		if s.now().Sub(day.Add(24*time.Hour)) > s.staleAfter {
+			status = "degraded"
+		}
+	}
+
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(true)
+	n.SizeBytes = int64Ptr(source.bytes)
+	if !source.lastWriteTime.IsZero() {
+		n.LastEventTime = timePtr(source.lastWriteTime)
+	}
+
+	return types.DataSource{
+		ID:         "source:" + source.name,
+		Name:       source.name,
+		Title:      source.name,
+		Type:       "securitylake-source",
+		Pattern:    "s3://" + s.bucket + "/" + s.prefix + source.name + "/",
+		Status:     status,
+		Tags:       []string{"securitylake", "ocsf", "source"},
+		Normalized: n,
+		Metadata:   metadata,
+	}
+}
+
+// listObjects performs one signed ListObjectsV2 request under the prefix
+func (s *SecurityLakeProvider) listObjects(ctx context.Context, signer *sigV4Signer, token string, maxKeys int, out *S3ListObjectsResponse) error {
+	query := url.Values{}
+	query.Set("list-type", "2")
+	query.Set("prefix", s.prefix)
+	query.Set("max-keys", fmt.Sprint(maxKeys))
+	if token != "" {
+		query.Set("continuation-token", token)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", s.bucketURL()+"?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	signer.sign(req, nil)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return readXMLResponse(resp, "securitylake", out)
+}
+
+// bucketURL addresses the bucket virtual-hosted style on AWS and path-style
+// on any other endpoint
+func (s *SecurityLakeProvider) bucketURL() string {
+	endpoint := strings.TrimSuffix(s.config.Endpoint, "/")
+	if endpoint == "" {
+		return "https://" + s.bucket + ".s3." + s.region + "." + awsDNSSuffix(s.region) + "/"
+	}
+	parsed, err := url.Parse(endpoint)
+	if err == nil && isAWSHost(parsed.Hostname()) {
+		return parsed.Scheme + "://" + s.bucket + "." + parsed.Host + "/"
+	}
+	return endpoint + "/" + url.PathEscape(s.bucket)
+}
+
+func (s *SecurityLakeProvider) signer(credentials awsCredentials) *sigV4Signer {
+	return &sigV4Signer{credentials: credentials, region: s.region, service: "s3", now: s.now}
+}
+
+func (s *SecurityLakeProvider) ValidateConnection(ctx context.Context) error {
+	credentials, err := resolveAWSCredentials(ctx, s.client, s.config.Auth, s.config.Options)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Security Lake: %w", err)
+	}
+	var page S3ListObjectsResponse
+	if err := s.listObjects(ctx, s.signer(credentials), "", 1, &page); err != nil {
+		return fmt.Errorf("failed to connect to Security Lake: %w", err)
+	}
+	return nil
+}
+
+func (s *SecurityLakeProvider) GetCapabilities() types.ProviderCapabilities {
+	return types.ProviderCapabilities{
+		SupportsRealTimeQueries: false,
+		SupportsHistoricalData:  true,
+		SupportedDataTypes:      []string{"securitylake-source"},
+		RequiresAuthentication:  true,
+	}
+}
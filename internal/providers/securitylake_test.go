@@ -0,0 +1,136 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+func TestSecurityLakeFetchDataViews(t *testing.T) {
+	now := time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC)
+	verifier := &sigV4Signer{
+		credentials: awsCredentials{AccessKeyID: "minio", SecretAccessKey: "minio-secret"},
+		region:      "us-east-1",
+		service:     "s3",
+		now:         func() time.Time { return now },
+	}
+
+	pages := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Re-sign the received request and compare, as MinIO would
+		signed := r.Header.Get("Authorization")
+		verifier.sign(r, nil)
+		if signed == "" || signed != r.Header.Get("Authorization") {
+			http.Error(w, "<Error><Code>SignatureDoesNotMatch</Code></Error>", http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/aws-security-data-lake" || r.URL.Query().Get("list-type") != "2" || r.URL.Query().Get("prefix") != "ext/" {
+			http.Error(w, "<Error><Code>InvalidRequest</Code></Error>", http.StatusBadRequest)
+			return
+		}
+
+		pages++
+		if r.URL.Query().Get("continuation-token") == "" {
+			w.Write([]byte(`<ListBucketResult><IsTruncated>true</IsTruncated><NextContinuationToken>page/2</NextContinuationToken>
+				<Contents><Key>ext/okta/region=us-east-1/accountId=111111111111/eventDay=20240502/a.parquet</Key><LastModified>2024-05-02T23:10:00.000Z</LastModified><Size>1000</Size></Contents>
+				<Contents><Key>ext/okta/region=eu-west-1/accountId=222222222222/eventDay=20240503/b.parquet</Key><LastModified>2024-05-03T01:00:00.000Z</LastModified><Size>500</Size></Contents>
+				<Contents><Key>ext/okta/README</Key><LastModified>2024-01-01T00:00:00.000Z</LastModified><Size>10</Size></Contents>
+				</ListBucketResult>`))
+			return
+		}
+		if r.URL.Query().Get("continuation-token") != "page/2" {
+			http.Error(w, "<Error><Code>InvalidArgument</Code></Error>", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`<ListBucketResult><IsTruncated>false</IsTruncated>
+			<Contents><Key>ext/okta/region=us-east-1/accountId=111111111111/eventDay=20240501/c.parquet</Key><LastModified>2024-05-01T10:00:00.000Z</LastModified><Size>250</Size></Contents>
+			<Contents><Key>ext/crowdstrike/region=us-east-1/accountId=111111111111/eventDay=20240420/d.parquet</Key><LastModified>2024-04-20T10:00:00.000Z</LastModified><Size>42</Size></Contents>
+			</ListBucketResult>`))
+	}))
+	defer server.Close()
+
+	provider, err := NewSecurityLakeProvider(types.ProviderConfig{
+		Type:     "securitylake",
+		Endpoint: server.URL,
+		Auth: &types.AuthConfig{
+			Type:            "aws_sigv4",
+			AccessKeyID:     "minio",
+			SecretAccessKey: "minio-secret",
+			Region:          "us-east-1",
+		},
+		Options: map[string]string{"bucket": "aws-security-data-lake"},
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider.(*SecurityLakeProvider).now = func() time.Time { return now }
+
+	sources, err := provider.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+	if pages != 2 {
+		t.Errorf("expected 2 list pages, got %d", pages)
+	}
+	if len(sources) != 2 {
+		t.Fatalf("expected 2 sources, got %d", len(sources))
+	}
+
+	crowdstrike, okta := sources[0], sources[1]
+	if okta.ID != "source:okta" || okta.Status != "active" || okta.Pattern != "s3://aws-security-data-lake/ext/okta/" {
+		t.Errorf("unexpected okta source %+v", okta)
+	}
+	if !reflect.DeepEqual(okta.Metadata["regions"], []string{"eu-west-1", "us-east-1"}) ||
+		!reflect.DeepEqual(okta.Metadata["accounts"], []string{"111111111111", "222222222222"}) {
+		t.Errorf("unexpected okta partitions %+v", okta.Metadata)
+	}
+	if okta.Metadata["latestEventDay"] != "2024-05-03" || okta.Metadata["objectCount"] != int64(3) || *okta.Normalized.SizeBytes != 1750 {
+		t.Errorf("unexpected okta volume %+v", okta.Metadata)
+	}
+	if want := time.Date(2024, 5, 3, 1, 0, 0, 0, time.UTC); okta.Normalized.LastEventTime == nil || !okta.Normalized.LastEventTime.Equal(want) {
+		t.Errorf("unexpected last write time %v", okta.Normalized.LastEventTime)
+	}
+	if crowdstrike.Status != "degraded" {
+		t.Errorf("source without recent partitions should be degraded, got %s", crowdstrike.Status)
+	}
+}
+
+func TestSecurityLakeBucketURL(t *testing.T) {
+	provider, err := NewSecurityLakeProvider(types.ProviderConfig{
+		Type:     "securitylake",
+		Endpoint: "https://s3.eu-west-1.amazonaws.com",
+		Auth:     &types.AuthConfig{Type: "aws_sigv4", Region: "eu-west-1"},
+		Options:  map[string]string{"bucket": "lake"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := provider.(*SecurityLakeProvider).bucketURL(); got != "https://lake.s3.eu-west-1.amazonaws.com/" {
+		t.Errorf("expected virtual-hosted bucket URL, got %s", got)
+	}
+
+	for endpoint, want := range map[string]string{
+		"": "https://lake.s3.cn-north-1.amazonaws.com.cn/",
+		"https://s3.dualstack.cn-north-1.amazonaws.com.cn": "https://lake.s3.dualstack.cn-north-1.amazonaws.com.cn/",
+		"http://localhost:9000":                            "http://localhost:9000/lake",
+	} {
+		provider, err := NewSecurityLakeProvider(types.ProviderConfig{
+			Type:     "securitylake",
+			Endpoint: endpoint,
+			Auth:     &types.AuthConfig{Type: "aws_sigv4", Region: "cn-north-1"},
+			Options:  map[string]string{"bucket": "lake"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := provider.(*SecurityLakeProvider).bucketURL(); got != want {
+			t.Errorf("endpoint %q: expected %s, got %s", endpoint, want, got)
+		}
+	}
+}
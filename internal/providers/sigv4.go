@@ -0,0 +1,180 @@
+package providers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// awsCredentials are the credentials used to sign AWS requests
+type awsCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Expiry          time.Time // zero for long-term keys
+}
+
+// valid reports whether the credentials can still be used at now, renewing
+// temporary ones early like OAuth tokens
+func (c *awsCredentials) valid(now time.Time) bool {
+	return c.Expiry.IsZero() || now.Before(c.Expiry.Add(-tokenExpiryMargin))
+}
+
+// sigV4Signer signs requests with AWS Signature Version 4
+type sigV4Signer struct {
+	credentials awsCredentials
+	region      string
+	service     string
+	now         func() time.Time
+}
+
+const sigV4Algorithm = "AWS4-HMAC-SHA256"
+
+// sign adds the X-Amz-Date, session token and Authorization headers. payload
+// must be the exact request body (nil for none).
+func (s *sigV4Signer) sign(req *http.Request, payload []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := hexSHA256(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if s.credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.credentials.SessionToken)
+	}
+	if s.signsContentHash() {
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	signedHeaders, canonicalHeaders := s.canonicalHeaders(req)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL.Path, s.service != "s3"),
+		canonicalQuery(req),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, s.region, s.service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		amzDate,
+		scope,
+		hexSHA256([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.credentials.SecretAccessKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, s.service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, s.credentials.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// signsContentHash reports whether the service requires x-amz-content-sha256
+func (s *sigV4Signer) signsContentHash() bool {
+	switch s.service {
+	case "s3", "es", "aoss":
+		return true
+	}
+	return false
+}
+
+// canonicalHeaders signs host, content-type and all x-amz-* headers
+func (s *sigV4Signer) canonicalHeaders(req *http.Request) (string, string) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.Join(values, ",")
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonical strings.Builder
+	for _, name := range names {
+		canonical.WriteString(name)
+		canonical.WriteString(":")
+		canonical.WriteString(strings.Join(strings.Fields(headers[name]), " "))
+		canonical.WriteString("\n")
+	}
+	return strings.Join(names, ";"), canonical.String()
+}
+
+// canonicalURI encodes each path segment; services other than S3 expect the
+// already-encoded path to be encoded a second time
+func canonicalURI(path string, doubleEncode bool) string {
+	if path == "" {
+		return "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		encoded := awsEscape(segment)
+		if doubleEncode {
+			encoded = awsEscape(encoded)
+		}
+		segments[i] = encoded
+	}
+	return strings.Join(segments, "/")
+}
+
+func canonicalQuery(req *http.Request) string {
+	query := req.URL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, awsEscape(key)+"="+awsEscape(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// awsEscape percent-encodes everything except RFC 3986 unreserved characters
+func awsEscape(value string) string {
+	var escaped strings.Builder
+	for _, b := range []byte(value) {
+		if (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9') ||
+			b == '-' || b == '_' || b == '.' || b == '~' {
+			escaped.WriteByte(b)
+			continue
+		}
+		fmt.Fprintf(&escaped, "%%%02X", b)
+	}
+	return escaped.String()
+}
+
+func hexSHA256(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,51 @@
+package providers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSigV4Signature checks the signer against the IAM ListUsers example from
+// the AWS Signature Version 4 documentation
+func TestSigV4Signature(t *testing.T) {
+	signer := &sigV4Signer{
+		credentials: awsCredentials{
+			AccessKeyID:     "AKIDEXAMPLE",
+			SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		},
+		region:  "us-east-1",
+		service: "iam",
+		now: func() time.Time {
+			return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+		},
+	}
+
+	req, err := http.NewRequest("GET", "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+	signer.sign(req, nil)
+
+	auth := req.Header.Get("Authorization")
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, " +
+		"SignedHeaders=content-type;host;x-amz-date, " +
+		"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7"
+	if auth != want {
+		t.Fatalf("unexpected Authorization header:\n got %s\nwant %s", auth, want)
+	}
+	if req.Header.Get("X-Amz-Content-Sha256") != "" {
+		t.Fatal("iam requests should not carry x-amz-content-sha256")
+	}
+}
+
+func TestCanonicalURI(t *testing.T) {
+	if got := canonicalURI("/my index/_doc", false); got != "/my%20index/_doc" {
+		t.Fatalf("unexpected S3 canonical URI %s", got)
+	}
+	if got := canonicalURI("/my index", true); !strings.Contains(got, "%2520") {
+		t.Fatalf("expected double encoding, got %s", got)
+	}
+}
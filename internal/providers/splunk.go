@@ -36,6 +36,8 @@ type SplunkIndexResponse struct {
 			ColdPath              string `json:"coldPath"`
 			ThawedPath            string `json:"thawedPath"`
 			EnableOnlineBucketRepair string `json:"enableOnlineBucketRepair"`
+			FrozenTimePeriod      string `json:"frozenTimePeriodInSecs"`
+			Disabled              bool   `json:"disabled"`
 		} `json:"content"`
 	} `json:"entry"`
 }
@@ -68,7 +70,88 @@ func (s *SplunkProvider) Name() string {
 
 func (s *SplunkProvider) FetchDataViews(ctx context.Context) ([]types.DataSource, error) {
 	// Splunk uses indexes as data sources
-	return s.fetchIndexes(ctx)
+	dataSources, err := s.fetchIndexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sourcetype discovery runs a search, so it is opt-in
+	if s.config.Options["discover_sourcetypes"] != "true" {
+		return dataSources, nil
+	}
+
+	sourcetypes, err := s.fetchSourcetypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover sourcetypes: %w", err)
+	}
+	for i := range dataSources {
+		if indexSourcetypes, ok := sourcetypes[dataSources[i].Name]; ok {
+			dataSources[i].Metadata["sourcetypes"] = indexSourcetypes
+		}
+	}
+
+	return dataSources, nil
+}
+
+// fetchSourcetypes returns the sourcetypes seen in each index over the
+// lookback window. tstats only reads index metadata, keeping the search cheap.
+func (s *SplunkProvider) fetchSourcetypes(ctx context.Context) (map[string][]string, error) {
+	baseURL := strings.TrimSuffix(s.config.Endpoint, "/")
+	endpoint := fmt.Sprintf("%s/services/search/jobs/export", baseURL)
+
+	earliest := s.config.Options["sourcetype_lookback"]
+	if earliest == "" {
+		earliest = "-30d"
+	}
+
+	form := url.Values{}
+	form.Add("search", "| tstats count where index=* OR index=_* by index, sourcetype")
+	form.Add("earliest_time", earliest)
+	form.Add("output_mode", "json")
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if s.config.Auth != nil {
+		s.addAuth(req)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("splunk returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	// The export endpoint streams one JSON object per result row
+	sourcetypes := make(map[string][]string)
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var row struct {
+			Result struct {
+				Index      string `json:"index"`
+				Sourcetype string `json:"sourcetype"`
+			} `json:"result"`
+		}
+		if err := decoder.Decode(&row); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to decode search results: %w", err)
+		}
+		if row.Result.Index != "" && row.Result.Sourcetype != "" {
+			sourcetypes[row.Result.Index] = append(sourcetypes[row.Result.Index], row.Result.Sourcetype)
+		}
+	}
+
+	return sourcetypes, nil
 }
 
 func (s *SplunkProvider) fetchIndexes(ctx context.Context) ([]types.DataSource, error) {
@@ -138,6 +221,8 @@ func (s *SplunkProvider) convertToDataSource(entry struct {
 		ColdPath              string `json:"coldPath"`
 		ThawedPath            string `json:"thawedPath"`
 		EnableOnlineBucketRepair string `json:"enableOnlineBucketRepair"`
+		FrozenTimePeriod      string `json:"frozenTimePeriodInSecs"`
+		Disabled              bool   `json:"disabled"`
 	} `json:"content"`
 }) types.DataSource {
 	
@@ -167,6 +252,8 @@ func (s *SplunkProvider) convertToDataSource(entry struct {
 		"homePath":          entry.Content.HomePath,
 		"coldPath":          entry.Content.ColdPath,
 		"thawedPath":        entry.Content.ThawedPath,
+		"frozenTimePeriodInSecs": entry.Content.FrozenTimePeriod,
+		"disabled":          entry.Content.Disabled,
 	}
 
 	// Parse time ranges if available
@@ -176,9 +263,52 @@ func (s *SplunkProvider) convertToDataSource(entry struct {
 		}
 	}
 
+	ds.Normalized = s.normalize(entry.Content.Disabled, entry.Content.TotalEventCount,
+		entry.Content.CurrentSizeMB, entry.Content.FrozenTimePeriod,
+		entry.Content.MinTime, entry.Content.MaxTime)
+
 	return ds
 }
 
+// normalize converts Splunk's string-typed index statistics into normalized fields
+func (s *SplunkProvider) normalize(disabled bool, eventCount, sizeMB, frozenSecs, minTime, maxTime string) *types.NormalizedFields {
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(!disabled)
+
+	if count, ok := parseInt64(eventCount); ok {
+		n.EventCount = int64Ptr(count)
+	}
+	if mb, ok := parseFloat64(sizeMB); ok {
+		n.SizeBytes = int64Ptr(megabytesToBytes(mb))
+	}
+	if seconds, ok := parseInt64(frozenSecs); ok && seconds > 0 {
+		n.RetentionDays = intPtr(secondsToDays(seconds))
+	}
+	if first, ok := parseSplunkTime(minTime); ok {
+		n.FirstEventTime = timePtr(first)
+	}
+	if last, ok := parseSplunkTime(maxTime); ok {
+		n.LastEventTime = timePtr(last)
+	}
+
+	return n
+}
+
+// parseSplunkTime parses the index time bounds, which Splunk reports either
+// as ISO-8601 timestamps or as epoch seconds depending on version
+func parseSplunkTime(value string) (time.Time, bool) {
+	if value == "" || value == "0" {
+		return time.Time{}, false
+	}
+	if t, ok := parseTimeLayouts(value, "2006-01-02T15:04:05.000-07:00", time.RFC3339); ok {
+		return t.UTC(), true
+	}
+	if epoch, ok := parseInt64(value); ok && epoch > 0 {
+		return time.Unix(epoch, 0).UTC(), true
+	}
+	return time.Time{}, false
+}
+
 func (s *SplunkProvider) addAuth(req *http.Request) {
 	auth := s.config.Auth
 	switch auth.Type {
@@ -217,11 +347,113 @@ func (s *SplunkProvider) ValidateConnection(ctx context.Context) error {
 	return nil
 }
 
+// SplunkSavedSearchResponse represents Splunk's saved searches API response
+type SplunkSavedSearchResponse struct {
+	Entry []struct {
+		ID      string `json:"id"`
+		Name    string `json:"name"`
+		ACL     struct {
+			App string `json:"app"`
+		} `json:"acl"`
+		Content struct {
+			Search            string      `json:"search"`
+			Disabled          bool        `json:"disabled"`
+			IsScheduled       bool        `json:"is_scheduled"`
+			CronSchedule      string      `json:"cron_schedule"`
+			CorrelationSearch interface{} `json:"action.correlationsearch.enabled"`
+		} `json:"content"`
+	} `json:"entry"`
+}
+
+// FetchDetectionRules lists correlation searches and scheduled saved searches
+func (s *SplunkProvider) FetchDetectionRules(ctx context.Context) ([]types.DetectionRule, error) {
+	baseURL := strings.TrimSuffix(s.config.Endpoint, "/")
+	endpoint := fmt.Sprintf("%s/servicesNS/-/-/saved/searches", baseURL)
+
+	params := url.Values{}
+	params.Add("output_mode", "json")
+	params.Add("count", "0")
+
+	fullURL := fmt.Sprintf("%s?%s", endpoint, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if s.config.Auth != nil {
+		s.addAuth(req)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("splunk returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var searchResp SplunkSavedSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&searchResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	rules := make([]types.DetectionRule, 0, len(searchResp.Entry))
+	for _, entry := range searchResp.Entry {
+		correlation := isTruthy(entry.Content.CorrelationSearch)
+		// Unscheduled saved searches are reports, not detections
+		if !correlation && !entry.Content.IsScheduled {
+			continue
+		}
+
+		ruleType := "splunk-saved-search"
+		if correlation {
+			ruleType = "splunk-correlation-search"
+		}
+
+		rules = append(rules, types.DetectionRule{
+			ID:       entry.ID,
+			Name:     entry.Name,
+			Type:     ruleType,
+			Enabled:  !entry.Content.Disabled,
+			Language: "spl",
+			Query:    entry.Content.Search,
+			Metadata: map[string]interface{}{
+				"app":          entry.ACL.App,
+				"cronSchedule": entry.Content.CronSchedule,
+			},
+		})
+	}
+
+	return rules, nil
+}
+
+// isTruthy interprets Splunk's boolean settings, which arrive as bools,
+// numbers or strings depending on how they were configured
+func isTruthy(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		switch strings.ToLower(v) {
+		case "1", "true", "t", "yes", "y":
+			return true
+		}
+	}
+	return false
+}
+
 func (s *SplunkProvider) GetCapabilities() types.ProviderCapabilities {
 	return types.ProviderCapabilities{
 		SupportsRealTimeQueries: true,
 		SupportsHistoricalData:  true,
 		SupportedDataTypes:      []string{"splunk-index", "summary-index"},
 		RequiresAuthentication:  s.config.Auth != nil,
+		SupportsDetectionRules:  true,
 	}
 }
@@ -0,0 +1,421 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+// SumoLogicProvider implements the Provider interface for Sumo Logic. It
+// inventories collectors, their sources and partitions.
+type SumoLogicProvider struct {
+	config  types.ProviderConfig
+	client  *http.Client
+	baseURL string
+}
+
+// SumoLogicCollectorsResponse represents the /api/v1/collectors response
+type SumoLogicCollectorsResponse struct {
+	Collectors []SumoLogicCollector `json:"collectors"`
+}
+
+// SumoLogicCollector represents a hosted or installed collector
+type SumoLogicCollector struct {
+	ID               int64  `json:"id"`
+	Name             string `json:"name"`
+	Description      string `json:"description"`
+	CollectorType    string `json:"collectorType"`
+	CollectorVersion string `json:"collectorVersion"`
+	Category         string `json:"category"`
+	HostName         string `json:"hostName"`
+	OSName           string `json:"osName"`
+	Alive            bool   `json:"alive"`
+	Ephemeral        bool   `json:"ephemeral"`
+	LastSeenAlive    int64  `json:"lastSeenAlive"`
+}
+
+// SumoLogicSourcesResponse represents the /api/v1/collectors/{id}/sources response
+type SumoLogicSourcesResponse struct {
+	Sources []SumoLogicSource `json:"sources"`
+}
+
+// SumoLogicSource represents a source configured on a collector
+type SumoLogicSource struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Category    string `json:"category"`
+	SourceType  string `json:"sourceType"`
+	ContentType string `json:"contentType"`
+	HostName    string `json:"hostName"`
+	Alive       *bool  `json:"alive"`
+	Paused      bool   `json:"paused"`
+}
+
+// SumoLogicPartitionsResponse represents the /api/v1/partitions response
+type SumoLogicPartitionsResponse struct {
+	Data []struct {
+		ID                string `json:"id"`
+		Name              string `json:"name"`
+		RoutingExpression string `json:"routingExpression"`
+		AnalyticsTier     string `json:"analyticsTier"`
+		RetentionPeriod   int    `json:"retentionPeriod"`
+		TotalBytes        int64  `json:"totalBytes"`
+		IsActive          bool   `json:"isActive"`
+		IsCompliant       bool   `json:"isCompliant"`
+		CreatedAt         string `json:"createdAt"`
+		ModifiedAt        string `json:"modifiedAt"`
+	} `json:"data"`
+	Next string `json:"next"`
+}
+
+// SumoLogicHealthEventsResponse represents the /api/v1/healthEvents response
+type SumoLogicHealthEventsResponse struct {
+	Data []struct {
+		EventName        string `json:"eventName"`
+		SeverityLevel    string `json:"severityLevel"`
+		EventTime        string `json:"eventTime"`
+		ResourceIdentity struct {
+			ID   string `json:"id"`
+			Type string `json:"type"`
+		} `json:"resourceIdentity"`
+	} `json:"data"`
+	Next string `json:"next"`
+}
+
+// sumoLogicDeployments maps deployment codes to regional API endpoints
+var sumoLogicDeployments = map[string]string{
+	"us1": "https://api.sumologic.com",
+	"us2": "https://api.us2.sumologic.com",
+	"eu":  "https://api.eu.sumologic.com",
+	"au":  "https://api.au.sumologic.com",
+	"de":  "https://api.de.sumologic.com",
+	"jp":  "https://api.jp.sumologic.com",
+	"ca":  "https://api.ca.sumologic.com",
+	"in":  "https://api.in.sumologic.com",
+	"ch":  "https://api.ch.sumologic.com",
+	"kr":  "https://api.kr.sumologic.com",
+	"fed": "https://api.fed.sumologic.com",
+}
+
+// sumoLogicNoDataEvent matches health events reporting that a source stopped sending data
+var sumoLogicNoDataEvent = regexp.MustCompile(`(?i)no ?data|not (receiving|ingesting)|stopped (receiving|sending)`)
+
+// NewSumoLogicProvider creates a new Sumo Logic provider
+func NewSumoLogicProvider(config types.ProviderConfig) (types.Provider, error) {
+	client, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL, err := sumoLogicBaseURL(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SumoLogicProvider{
+		config:  config,
+		client:  client,
+		baseURL: baseURL,
+	}, nil
+}
+
+// sumoLogicBaseURL resolves the API endpoint from options.deployment, or from
+// the endpoint, accepting the web UI URL (service.<deployment>) as well
+func sumoLogicBaseURL(config types.ProviderConfig) (string, error) {
+	if deployment := strings.ToLower(config.Options["deployment"]); deployment != "" {
+		baseURL, ok := sumoLogicDeployments[deployment]
+		if !ok {
+			return "", fmt.Errorf("unknown Sumo Logic deployment: %s", deployment)
+		}
+		if config.Endpoint != "" {
+			return "", fmt.Errorf("sumologic takes an endpoint or options.deployment, not both")
+		}
+		return baseURL, nil
+	}
+	return strings.Replace(strings.TrimSuffix(config.Endpoint, "/"), "://service.", "://api.", 1), nil
+}
+
+func (s *SumoLogicProvider) Name() string {
+	return "sumologic"
+}
+
+func (s *SumoLogicProvider) FetchDataViews(ctx context.Context) ([]types.DataSource, error) {
+	collectors, err := s.fetchCollectors(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch collectors: %w", err)
+	}
+
+	health, err := s.fetchHealthEvents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch health events: %w", err)
+	}
+
+	dataSources := []types.DataSource{}
+	for _, collector := range collectors {
+		dataSources = append(dataSources, s.convertCollector(collector))
+
+		var sources SumoLogicSourcesResponse
+		if err := s.getJSON(ctx, fmt.Sprintf("/api/v1/collectors/%d/sources", collector.ID), &sources); err != nil {
+			return nil, fmt.Errorf("failed to fetch sources for collector %s: %w", collector.Name, err)
+		}
+		for _, source := range sources.Sources {
+			dataSources = append(dataSources, s.convertSource(source, collector, health[strconv.FormatInt(source.ID, 10)]))
+		}
+	}
+
+	partitions, err := s.fetchPartitions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch partitions: %w", err)
+	}
+	dataSources = append(dataSources, partitions...)
+
+	return dataSources, nil
+}
+
+func (s *SumoLogicProvider) fetchCollectors(ctx context.Context) ([]SumoLogicCollector, error) {
+	const limit = 1000
+	collectors := []SumoLogicCollector{}
+	for offset := 0; ; offset += limit {
+		var resp SumoLogicCollectorsResponse
+		path := fmt.Sprintf("/api/v1/collectors?limit=%d&offset=%d", limit, offset)
+		if err := s.getJSON(ctx, path, &resp); err != nil {
+			return nil, err
+		}
+		collectors = append(collectors, resp.Collectors...)
+		if len(resp.Collectors) < limit {
+			return collectors, nil
+		}
+	}
+}
+
+// fetchHealthEvents returns the names of open health events for sources,
+// keyed by source ID. Accounts without the health events feature return 403.
+func (s *SumoLogicProvider) fetchHealthEvents(ctx context.Context) (map[string][]string, error) {
+	events := make(map[string][]string)
+	token := ""
+	for {
+		params := url.Values{}
+		params.Add("limit", "1000")
+		if token != "" {
+			params.Add("token", token)
+		}
+
+		var resp SumoLogicHealthEventsResponse
+		err := s.getJSON(ctx, "/api/v1/healthEvents?"+params.Encode(), &resp)
+		if isStatus(err, http.StatusForbidden) || isStatus(err, http.StatusNotFound) {
+			return events, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range resp.Data {
+			if strings.EqualFold(event.ResourceIdentity.Type, "Source") {
+				events[event.ResourceIdentity.ID] = append(events[event.ResourceIdentity.ID], event.EventName)
+			}
+		}
+		if resp.Next == "" {
+			return events, nil
+		}
+		token = resp.Next
+	}
+}
+
+func (s *SumoLogicProvider) fetchPartitions(ctx context.Context) ([]types.DataSource, error) {
+	dataSources := []types.DataSource{}
+	token := ""
+	for {
+		params := url.Values{}
+		params.Add("limit", "1000")
+		if token != "" {
+			params.Add("token", token)
+		}
+
+		var resp SumoLogicPartitionsResponse
+		if err := s.getJSON(ctx, "/api/v1/partitions?"+params.Encode(), &resp); err != nil {
+			return nil, err
+		}
+
+		for _, partition := range resp.Data {
+			n := types.NewNormalizedFields()
+			n.Enabled = boolPtr(partition.IsActive)
+			n.SizeBytes = int64Ptr(partition.TotalBytes)
+			if partition.RetentionPeriod > 0 {
+				n.RetentionDays = intPtr(partition.RetentionPeriod)
+			}
+
+			status := "active"
+			if !partition.IsActive {
+				status = "disabled"
+			}
+
+			ds := types.DataSource{
+				ID:         "partition:" + partition.ID,
+				Name:       partition.Name,
+				Title:      partition.Name,
+				Type:       "sumologic-partition",
+				Pattern:    partition.RoutingExpression,
+				Status:     status,
+				Tags:       []string{"sumologic", "partition"},
+				Normalized: n,
+				Metadata: map[string]interface{}{
+					"routingExpression": partition.RoutingExpression,
+					"analyticsTier":     partition.AnalyticsTier,
+					"retentionPeriod":   partition.RetentionPeriod,
+					"isCompliant":       partition.IsCompliant,
+				},
+			}
+			if created, err := time.Parse(time.RFC3339, partition.CreatedAt); err == nil {
+				ds.CreatedAt = &created
+			}
+			if modified, err := time.Parse(time.RFC3339, partition.ModifiedAt); err == nil {
+				ds.UpdatedAt = &modified
+			}
+			dataSources = append(dataSources, ds)
+		}
+
+		if resp.Next == "" {
+			return dataSources, nil
+		}
+		token = resp.Next
+	}
+}
+
+// convertCollector maps a collector. Collectors have no enabled state, so
+// liveness only sets the status and metadata.alive.
+func (s *SumoLogicProvider) convertCollector(collector SumoLogicCollector) types.DataSource {
+	n := types.NewNormalizedFields()
+
+	status := "active"
+	if !collector.Alive {
+		status = "offline"
+	}
+
+	metadata := map[string]interface{}{
+		"collectorType":    collector.CollectorType,
+		"collectorVersion": collector.CollectorVersion,
+		"category":         collector.Category,
+		"hostName":         collector.HostName,
+		"alive":            collector.Alive,
+		"ephemeral":        collector.Ephemeral,
+	}
+	if collector.OSName != "" {
+		metadata["osName"] = collector.OSName
+	}
+	if collector.LastSeenAlive > 0 {
+		metadata["lastSeenAlive"] = unixMillis(collector.LastSeenAlive).Format(time.RFC3339)
+	}
+
+	return types.DataSource{
+		ID:          fmt.Sprintf("collector:%d", collector.ID),
+		Name:        collector.Name,
+		Title:       collector.Name,
+		Type:        "sumologic-collector",
+		Description: collector.Description,
+		Status:      status,
+		Tags:        []string{"sumologic", "collector", strings.ToLower(collector.CollectorType)},
+		Normalized:  n,
+		Metadata:    metadata,
+	}
+}
+
+// convertSource maps a source. A source on a dead collector is offline even
+// if the source itself reports alive; open health events mark it as no_data
+// when they report missing data and degraded otherwise.
+func (s *SumoLogicProvider) convertSource(source SumoLogicSource, collector SumoLogicCollector, healthEvents []string) types.DataSource {
+	status := "active"
+	switch {
+	case source.Paused:
+		status = "disabled"
+	case !collector.Alive || (source.Alive != nil && !*source.Alive):
+		status = "offline"
+	case len(healthEvents) > 0:
+		status = "degraded"
+		for _, event := range healthEvents {
+			if sumoLogicNoDataEvent.MatchString(event) {
+				status = "no_data"
+				break
+			}
+		}
+	}
+
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(!source.Paused)
+
+	metadata := map[string]interface{}{
+		"sourceCategory": source.Category,
+		"sourceType":     source.SourceType,
+		"contentType":    source.ContentType,
+		"collectorId":    collector.ID,
+		"collectorName":  collector.Name,
+	}
+	if source.Alive != nil {
+		metadata["alive"] = *source.Alive
+	}
+	if source.HostName != "" {
+		metadata["hostName"] = source.HostName
+	}
+	if len(healthEvents) > 0 {
+		metadata["healthEvents"] = healthEvents
+	}
+
+	return types.DataSource{
+		ID:          fmt.Sprintf("source:%d", source.ID),
+		Name:        source.Name,
+		Title:       source.Name,
+		Type:        "sumologic-source",
+		Pattern:     source.Category,
+		Description: source.Description,
+		Status:      status,
+		Tags:        []string{"sumologic", "source"},
+		Normalized:  n,
+		Metadata:    metadata,
+	}
+}
+
+// getJSON performs a GET against the Sumo Logic API using access ID/key
+// basic auth
+func (s *SumoLogicProvider) getJSON(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if s.config.Auth != nil {
+		req.SetBasicAuth(s.config.Auth.Username, s.config.Auth.Password)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return readJSONResponse(resp, "sumo logic", out)
+}
+
+func (s *SumoLogicProvider) ValidateConnection(ctx context.Context) error {
+	var resp SumoLogicCollectorsResponse
+	if err := s.getJSON(ctx, "/api/v1/collectors?limit=1", &resp); err != nil {
+		return fmt.Errorf("failed to connect to Sumo Logic: %w", err)
+	}
+	return nil
+}
+
+func (s *SumoLogicProvider) GetCapabilities() types.ProviderCapabilities {
+	return types.ProviderCapabilities{
+		SupportsRealTimeQueries: true,
+		SupportsHistoricalData:  true,
+		SupportedDataTypes:      []string{"sumologic-collector", "sumologic-source", "sumologic-partition"},
+		RequiresAuthentication:  true,
+	}
+}
@@ -0,0 +1,109 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+func TestSumoLogicFetchDataViews(t *testing.T) {
+	responses := map[string]string{
+		"/api/v1/collectors": `{"collectors":[
+			{"id":1,"name":"hosted","collectorType":"Hosted","alive":true},
+			{"id":2,"name":"dc-01","collectorType":"Installable","alive":false,"lastSeenAlive":1700000000000}]}`,
+		"/api/v1/collectors/1/sources": `{"sources":[
+			{"id":10,"name":"cloudtrail","category":"aws/cloudtrail","sourceType":"Polling","contentType":"AwsCloudTrailBucket","alive":true},
+			{"id":11,"name":"okta","category":"okta","sourceType":"HTTP","alive":true},
+			{"id":12,"name":"gsuite","category":"gsuite","sourceType":"Polling","alive":true}]}`,
+		"/api/v1/collectors/2/sources": `{"sources":[{"id":20,"name":"windows","category":"windows/security","sourceType":"LocalWindowsEventLog","alive":true}]}`,
+		"/api/v1/healthEvents": `{"data":[
+			{"eventName":"Source not receiving data","resourceIdentity":{"id":"11","type":"Source"}},
+			{"eventName":"Third party config","resourceIdentity":{"id":"12","type":"Source"}}]}`,
+		"/api/v1/partitions": `{"data":[{"id":"p1","name":"security","routingExpression":"_sourceCategory=aws/*","retentionPeriod":365,"totalBytes":1024,"isActive":true,"analyticsTier":"enhanced"}]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "suXXXX" || pass != "key" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	provider, err := NewSumoLogicProvider(types.ProviderConfig{
+		Type:     "sumologic",
+		Endpoint: server.URL,
+		Auth:     &types.AuthConfig{Type: "basic", Username: "suXXXX", Password: "key"},
+		Timeout:  5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, err := provider.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+
+	statuses := make(map[string]string)
+	for _, ds := range sources {
+		statuses[ds.ID] = ds.Status
+	}
+	want := map[string]string{
+		"collector:1":  "active",
+		"collector:2":  "offline",
+		"source:10":    "active",
+		"source:11":    "no_data",
+		"source:12":    "degraded",
+		"source:20":    "offline",
+		"partition:p1": "active",
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("expected %d data sources, got %v", len(want), statuses)
+	}
+	for id, status := range want {
+		if statuses[id] != status {
+			t.Errorf("%s: expected status %s, got %s", id, status, statuses[id])
+		}
+	}
+
+	for _, ds := range sources {
+		if ds.ID == "partition:p1" && (ds.Normalized.RetentionDays == nil || *ds.Normalized.RetentionDays != 365) {
+			t.Errorf("expected partition retention 365, got %v", ds.Normalized.RetentionDays)
+		}
+		if ds.ID == "collector:2" && (ds.Normalized.Enabled != nil || ds.Metadata["alive"] != false) {
+			t.Errorf("a dead collector is not disabled, got enabled %v, alive %v", ds.Normalized.Enabled, ds.Metadata["alive"])
+		}
+	}
+}
+
+func TestSumoLogicBaseURL(t *testing.T) {
+	cases := []struct {
+		config types.ProviderConfig
+		want   string
+	}{
+		{types.ProviderConfig{Endpoint: "https://api.sumologic.com"}, "https://api.sumologic.com"},
+		{types.ProviderConfig{Endpoint: "https://service.eu.sumologic.com/"}, "https://api.eu.sumologic.com"},
+		{types.ProviderConfig{Options: map[string]string{"deployment": "AU"}}, "https://api.au.sumologic.com"},
+	}
+	for _, c := range cases {
+		got, err := sumoLogicBaseURL(c.config)
+		if err != nil || got != c.want {
+			t.Errorf("sumoLogicBaseURL(%+v) = %q, %v; want %q", c.config, got, err, c.want)
+		}
+	}
+	if _, err := sumoLogicBaseURL(types.ProviderConfig{Options: map[string]string{"deployment": "mars"}}); err == nil {
+		t.Error("expected error for unknown deployment")
+	}
+	if _, err := sumoLogicBaseURL(types.ProviderConfig{Endpoint: "https://api.sumologic.com", Options: map[string]string{"deployment": "au"}}); err == nil {
+		t.Error("expected error for both an endpoint and a deployment")
+	}
+}
@@ -0,0 +1,523 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+// WazuhProvider implements the Provider interface for the Wazuh manager API.
+// It inventories agents, decoders grouped by file and the localfile log
+// collection entries of each agent group, and optionally the indices of the
+// Wazuh indexer.
+type WazuhProvider struct {
+	config  types.ProviderConfig
+	client  *http.Client
+	indexer *OpenSearchProvider
+
+	mu    sync.Mutex
+	token cachedToken
+}
+
+// WazuhResponse is the envelope of every Wazuh API response
+type WazuhResponse struct {
+	Data struct {
+		AffectedItems      []json.RawMessage `json:"affected_items"`
+		TotalAffectedItems int               `json:"total_affected_items"`
+	} `json:"data"`
+	Message string `json:"message"`
+	Error   int    `json:"error"`
+}
+
+// WazuhAgent represents an enrolled agent
+type WazuhAgent struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	IP            string   `json:"ip"`
+	Status        string   `json:"status"`
+	Version       string   `json:"version"`
+	Group         []string `json:"group"`
+	NodeName      string   `json:"node_name"`
+	LastKeepAlive string   `json:"lastKeepAlive"`
+	DateAdd       string   `json:"dateAdd"`
+	OS            struct {
+		Name     string `json:"name"`
+		Platform string `json:"platform"`
+		Version  string `json:"version"`
+	} `json:"os"`
+}
+
+// WazuhDecoder represents a single decoder
+type WazuhDecoder struct {
+	Name            string `json:"name"`
+	Filename        string `json:"filename"`
+	RelativeDirname string `json:"relative_dirname"`
+	Status          string `json:"status"`
+}
+
+// WazuhGroup represents an agent group
+type WazuhGroup struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// WazuhGroupConfiguration is one block of a group's agent.conf
+type WazuhGroupConfiguration struct {
+	Filters map[string]interface{} `json:"filters"`
+	Config  struct {
+		Localfile []WazuhLocalfile `json:"localfile"`
+	} `json:"config"`
+}
+
+// WazuhLocalfile is a log collection entry
+type WazuhLocalfile struct {
+	Location  string `json:"location"`
+	LogFormat string `json:"log_format"`
+	Command   string `json:"command"`
+	Alias     string `json:"alias"`
+	Frequency string `json:"frequency"`
+}
+
+// wazuhTokenLifetime is the manager's default auth_token_exp_timeout
+const wazuhTokenLifetime = 900 * time.Second
+
+// NewWazuhProvider creates a new Wazuh provider. Setting options.indexer_url
+// also inventories indexer indices matching options.indexer_pattern.
+func NewWazuhProvider(config types.ProviderConfig) (types.Provider, error) {
+	client, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &WazuhProvider{
+		config: config,
+		client: client,
+	}
+
+	if indexerURL := config.Options["indexer_url"]; indexerURL != "" {
+		// The indexer often uses different credentials than the manager API
+		indexerAuth := config.Auth
+		if username := config.Options["indexer_username"]; username != "" {
+			indexerAuth = &types.AuthConfig{Type: "basic", Username: username, Password: config.Options["indexer_password"]}
+		}
+		indexer, err := NewOpenSearchProvider(types.ProviderConfig{
+			Type:     "opensearch",
+			Endpoint: indexerURL,
+			Auth:     indexerAuth,
+			TLS:      config.TLS,
+			Timeout:  config.Timeout,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure indexer: %w", err)
+		}
+		provider.indexer = indexer.(*OpenSearchProvider)
+	}
+
+	return provider, nil
+}
+
+func (w *WazuhProvider) Name() string {
+	return "wazuh"
+}
+
+func (w *WazuhProvider) FetchDataViews(ctx context.Context) ([]types.DataSource, error) {
+	dataSources := []types.DataSource{}
+
+	agents, err := w.fetchAgents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch agents: %w", err)
+	}
+	dataSources = append(dataSources, agents...)
+
+	localfiles, err := w.fetchLocalfiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch group configuration: %w", err)
+	}
+	dataSources = append(dataSources, localfiles...)
+
+	if w.config.Options["include_decoders"] != "false" {
+		decoders, err := w.fetchDecoders(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch decoders: %w", err)
+		}
+		dataSources = append(dataSources, decoders...)
+	}
+
+	if w.indexer != nil {
+		indices, err := w.fetchIndexerIndices(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch indexer indices: %w", err)
+		}
+		dataSources = append(dataSources, indices...)
+	}
+
+	return dataSources, nil
+}
+
+func (w *WazuhProvider) fetchAgents(ctx context.Context) ([]types.DataSource, error) {
+	items, err := w.listAll(ctx, "/agents", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	dataSources := make([]types.DataSource, 0, len(items))
+	for _, item := range items {
+		var agent WazuhAgent
+		if err := json.Unmarshal(item, &agent); err != nil {
+			return nil, fmt.Errorf("failed to decode agent: %w", err)
+		}
+		dataSources = append(dataSources, w.convertAgent(agent))
+	}
+	return dataSources, nil
+}
+
+// convertAgent maps an agent; Wazuh's own status values (active,
+// disconnected, never_connected, pending) are kept as the status. Every
+// listed agent is enrolled, so connectivity does not affect enabled.
+func (w *WazuhProvider) convertAgent(agent WazuhAgent) types.DataSource {
+	status := strings.ToLower(agent.Status)
+	if status == "" {
+		status = "unknown"
+	}
+
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(true)
+	n.Vendor = "Wazuh"
+	n.Product = "Wazuh agent"
+
+	metadata := map[string]interface{}{
+		"ip":         agent.IP,
+		"os":         agent.OS.Name,
+		"osPlatform": agent.OS.Platform,
+		"osVersion":  agent.OS.Version,
+		"version":    agent.Version,
+		"groups":     agent.Group,
+		"status":     agent.Status,
+	}
+	if agent.NodeName != "" {
+		metadata["node"] = agent.NodeName
+	}
+	if agent.LastKeepAlive != "" {
+		metadata["lastKeepAlive"] = agent.LastKeepAlive
+	}
+
+	tags := []string{"wazuh", "agent"}
+	for _, group := range agent.Group {
+		tags = append(tags, "group:"+group)
+	}
+
+	ds := types.DataSource{
+		ID:         "agent:" + agent.ID,
+		Name:       agent.Name,
+		Title:      agent.Name,
+		Type:       "wazuh-agent",
+		Status:     status,
+		Tags:       tags,
+		Normalized: n,
+		Metadata:   metadata,
+	}
+	if added, err := time.Parse(time.RFC3339, agent.DateAdd); err == nil {
+		ds.CreatedAt = &added
+	}
+	return ds
+}
+
+// fetchLocalfiles lists the localfile entries in each group's agent.conf
+func (w *WazuhProvider) fetchLocalfiles(ctx context.Context) ([]types.DataSource, error) {
+	items, err := w.listAll(ctx, "/groups", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	dataSources := []types.DataSource{}
+	for _, item := range items {
+		var group WazuhGroup
+		if err := json.Unmarshal(item, &group); err != nil {
+			return nil, fmt.Errorf("failed to decode group: %w", err)
+		}
+
+		blocks, err := w.listAll(ctx, "/groups/"+url.PathEscape(group.Name)+"/configuration", nil)
+		if err != nil {
+			return nil, fmt.Errorf("group %s: %w", group.Name, err)
+		}
+		for _, block := range blocks {
+			var conf WazuhGroupConfiguration
+			if err := json.Unmarshal(block, &conf); err != nil {
+				return nil, fmt.Errorf("failed to decode configuration of group %s: %w", group.Name, err)
+			}
+			for _, localfile := range conf.Config.Localfile {
+				dataSources = append(dataSources, w.convertLocalfile(group, localfile, conf.Filters))
+			}
+		}
+	}
+	return dataSources, nil
+}
+
+func (w *WazuhProvider) convertLocalfile(group WazuhGroup, localfile WazuhLocalfile, filters map[string]interface{}) types.DataSource {
+	location := localfile.Location
+	if location == "" {
+		location = localfile.Command
+	}
+
+	metadata := map[string]interface{}{
+		"group":      group.Name,
+		"logFormat":  localfile.LogFormat,
+		"agentCount": group.Count,
+	}
+	if localfile.Command != "" {
+		metadata["command"] = localfile.Command
+	}
+	if localfile.Alias != "" {
+		metadata["alias"] = localfile.Alias
+	}
+	if len(filters) > 0 {
+		metadata["filters"] = filters
+	}
+
+	n := types.NewNormalizedFields()
+	n.Enabled = boolPtr(group.Count > 0)
+
+	return types.DataSource{
+		ID:         "localfile:" + group.Name + ":" + location,
+		Name:       location,
+		Title:      location,
+		Type:       "wazuh-localfile",
+		Pattern:    location,
+		Status:     "active",
+		Tags:       []string{"wazuh", "localfile", "group:" + group.Name, "format:" + localfile.LogFormat},
+		Normalized: n,
+		Metadata:   metadata,
+	}
+}
+
+// fetchDecoders groups decoders by file; individual decoders number in the
+// thousands and are only meaningful alongside their siblings. Files are keyed
+// by directory as well, since overrides in etc/decoders reuse ruleset names.
+func (w *WazuhProvider) fetchDecoders(ctx context.Context) ([]types.DataSource, error) {
+	items, err := w.listAll(ctx, "/decoders", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	type decoderFile struct {
+		dirname  string
+		filename string
+		names    map[string]bool
+		enabled  bool
+	}
+	files := make(map[string]*decoderFile)
+	for _, item := range items {
+		var decoder WazuhDecoder
+		if err := json.Unmarshal(item, &decoder); err != nil {
+			return nil, fmt.Errorf("failed to decode decoder: %w", err)
+		}
+		path := decoder.RelativeDirname + "/" + decoder.Filename
+		file, ok := files[path]
+		if !ok {
+			file = &decoderFile{dirname: decoder.RelativeDirname, filename: decoder.Filename, names: make(map[string]bool)}
+			files[path] = file
+		}
+		file.names[decoder.Name] = true
+		if decoder.Status != "disabled" {
+			file.enabled = true
+		}
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	dataSources := make([]types.DataSource, 0, len(paths))
+	for _, path := range paths {
+		file := files[path]
+		names := make([]string, 0, len(file.names))
+		for name := range file.names {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		status := "active"
+		if !file.enabled {
+			status = "disabled"
+		}
+		n := types.NewNormalizedFields()
+		n.Enabled = boolPtr(file.enabled)
+
+		dataSources = append(dataSources, types.DataSource{
+			ID:         "decoders:" + path,
+			Name:       file.filename,
+			Title:      file.filename,
+			Type:       "wazuh-decoder-file",
+			Status:     status,
+			Tags:       []string{"wazuh", "decoder"},
+			Normalized: n,
+			Metadata: map[string]interface{}{
+				"relativeDirname": file.dirname,
+				"decoders":        names,
+				"decoderCount":    len(names),
+			},
+		})
+	}
+	return dataSources, nil
+}
+
+// fetchIndexerIndices lists indexer indices matching options.indexer_pattern
+// (default "wazuh-*"), with ISM retention where configured
+func (w *WazuhProvider) fetchIndexerIndices(ctx context.Context) ([]types.DataSource, error) {
+	pattern := w.config.Options["indexer_pattern"]
+	if pattern == "" {
+		pattern = "wazuh-*"
+	}
+
+	policies, err := w.indexer.fetchISMPolicies(ctx)
+	if err != nil && !isStatus(err, http.StatusNotFound) {
+		return nil, err
+	}
+	indices, err := w.indexer.fetchIndices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	dataSources := []types.DataSource{}
+	for _, index := range indices {
+		if !indexPatternMatches(pattern, index.Index) {
+			continue
+		}
+		ds := w.indexer.convertIndex(index, policies)
+		ds.Type = "wazuh-indexer-index"
+		ds.Tags = []string{"wazuh", "indexer"}
+		dataSources = append(dataSources, ds)
+	}
+	return dataSources, nil
+}
+
+// listAll pages through a Wazuh list endpoint using offset and limit
+func (w *WazuhProvider) listAll(ctx context.Context, path string, params url.Values) ([]json.RawMessage, error) {
+	const limit = 500
+	items := []json.RawMessage{}
+	for offset := 0; ; offset += limit {
+		query := url.Values{}
+		for key, values := range params {
+			query[key] = values
+		}
+		query.Set("limit", strconv.Itoa(limit))
+		query.Set("offset", strconv.Itoa(offset))
+
+		var resp WazuhResponse
+		if err := w.getJSON(ctx, path+"?"+query.Encode(), &resp); err != nil {
+			return nil, err
+		}
+		items = append(items, resp.Data.AffectedItems...)
+		if len(resp.Data.AffectedItems) == 0 || len(items) >= resp.Data.TotalAffectedItems {
+			return items, nil
+		}
+	}
+}
+
+// getJSON performs an authenticated GET and checks the Wazuh error field
+func (w *WazuhProvider) getJSON(ctx context.Context, path string, resp *WazuhResponse) error {
+	token, err := w.authenticate(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", strings.TrimSuffix(w.config.Endpoint, "/")+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	httpResp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if err := readJSONResponse(httpResp, "wazuh", resp); err != nil {
+		return err
+	}
+	if resp.Error != 0 {
+		return fmt.Errorf("wazuh returned error %d: %s", resp.Error, resp.Message)
+	}
+	return nil
+}
+
+// authenticate exchanges the configured credentials for a JWT at
+// /security/user/authenticate, or uses a pre-issued bearer token
+func (w *WazuhProvider) authenticate(ctx context.Context) (string, error) {
+	auth := w.config.Auth
+	if auth != nil && auth.Type == "bearer" {
+		return auth.Token, nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	if w.token.valid(now) {
+		return w.token.value, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", strings.TrimSuffix(w.config.Endpoint, "/")+"/security/user/authenticate", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create authentication request: %w", err)
+	}
+	if auth != nil {
+		req.SetBasicAuth(auth.Username, auth.Password)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to authenticate: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+		Error int `json:"error"`
+	}
+	if err := readJSONResponse(resp, "wazuh", &result); err != nil {
+		return "", fmt.Errorf("authentication failed: %w", err)
+	}
+	if result.Data.Token == "" {
+		return "", fmt.Errorf("wazuh authentication returned no token")
+	}
+
+	w.token.set(oauthTokenResponse{AccessToken: result.Data.Token, ExpiresIn: int64(wazuhTokenLifetime / time.Second)}, now)
+	return w.token.value, nil
+}
+
+func (w *WazuhProvider) ValidateConnection(ctx context.Context) error {
+	var resp WazuhResponse
+	if err := w.getJSON(ctx, "/manager/info", &resp); err != nil {
+		return fmt.Errorf("failed to connect to Wazuh: %w", err)
+	}
+	return nil
+}
+
+func (w *WazuhProvider) GetCapabilities() types.ProviderCapabilities {
+	dataTypes := []string{"wazuh-agent", "wazuh-localfile", "wazuh-decoder-file"}
+	if w.indexer != nil {
+		dataTypes = append(dataTypes, "wazuh-indexer-index")
+	}
+	return types.ProviderCapabilities{
+		SupportsRealTimeQueries: false,
+		SupportsHistoricalData:  w.indexer != nil,
+		SupportedDataTypes:      dataTypes,
+		RequiresAuthentication:  true,
+	}
+}
@@ -0,0 +1,106 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+func TestWazuhFetchDataViews(t *testing.T) {
+	authCalls := 0
+	responses := map[string]string{
+		"/agents": `{"data":{"affected_items":[
+			{"id":"000","name":"manager","status":"active","version":"Wazuh v4.7.2","os":{"name":"Ubuntu","platform":"ubuntu","version":"22.04"}},
+			{"id":"001","name":"web-01","status":"disconnected","group":["linux"],"os":{"name":"CentOS"}},
+			{"id":"002","name":"dc-01","status":"never_connected","group":["windows"]}],"total_affected_items":3},"error":0}`,
+		"/groups": `{"data":{"affected_items":[{"name":"linux","count":1},{"name":"windows","count":0}],"total_affected_items":2},"error":0}`,
+		"/groups/linux/configuration": `{"data":{"affected_items":[{"filters":{},"config":{"localfile":[
+			{"location":"/var/log/auth.log","log_format":"syslog"},
+			{"command":"df -P","log_format":"command","alias":"df"}]}}],"total_affected_items":1},"error":0}`,
+		"/groups/windows/configuration": `{"data":{"affected_items":[{"filters":{"os":"Windows"},"config":{"localfile":[
+			{"location":"Security","log_format":"eventchannel"}]}}],"total_affected_items":1},"error":0}`,
+		"/decoders": `{"data":{"affected_items":[
+			{"name":"sshd","filename":"0310-ssh_decoders.xml","relative_dirname":"ruleset/decoders","status":"enabled"},
+			{"name":"sshd-success","filename":"0310-ssh_decoders.xml","relative_dirname":"ruleset/decoders","status":"enabled"},
+			{"name":"old","filename":"legacy.xml","relative_dirname":"etc/decoders","status":"disabled"},
+			{"name":"sshd-local","filename":"0310-ssh_decoders.xml","relative_dirname":"etc/decoders","status":"enabled"}],"total_affected_items":4},"error":0}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/security/user/authenticate" {
+			authCalls++
+			if user, pass, ok := r.BasicAuth(); !ok || user != "wazuh-wui" || pass != "secret" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"data":{"token":"jwt-token"},"error":0}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer jwt-token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		body, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	provider, err := NewWazuhProvider(types.ProviderConfig{
+		Type:     "wazuh",
+		Endpoint: server.URL,
+		Auth:     &types.AuthConfig{Type: "basic", Username: "wazuh-wui", Password: "secret"},
+		Timeout:  5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources, err := provider.FetchDataViews(context.Background())
+	if err != nil {
+		t.Fatalf("FetchDataViews: %v", err)
+	}
+	if authCalls != 1 {
+		t.Errorf("expected the JWT to be reused, got %d authentications", authCalls)
+	}
+
+	byID := make(map[string]types.DataSource)
+	for _, ds := range sources {
+		byID[ds.ID] = ds
+	}
+	if len(byID) != 9 {
+		t.Fatalf("expected 9 data sources, got %d", len(byID))
+	}
+
+	for id, want := range map[string]string{"agent:000": "active", "agent:001": "disconnected", "agent:002": "never_connected"} {
+		if got := byID[id].Status; got != want {
+			t.Errorf("%s: expected status %s, got %s", id, want, got)
+		}
+		if enabled := byID[id].Normalized.Enabled; enabled == nil || !*enabled {
+			t.Errorf("%s: an enrolled agent is enabled whatever its connectivity", id)
+		}
+	}
+	if ds := byID["localfile:linux:/var/log/auth.log"]; ds.Metadata["logFormat"] != "syslog" {
+		t.Errorf("unexpected localfile %+v", ds)
+	}
+	if ds := byID["localfile:linux:df -P"]; ds.Metadata["alias"] != "df" {
+		t.Errorf("command localfile should be keyed by its command, got %+v", ds)
+	}
+	if ds := byID["localfile:windows:Security"]; *ds.Normalized.Enabled {
+		t.Error("localfile in a group without agents should not be enabled")
+	}
+	if ds := byID["decoders:ruleset/decoders/0310-ssh_decoders.xml"]; ds.Metadata["decoderCount"] != 2 {
+		t.Errorf("unexpected decoder file %+v", ds)
+	}
+	if ds := byID["decoders:etc/decoders/0310-ssh_decoders.xml"]; ds.Metadata["decoderCount"] != 1 || ds.Name != "0310-ssh_decoders.xml" {
+		t.Errorf("an override file should stay separate from the ruleset file, got %+v", ds)
+	}
+	if ds := byID["decoders:etc/decoders/legacy.xml"]; ds.Status != "disabled" {
+		t.Errorf("expected disabled decoder file, got %s", ds.Status)
+	}
+}
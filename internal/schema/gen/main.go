@@ -0,0 +1,26 @@
+// Command gen writes the inventory JSON Schema generated from the Go types.
+// It is invoked by `go generate ./internal/schema`.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/logfiend/internal/schema"
+)
+
+func main() {
+	output := flag.String("o", "inventory.schema.json", "Path to write the schema")
+	flag.Parse()
+
+	data, err := json.MarshalIndent(schema.Generate(), "", "  ")
+	if err != nil {
+		log.Fatalf("failed to marshal schema: %v", err)
+	}
+
+	if err := os.WriteFile(*output, append(data, '\n'), 0644); err != nil {
+		log.Fatalf("failed to write schema: %v", err)
+	}
+}
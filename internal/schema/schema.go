@@ -0,0 +1,190 @@
+// Package schema publishes the JSON Schema contract for inventory output and
+// validates inventory documents against it.
+package schema
+
+import (
+	_ "embed"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+//go:generate go run ./gen -o inventory.schema.json
+
+// Draft is the JSON Schema dialect used by the published schema
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+//go:embed inventory.schema.json
+var inventorySchema []byte
+
+// Inventory returns the embedded JSON Schema for types.DataSourceInventory
+func Inventory() []byte {
+	return inventorySchema
+}
+
+// CheckVersion reports whether an inventory written with the given
+// schema_version can be read by consumers of the current schema: same major
+// version and the same or an older minor. Newer minors are rejected because
+// every object in the schema disallows unknown properties, so their additions
+// would fail validation. Inventories without a schema_version predate 1.1 and
+// are not accepted.
+func CheckVersion(version string) error {
+	if version == "" {
+		return fmt.Errorf("schema_version is missing (pre-1.1 inventory)")
+	}
+	major, minor, err := parseSchemaVersion(version)
+	if err != nil {
+		return err
+	}
+	currentMajor, currentMinor, err := parseSchemaVersion(types.InventorySchemaVersion)
+	if err != nil {
+		return err
+	}
+	if major != currentMajor {
+		return fmt.Errorf("schema_version %s is incompatible with %s", version, types.InventorySchemaVersion)
+	}
+	if minor > currentMinor {
+		return fmt.Errorf("schema_version %s is newer than %s; upgrade logfiend to read it", version, types.InventorySchemaVersion)
+	}
+	return nil
+}
+
+// parseSchemaVersion splits a "major.minor" schema version
+func parseSchemaVersion(version string) (int, int, error) {
+	majorText, minorText, ok := strings.Cut(version, ".")
+	major, majorErr := strconv.Atoi(majorText)
+	minor, minorErr := strconv.Atoi(minorText)
+	if !ok || majorErr != nil || minorErr != nil || major < 0 || minor < 0 {
+		return 0, 0, fmt.Errorf("invalid schema_version %q", version)
+	}
+	return major, minor, nil
+}
+
+// Generate builds the JSON Schema for types.DataSourceInventory from the Go
+// types. The embedded copy is produced by `go generate ./internal/schema`.
+func Generate() map[string]interface{} {
+	g := &generator{defs: make(map[string]interface{})}
+	root := g.schemaFor(reflect.TypeOf(types.DataSourceInventory{}))
+
+	doc := map[string]interface{}{
+		"$schema": Draft,
+		"$id":     "urn:logfiend:schema:datasource-inventory:" + types.InventorySchemaVersion,
+		"title":   "LogFiend data source inventory",
+		"$defs":   g.defs,
+	}
+	for key, value := range root {
+		doc[key] = value
+	}
+	return doc
+}
+
+// generator converts Go types to JSON Schema, emitting named structs as $defs
+type generator struct {
+	defs map[string]interface{}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (g *generator) schemaFor(t reflect.Type) map[string]interface{} {
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Pointer:
+		return g.schemaFor(t.Elem())
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schemaFor(t.Elem())}
+	case reflect.Interface:
+		return map[string]interface{}{}
+	case reflect.Struct:
+		return g.structRef(t)
+	default:
+		panic(fmt.Sprintf("schema: unsupported type %s", t))
+	}
+}
+
+// structRef registers a struct under $defs and returns a reference to it
+func (g *generator) structRef(t reflect.Type) map[string]interface{} {
+	ref := map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+	if _, exists := g.defs[t.Name()]; exists {
+		return ref
+	}
+	g.defs[t.Name()] = nil // reserve the name for recursive types
+
+	properties := make(map[string]interface{})
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitEmpty, ok := jsonFieldName(field)
+		if !ok {
+			continue
+		}
+
+		prop := g.schemaFor(field.Type)
+		if !omitEmpty && nullable(field.Type) {
+			prop = map[string]interface{}{"anyOf": []interface{}{prop, map[string]interface{}{"type": "null"}}}
+		}
+		properties[name] = prop
+		if !omitEmpty {
+			required = append(required, name)
+		}
+	}
+	sort.Strings(required)
+
+	g.defs[t.Name()] = map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+	return ref
+}
+
+// jsonFieldName mirrors encoding/json's handling of struct tags
+func jsonFieldName(field reflect.StructField) (name string, omitEmpty bool, ok bool) {
+	if !field.IsExported() {
+		return "", false, false
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, true
+}
+
+// nullable reports whether encoding/json may emit null for a value of type t
+func nullable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		return true
+	}
+	return false
+}
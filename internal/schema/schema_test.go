@@ -0,0 +1,109 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/logfiend/internal/types"
+)
+
+func TestEmbeddedSchemaUpToDate(t *testing.T) {
+	generated, err := json.MarshalIndent(Generate(), "", "  ")
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+	if !bytes.Equal(append(generated, '\n'), Inventory()) {
+		t.Fatal("inventory.schema.json is stale; run `go generate ./internal/schema`")
+	}
+}
+
+func TestValidateInventoryAcceptsGeneratedOutput(t *testing.T) {
+	now := time.Now()
+	inventory := types.DataSourceInventory{
+		Metadata: types.InventoryMetadata{
+			SchemaVersion: types.InventorySchemaVersion,
+			Timestamp:     now,
+			Provider:      "splunk",
+			Version:       "dev",
+			SourceCount:   1,
+			GeneratedBy:   "logfiend",
+		},
+		DataSources: []types.DataSource{{
+			ID:         "main",
+			Name:       "main",
+			Title:      "main",
+			Type:       "splunk-index",
+			UpdatedAt:  &now,
+			Tags:       []string{"external"},
+			Normalized: types.NewNormalizedFields(),
+			Metadata:   map[string]interface{}{"currentSizeMB": "12"},
+		}},
+	}
+	data, err := json.Marshal(inventory)
+	if err != nil {
+		t.Fatalf("marshal error: %v", err)
+	}
+
+	violations, err := ValidateInventory(data)
+	if err != nil {
+		t.Fatalf("validate error: %v", err)
+	}
+	if len(violations) != 0 {
+		t.Fatalf("expected no violations, got %v", violations)
+	}
+}
+
+func TestValidateInventoryReportsPointers(t *testing.T) {
+	document := []byte(`{
+		"metadata": {"schema_version": "1.1", "timestamp": "yesterday", "provider": "x",
+			"version": "dev", "source_count": "1", "generated_by": "logfiend"},
+		"data_sources": [{"id": "a", "name": "a", "title": "a", "type": "t",
+			"normalized": {"version": 1, "event_count": 1.5}, "a/b": true}]
+	}`)
+
+	violations, err := ValidateInventory(document)
+	if err != nil {
+		t.Fatalf("validate error: %v", err)
+	}
+
+	want := map[string]bool{
+		"/metadata/timestamp":                    true,
+		"/metadata/source_count":                 true,
+		"/data_sources/0/normalized/event_count": true,
+		"/data_sources/0/a~1b":                   true,
+	}
+	if len(violations) != len(want) {
+		t.Fatalf("expected %d violations, got %v", len(want), violations)
+	}
+	for _, violation := range violations {
+		if !want[violation.Pointer] {
+			t.Fatalf("unexpected violation %v", violation)
+		}
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	major, minor, err := parseSchemaVersion(types.InventorySchemaVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		version string
+		ok      bool
+	}{
+		{types.InventorySchemaVersion, true},
+		{"1.0", true},
+		{"", false},
+		{"2.0", false},
+		{fmt.Sprintf("%d.%d", major, minor+1), false},
+		{"1.x", false},
+	}
+	for _, c := range cases {
+		if err := CheckVersion(c.version); (err == nil) != c.ok {
+			t.Fatalf("CheckVersion(%q): expected ok=%v, got %v", c.version, c.ok, err)
+		}
+	}
+}
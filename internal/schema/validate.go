@@ -0,0 +1,270 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ValidationError describes a single schema violation located by JSON pointer
+type ValidationError struct {
+	Pointer string `json:"pointer"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	pointer := e.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	return fmt.Sprintf("%s: %s", pointer, e.Message)
+}
+
+// ValidateInventory checks an inventory document against the embedded schema.
+// It returns an error only if the schema or the document is not valid JSON.
+func ValidateInventory(document []byte) ([]ValidationError, error) {
+	var root map[string]interface{}
+	if err := decodeJSON(inventorySchema, &root); err != nil {
+		return nil, fmt.Errorf("embedded schema is invalid: %w", err)
+	}
+	return Validate(root, document)
+}
+
+// decodeJSON decodes with UseNumber so integers and numbers stay distinguishable
+func decodeJSON(data []byte, target interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(target)
+}
+
+// Validate checks a JSON document against a schema. Only the keywords used by
+// the generated inventory schema are supported: $ref (local), type, properties,
+// required, additionalProperties, items, anyOf, enum and format "date-time".
+func Validate(schemaDoc map[string]interface{}, document []byte) ([]ValidationError, error) {
+	var value interface{}
+	if err := decodeJSON(document, &value); err != nil {
+		return nil, fmt.Errorf("document is not valid JSON: %w", err)
+	}
+
+	v := &validator{root: schemaDoc}
+	return v.validate(schemaDoc, value, ""), nil
+}
+
+type validator struct {
+	root map[string]interface{}
+}
+
+func (v *validator) validate(schema map[string]interface{}, value interface{}, pointer string) []ValidationError {
+	if ref, ok := schema["$ref"].(string); ok {
+		target, err := v.resolve(ref)
+		if err != nil {
+			return []ValidationError{{Pointer: pointer, Message: err.Error()}}
+		}
+		if errs := v.validate(target, value, pointer); len(errs) > 0 {
+			return errs
+		}
+	}
+
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		if errs := v.validateAnyOf(anyOf, value, pointer); len(errs) > 0 {
+			return errs
+		}
+	}
+
+	if expected, ok := schema["type"]; ok && !matchesType(expected, value) {
+		return []ValidationError{{
+			Pointer: pointer,
+			Message: fmt.Sprintf("expected %s, got %s", describeType(expected), jsonType(value)),
+		}}
+	}
+
+	var errs []ValidationError
+	if enum, ok := schema["enum"].([]interface{}); ok && !inEnum(enum, value) {
+		errs = append(errs, ValidationError{Pointer: pointer, Message: fmt.Sprintf("value %v is not one of %v", value, enum)})
+	}
+	if format, ok := schema["format"].(string); ok {
+		errs = append(errs, checkFormat(format, value, pointer)...)
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		errs = append(errs, v.validateObject(schema, typed, pointer)...)
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range typed {
+				errs = append(errs, v.validate(items, item, fmt.Sprintf("%s/%d", pointer, i))...)
+			}
+		}
+	}
+
+	return errs
+}
+
+func (v *validator) validateObject(schema map[string]interface{}, object map[string]interface{}, pointer string) []ValidationError {
+	var errs []ValidationError
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			key, _ := name.(string)
+			if _, present := object[key]; !present {
+				errs = append(errs, ValidationError{Pointer: pointer, Message: fmt.Sprintf("missing required property %q", key)})
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		child := pointer + "/" + escapePointer(key)
+		if propSchema, ok := properties[key].(map[string]interface{}); ok {
+			errs = append(errs, v.validate(propSchema, object[key], child)...)
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				errs = append(errs, ValidationError{Pointer: child, Message: "property is not allowed by the schema"})
+			}
+		case map[string]interface{}:
+			errs = append(errs, v.validate(additional, object[key], child)...)
+		}
+	}
+
+	return errs
+}
+
+// validateAnyOf passes if any branch matches. Otherwise it reports the errors
+// of the branch whose type matched, which is more useful than a bare mismatch.
+func (v *validator) validateAnyOf(branches []interface{}, value interface{}, pointer string) []ValidationError {
+	var closest []ValidationError
+	for _, branch := range branches {
+		branchSchema, ok := branch.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		errs := v.validate(branchSchema, value, pointer)
+		if len(errs) == 0 {
+			return nil
+		}
+		if closest == nil && !(len(errs) == 1 && errs[0].Pointer == pointer) {
+			closest = errs
+		}
+	}
+	if closest != nil {
+		return closest
+	}
+	return []ValidationError{{Pointer: pointer, Message: fmt.Sprintf("%s does not match any allowed schema", jsonType(value))}}
+}
+
+// resolve follows a local reference of the form #/$defs/Name
+func (v *validator) resolve(ref string) (map[string]interface{}, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported schema reference %q", ref)
+	}
+	var node interface{} = v.root
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unresolvable schema reference %q", ref)
+		}
+		node = object[unescapePointer(token)]
+	}
+	target, ok := node.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unresolvable schema reference %q", ref)
+	}
+	return target, nil
+}
+
+func matchesType(expected interface{}, value interface{}) bool {
+	switch typed := expected.(type) {
+	case string:
+		return typeMatches(typed, value)
+	case []interface{}:
+		for _, candidate := range typed {
+			if name, ok := candidate.(string); ok && typeMatches(name, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func typeMatches(name string, value interface{}) bool {
+	actual := jsonType(value)
+	if name == "number" && actual == "integer" {
+		return true
+	}
+	return name == actual
+}
+
+// jsonType names the JSON Schema type of a value decoded with UseNumber
+func jsonType(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := typed.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func describeType(expected interface{}) string {
+	if list, ok := expected.([]interface{}); ok {
+		names := make([]string, 0, len(list))
+		for _, name := range list {
+			names = append(names, fmt.Sprint(name))
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(expected)
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, candidate := range enum {
+		if fmt.Sprint(candidate) == fmt.Sprint(value) && jsonType(candidate) == jsonType(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkFormat(format string, value interface{}, pointer string) []ValidationError {
+	text, ok := value.(string)
+	if !ok || format != "date-time" {
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339Nano, text); err != nil {
+		return []ValidationError{{Pointer: pointer, Message: fmt.Sprintf("%q is not an RFC 3339 date-time", text)}}
+	}
+	return nil
+}
+
+// escapePointer encodes a reference token per RFC 6901
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+func unescapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
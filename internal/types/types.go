@@ -17,22 +17,108 @@ type DataSource struct {
 	UpdatedAt   *time.Time             `json:"updated_at,omitempty"`
 	Status      string                 `json:"status,omitempty"`
 	Tags        []string               `json:"tags,omitempty"`
+	Normalized  *NormalizedFields      `json:"normalized,omitempty"`
+	OCSF        *OCSFClassification    `json:"ocsf,omitempty"`
+	Relations   []Relation             `json:"relations,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// InventorySchemaVersion identifies the layout of DataSourceInventory output.
+// Bump the minor version for additive changes and the major version for
+// anything that removes or changes the meaning of an existing field.
+const InventorySchemaVersion = "1.3"
+
+// Relation types describe how data flows between linked data sources
+const (
+	RelationRoutesTo    = "routes_to"    // source forwards events to the target
+	RelationStoredIn    = "stored_in"    // source's events are stored in the target
+	RelationProcessedBy = "processed_by" // source's events pass through the target
+)
+
+// Relation links a data source to another data source in the same inventory,
+// identified by its ID
+type Relation struct {
+	Type     string `json:"type"`
+	TargetID string `json:"target_id"`
+}
+
+// NormalizedFieldsVersion identifies the layout of NormalizedFields
+const NormalizedFieldsVersion = 1
+
+// NormalizedFields holds provider-independent facts about a data source in
+// consistent units. Nil pointers mean the provider could not determine the
+// value; the raw provider values remain available in DataSource.Metadata.
+type NormalizedFields struct {
+	Version        int        `json:"version"`
+	Enabled        *bool      `json:"enabled,omitempty"`
+	EventCount     *int64     `json:"event_count,omitempty"`
+	SizeBytes      *int64     `json:"size_bytes,omitempty"`
+	RetentionDays  *int       `json:"retention_days,omitempty"`
+	FirstEventTime *time.Time `json:"first_event_time,omitempty"`
+	LastEventTime  *time.Time `json:"last_event_time,omitempty"`
+	IngestRateEPS  *float64   `json:"ingest_rate_eps,omitempty"`
+	Vendor         string     `json:"vendor,omitempty"`
+	Product        string     `json:"product,omitempty"`
+}
+
+// NewNormalizedFields returns an empty NormalizedFields stamped with the current version
+func NewNormalizedFields() *NormalizedFields {
+	return &NormalizedFields{Version: NormalizedFieldsVersion}
+}
+
+// OCSFClassification lists the OCSF event classes a data source would populate
+type OCSFClassification struct {
+	SchemaVersion string      `json:"schema_version"`
+	Classes       []OCSFClass `json:"classes"`
+	MatchedBy     []string    `json:"matched_by,omitempty"`
+}
+
+// OCSFClass identifies an OCSF event class and its category
+type OCSFClass struct {
+	CategoryUID  int    `json:"category_uid"`
+	CategoryName string `json:"category_name"`
+	ClassUID     int    `json:"class_uid"`
+	ClassName    string `json:"class_name"`
+}
+
+// OCSFSummary counts classified data sources per OCSF category and class
+type OCSFSummary struct {
+	SchemaVersion     string                `json:"schema_version"`
+	ClassifiedCount   int                   `json:"classified_count"`
+	UnclassifiedCount int                   `json:"unclassified_count"`
+	Categories        []OCSFCategorySummary `json:"categories"`
+}
+
+// OCSFCategorySummary counts data sources populating one OCSF category
+type OCSFCategorySummary struct {
+	CategoryUID  int                `json:"category_uid"`
+	CategoryName string             `json:"category_name"`
+	SourceCount  int                `json:"source_count"`
+	Classes      []OCSFClassSummary `json:"classes"`
+}
+
+// OCSFClassSummary counts data sources populating one OCSF class
+type OCSFClassSummary struct {
+	ClassUID    int    `json:"class_uid"`
+	ClassName   string `json:"class_name"`
+	SourceCount int    `json:"source_count"`
+}
+
 // InventoryMetadata contains metadata about the inventory collection
 type InventoryMetadata struct {
-	Timestamp   time.Time `json:"timestamp"`
-	Provider    string    `json:"provider"`
-	Version     string    `json:"version"`
-	SourceCount int       `json:"source_count"`
-	GeneratedBy string    `json:"generated_by"`
+	SchemaVersion string    `json:"schema_version"`
+	Timestamp     time.Time `json:"timestamp"`
+	Provider      string    `json:"provider"`
+	Version       string    `json:"version"`
+	SourceCount   int       `json:"source_count"`
+	GeneratedBy   string    `json:"generated_by"`
 }
 
 // DataSourceInventory holds the complete inventory with metadata
 type DataSourceInventory struct {
 	Metadata    InventoryMetadata `json:"metadata"`
 	DataSources []DataSource      `json:"data_sources"`
+	OCSFSummary *OCSFSummary      `json:"ocsf_summary,omitempty"`
 }
 
 // Provider defines the interface that all SIEM providers must implement
@@ -51,12 +137,33 @@ type Provider interface {
 	GetCapabilities() ProviderCapabilities
 }
 
+// DetectionProvider is an optional capability for providers that can export
+// detection content. Callers discover it with a type assertion on a Provider.
+type DetectionProvider interface {
+	// FetchDetectionRules retrieves the detection rules defined in the SIEM
+	FetchDetectionRules(ctx context.Context) ([]DetectionRule, error)
+}
+
+// DetectionRule represents a detection (rule, correlation search, analytics
+// rule) in any SIEM system
+type DetectionRule struct {
+	ID       string                 `json:"id"`
+	Name     string                 `json:"name"`
+	Type     string                 `json:"type"`
+	Enabled  bool                   `json:"enabled"`
+	Language string                 `json:"language,omitempty"` // spl, kql, kuery, eql, esql, lucene
+	Query    string                 `json:"query,omitempty"`
+	Indices  []string               `json:"indices,omitempty"` // index patterns declared on the rule itself
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
 // ProviderCapabilities describes what features a provider supports
 type ProviderCapabilities struct {
 	SupportsRealTimeQueries bool     `json:"supports_real_time_queries"`
 	SupportsHistoricalData  bool     `json:"supports_historical_data"`
 	SupportedDataTypes      []string `json:"supported_data_types"`
 	RequiresAuthentication  bool     `json:"requires_authentication"`
+	SupportsDetectionRules  bool     `json:"supports_detection_rules"`
 }
 
 // ProviderConfig holds configuration for any provider
@@ -72,11 +179,29 @@ type ProviderConfig struct {
 
 // AuthConfig holds authentication configuration
 type AuthConfig struct {
-	Type     string `yaml:"type" json:"type"` // basic, bearer, api_key
+	Type     string `yaml:"type" json:"type"` // basic, bearer, api_key, aws_sigv4, service_account, client_credentials
 	Username string `yaml:"username,omitempty" json:"username,omitempty"`
 	Password string `yaml:"password,omitempty" json:"password,omitempty"`
 	Token    string `yaml:"token,omitempty" json:"token,omitempty"`
 	APIKey   string `yaml:"api_key,omitempty" json:"api_key,omitempty"`
+
+	// Datadog application key, sent alongside api_key
+	ApplicationKey string `yaml:"application_key,omitempty" json:"application_key,omitempty"`
+
+	// AWS Signature Version 4
+	AccessKeyID     string `yaml:"access_key_id,omitempty" json:"access_key_id,omitempty"`
+	SecretAccessKey string `yaml:"secret_access_key,omitempty" json:"secret_access_key,omitempty"`
+	SessionToken    string `yaml:"session_token,omitempty" json:"session_token,omitempty"`
+	Region          string `yaml:"region,omitempty" json:"region,omitempty"`
+
+	// OAuth client credentials grant
+	ClientID     string `yaml:"client_id,omitempty" json:"client_id,omitempty"`
+	ClientSecret string `yaml:"client_secret,omitempty" json:"client_secret,omitempty"`
+
+	// OAuth token exchange; credentials_file is a Google service-account JSON key
+	CredentialsFile string   `yaml:"credentials_file,omitempty" json:"credentials_file,omitempty"`
+	TokenURL        string   `yaml:"token_url,omitempty" json:"token_url,omitempty"`
+	Scopes          []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
 }
 
 // TLSConfig holds TLS configuration
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
@@ -12,6 +13,7 @@ import (
 	"strings"
 
 	"github.com/logfiend/internal/config"
+	"github.com/logfiend/internal/ocsf"
 	"github.com/logfiend/internal/providers"
 	"github.com/logfiend/internal/types"
 )
@@ -20,6 +22,19 @@ import (
 var version = "dev"
 
 func main() {
+	// Dispatch subcommands before the inventory flags are parsed
+	if len(os.Args) > 1 {
+		if cmd := findCommand(os.Args[1]); cmd != nil {
+			if err := cmd.run(os.Args[2:]); err != nil {
+				if !errors.Is(err, errCommandFailed) {
+					fmt.Fprintf(os.Stderr, "❌ %s: %v\n", cmd.name, err)
+				}
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+	}
+
 	configPath := flag.String("config", "config.yml", "Path to configuration file")
 	output := flag.String("output", "datasource_inventory.json", "Path to save data source inventory JSON")
 	providerName := flag.String("provider", "", "Override provider from config (optional)")
@@ -29,6 +44,11 @@ func main() {
 	debug := flag.Bool("debug", false, "Enable debug output")
 	airgap := flag.Bool("airgap", false, "Run in airgap mode (no network calls)")
 	version := flag.Bool("version", false, "Show version information")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: logfiend [OPTIONS] | logfiend <command> [args]\n\nOptions:\n")
+		flag.PrintDefaults()
+		printCommands()
+	}
 	flag.Parse()
 
 	// Show version if requested
@@ -91,6 +111,20 @@ func main() {
 		log.Fatalf("Failed to sanitize config: %v", err)
 	}
 
+	// Load the OCSF catalog up front so a bad mappings file fails before any network calls
+	var ocsfCatalog *ocsf.Catalog
+	if !cfg.OCSF.Disabled {
+		if cfg.OCSF.MappingsFile != "" {
+			if err := validatePath(cfg.OCSF.MappingsFile); err != nil {
+				log.Fatalf("Invalid OCSF mappings path: %v", err)
+			}
+		}
+		ocsfCatalog, err = ocsf.LoadCatalog(cfg.OCSF.MappingsFile)
+		if err != nil {
+			log.Fatalf("Failed to load OCSF catalog: %v", err)
+		}
+	}
+
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
 	defer cancel()
@@ -152,6 +186,7 @@ func main() {
 	// Build inventory
 	inventory := types.DataSourceInventory{
 		Metadata: types.InventoryMetadata{
+			SchemaVersion: types.InventorySchemaVersion,
 			Timestamp:    time.Now(),
 			Provider:     provider.Name(),
 			Version:      getVersion(),
@@ -161,6 +196,11 @@ func main() {
 		DataSources: dataViews,
 	}
 
+	// Classify sources into OCSF categories and classes
+	if ocsfCatalog != nil {
+		ocsfCatalog.Apply(&inventory)
+	}
+
 	// Marshal to JSON with pretty printing
 	jsonOutput, err := json.MarshalIndent(inventory, "", "  ")
 	if err != nil {
@@ -178,6 +218,7 @@ func main() {
 	// Print summary (only if we have data)
 	if len(dataViews) > 0 && *verbose {
 		printSummary(dataViews)
+		printOCSFSummary(inventory.OCSFSummary)
 	}
 }
 
@@ -193,12 +234,27 @@ func printSummary(dataSources []types.DataSource) {
 	}
 }
 
+func printOCSFSummary(summary *types.OCSFSummary) {
+	if summary == nil {
+		return
+	}
+
+	fmt.Printf("\n🧭 OCSF %s classification (%d classified, %d unclassified):\n",
+		summary.SchemaVersion, summary.ClassifiedCount, summary.UnclassifiedCount)
+	for _, category := range summary.Categories {
+		fmt.Printf("  [%d] %s: %d\n", category.CategoryUID, category.CategoryName, category.SourceCount)
+		for _, class := range category.Classes {
+			fmt.Printf("    [%d] %s: %d\n", class.ClassUID, class.ClassName, class.SourceCount)
+		}
+	}
+}
+
 // getVersion returns the application version
 func getVersion() string {
 	return version
 }
 
-// validatePath ensures the config path is safe and accessible
+// validatePath ensures an input path (config or rules file) is safe and accessible
 func validatePath(path string) error {
 	// Sanitize path
 	cleanPath := filepath.Clean(path)
@@ -215,7 +271,7 @@ func validatePath(path string) error {
 	
 	// Check if file exists and is readable
 	if _, err := os.Stat(cleanPath); os.IsNotExist(err) {
-		return fmt.Errorf("config file does not exist: %s", cleanPath)
+		return fmt.Errorf("file does not exist: %s", cleanPath)
 	}
 	
 	return nil
@@ -0,0 +1,113 @@
+// Package pluginsdk is the Go SDK for logfiend provider plugins: external
+// executables that logfiend launches and talks to over stdin and stdout.
+//
+// The protocol is JSON-RPC 2.0 with one message per line. Each session
+// starts with an Initialize request carrying the ProviderConfig, followed by
+// one or more of Name, GetCapabilities, ValidateConnection and
+// FetchDataViews. logfiend closes stdin when it is done; the plugin should
+// then exit. Plugins must not write anything but responses to stdout, so
+// Serve redirects os.Stdout to stderr for the rest of the process.
+//
+// A plugin implements Provider, the same interface as the built-in providers,
+// and hands a factory to Serve:
+//
+//	func main() {
+//		pluginsdk.Serve(func(config pluginsdk.ProviderConfig) (pluginsdk.Provider, error) {
+//			return &myProvider{config: config}, nil
+//		})
+//	}
+//
+// See docs/PLUGINS.md for the wire format.
+package pluginsdk
+
+import (
+	"encoding/json"
+
+	"github.com/logfiend/internal/types"
+)
+
+// ProtocolVersion is the plugin protocol version spoken by this SDK. It is
+// bumped for any change an older host or plugin would misread.
+const ProtocolVersion = 1
+
+// Methods a plugin answers
+const (
+	MethodInitialize         = "Initialize"
+	MethodName               = "Name"
+	MethodGetCapabilities    = "GetCapabilities"
+	MethodValidateConnection = "ValidateConnection"
+	MethodFetchDataViews     = "FetchDataViews"
+)
+
+// JSON-RPC error codes
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+	// CodeProviderError reports an error returned by the Provider itself
+	CodeProviderError = -32000
+)
+
+// Types shared with the built-in providers
+type (
+	Provider             = types.Provider
+	ProviderConfig       = types.ProviderConfig
+	ProviderCapabilities = types.ProviderCapabilities
+	AuthConfig           = types.AuthConfig
+	TLSConfig            = types.TLSConfig
+	DataSource           = types.DataSource
+	NormalizedFields     = types.NormalizedFields
+	Relation             = types.Relation
+)
+
+// NewNormalizedFields returns an empty NormalizedFields stamped with the
+// current version
+func NewNormalizedFields() *NormalizedFields {
+	return types.NewNormalizedFields()
+}
+
+// Request is a JSON-RPC request sent by logfiend
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int64           `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is a plugin's answer to a Request
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int64           `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error object
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// InitializeParams opens a session. Config is the provider block of
+// logfiend's configuration, credentials included; it is only ever sent over
+// stdin, never in arguments or the environment.
+type InitializeParams struct {
+	ProtocolVersion int            `json:"protocol_version"`
+	Config          ProviderConfig `json:"config"`
+}
+
+// InitializeResult confirms the protocol version the plugin speaks
+type InitializeResult struct {
+	ProtocolVersion int `json:"protocol_version"`
+}
+
+// FetchDataViewsResult carries the inventoried data sources
+type FetchDataViewsResult struct {
+	DataSources []DataSource `json:"data_sources"`
+}
@@ -0,0 +1,123 @@
+package pluginsdk
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Factory creates the plugin's Provider from the configuration sent in
+// Initialize
+type Factory func(config ProviderConfig) (Provider, error)
+
+// Serve answers logfiend's requests on stdin and stdout until stdin is
+// closed, then exits. Errors that end the session are written to stderr and
+// exit with status 1.
+func Serve(factory Factory) {
+	out := os.Stdout
+	// Stray prints would corrupt the protocol stream
+	os.Stdout = os.Stderr
+
+	if err := ServeIO(context.Background(), factory, os.Stdin, out); err != nil {
+		fmt.Fprintf(os.Stderr, "plugin: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// ServeIO runs a session over the given streams. It returns nil when in
+// reaches EOF.
+func ServeIO(ctx context.Context, factory Factory, in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	encoder := json.NewEncoder(out)
+	var provider Provider
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) == 0 && err == io.EOF {
+			return nil
+		}
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("failed to read request: %w", err)
+		}
+
+		var request Request
+		if err := json.Unmarshal(line, &request); err != nil {
+			if err := encoder.Encode(errorResponse(0, CodeParseError, "invalid JSON: "+err.Error())); err != nil {
+				return err
+			}
+			continue
+		}
+
+		response := handle(ctx, factory, &provider, request)
+		if err := encoder.Encode(response); err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+	}
+}
+
+// handle answers one request; provider is set by Initialize
+func handle(ctx context.Context, factory Factory, provider *Provider, request Request) Response {
+	if request.JSONRPC != "2.0" {
+		return errorResponse(request.ID, CodeInvalidRequest, `jsonrpc must be "2.0"`)
+	}
+
+	if request.Method == MethodInitialize {
+		var params InitializeParams
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			return errorResponse(request.ID, CodeInvalidParams, "invalid Initialize params: "+err.Error())
+		}
+		if params.ProtocolVersion != ProtocolVersion {
+			return errorResponse(request.ID, CodeInvalidParams,
+				fmt.Sprintf("unsupported protocol version %d (plugin speaks %d)", params.ProtocolVersion, ProtocolVersion))
+		}
+		created, err := factory(params.Config)
+		if err != nil {
+			return errorResponse(request.ID, CodeProviderError, err.Error())
+		}
+		*provider = created
+		return resultResponse(request.ID, InitializeResult{ProtocolVersion: ProtocolVersion})
+	}
+
+	if *provider == nil {
+		return errorResponse(request.ID, CodeInvalidRequest, "Initialize must be the first request")
+	}
+	p := *provider
+
+	switch request.Method {
+	case MethodName:
+		return resultResponse(request.ID, p.Name())
+	case MethodGetCapabilities:
+		return resultResponse(request.ID, p.GetCapabilities())
+	case MethodValidateConnection:
+		if err := p.ValidateConnection(ctx); err != nil {
+			return errorResponse(request.ID, CodeProviderError, err.Error())
+		}
+		return resultResponse(request.ID, struct{}{})
+	case MethodFetchDataViews:
+		dataSources, err := p.FetchDataViews(ctx)
+		if err != nil {
+			return errorResponse(request.ID, CodeProviderError, err.Error())
+		}
+		if dataSources == nil {
+			dataSources = []DataSource{}
+		}
+		return resultResponse(request.ID, FetchDataViewsResult{DataSources: dataSources})
+	}
+	return errorResponse(request.ID, CodeMethodNotFound, "unknown method "+request.Method)
+}
+
+func resultResponse(id int64, result interface{}) Response {
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return errorResponse(id, CodeInternalError, "failed to encode result: "+err.Error())
+	}
+	return Response{JSONRPC: "2.0", ID: id, Result: encoded}
+}
+
+func errorResponse(id int64, code int, message string) Response {
+	return Response{JSONRPC: "2.0", ID: id, Error: &Error{Code: code, Message: message}}
+}
@@ -0,0 +1,86 @@
+package pluginsdk
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type stubProvider struct {
+	config ProviderConfig
+}
+
+func (s *stubProvider) Name() string { return "stub" }
+
+func (s *stubProvider) FetchDataViews(ctx context.Context) ([]DataSource, error) {
+	if s.config.Endpoint == "" {
+		return nil, errors.New("no endpoint")
+	}
+	return []DataSource{{ID: "a", Type: "stub-source", Normalized: NewNormalizedFields()}}, nil
+}
+
+func (s *stubProvider) ValidateConnection(ctx context.Context) error { return nil }
+
+func (s *stubProvider) GetCapabilities() ProviderCapabilities {
+	return ProviderCapabilities{SupportedDataTypes: []string{"stub-source"}}
+}
+
+func TestServeIO(t *testing.T) {
+	requests := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"Name"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"Initialize","params":{"protocol_version":99,"config":{}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"Initialize","params":{"protocol_version":1,"config":{"type":"plugin","endpoint":"https://siem.example.com","auth":{"type":"bearer","token":"t"}}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"Name"}`,
+		`{"jsonrpc":"2.0","id":5,"method":"FetchDataViews"}`,
+		`{"jsonrpc":"2.0","id":6,"method":"Shutdown"}`,
+		`not json`,
+		`{"jsonrpc":"2.0","id":7,"method":"ValidateConnection"}`,
+	}, "\n") + "\n"
+
+	var out strings.Builder
+	factory := func(config ProviderConfig) (Provider, error) {
+		if config.Auth == nil || config.Auth.Token != "t" {
+			t.Errorf("config not passed to the factory: %+v", config)
+		}
+		return &stubProvider{config: config}, nil
+	}
+	if err := ServeIO(context.Background(), factory, strings.NewReader(requests), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	var responses []Response
+	scanner := bufio.NewScanner(strings.NewReader(out.String()))
+	for scanner.Scan() {
+		var response Response
+		if err := json.Unmarshal(scanner.Bytes(), &response); err != nil {
+			t.Fatalf("invalid response line %q: %v", scanner.Text(), err)
+		}
+		responses = append(responses, response)
+	}
+	if len(responses) != 8 {
+		t.Fatalf("expected 8 responses, got %d:\n%s", len(responses), out.String())
+	}
+
+	wantErrors := map[int]int{0: CodeInvalidRequest, 1: CodeInvalidParams, 5: CodeMethodNotFound, 6: CodeParseError}
+	for i, response := range responses {
+		code, wantError := wantErrors[i]
+		switch {
+		case wantError && (response.Error == nil || response.Error.Code != code):
+			t.Errorf("response %d: expected error code %d, got %+v", i, code, response.Error)
+		case !wantError && response.Error != nil:
+			t.Errorf("response %d: unexpected error %+v", i, response.Error)
+		}
+	}
+
+	var name string
+	if err := json.Unmarshal(responses[3].Result, &name); err != nil || name != "stub" {
+		t.Errorf("unexpected Name result %s", responses[3].Result)
+	}
+	var result FetchDataViewsResult
+	if err := json.Unmarshal(responses[4].Result, &result); err != nil || len(result.DataSources) != 1 || result.DataSources[0].ID != "a" {
+		t.Errorf("unexpected FetchDataViews result %s", responses[4].Result)
+	}
+}